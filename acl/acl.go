@@ -0,0 +1,284 @@
+// Package acl gives semantics to the owner, read and write fields carried by
+// every document in the model.
+//
+// The owner is a principal ID. Read and write lists hold entries of the form
+//
+//	alice          principal ID (also written user:alice)
+//	group:ops      any member of group ops
+//	role:analyst   any principal holding role analyst, directly or inherited
+//	*              everyone
+//
+// A trailing '*' in the name part matches by prefix, so "group:team-*"
+// grants access to every group starting with "team-". Write access implies
+// read access, and the owner may read, write and delete.
+package acl
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Entry prefixes recognized in read and write lists.
+const (
+	UserPrefix  = "user:"
+	GroupPrefix = "group:"
+	RolePrefix  = "role:"
+	Everyone    = "*"
+)
+
+// Principal is the subject access is evaluated for.
+type Principal struct {
+	ID     string
+	Groups []string
+	Roles  []string
+}
+
+// Evaluator checks access for principals. Its zero value is not usable;
+// create one with New.
+type Evaluator struct {
+	inherits       map[string][]string
+	superusers     map[string]bool
+	relationPolicy RelationPolicy
+}
+
+// Option configures an Evaluator.
+type Option func(*Evaluator)
+
+// WithRoleInheritance declares that holders of role also hold every role in
+// inherits, transitively.
+func WithRoleInheritance(role string, inherits ...string) Option {
+	return func(e *Evaluator) {
+		e.inherits[role] = append(e.inherits[role], inherits...)
+	}
+}
+
+// WithSuperusers grants holders of any of roles full access to every
+// document regardless of its lists.
+func WithSuperusers(roles ...string) Option {
+	return func(e *Evaluator) {
+		for _, r := range roles {
+			e.superusers[r] = true
+		}
+	}
+}
+
+// New returns an Evaluator configured by opts.
+func New(opts ...Option) *Evaluator {
+	e := &Evaluator{
+		inherits:   map[string][]string{},
+		superusers: map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Default is the evaluator used by the package-level functions. It has no
+// role inheritance, no superusers and the Intersection relation policy.
+var Default = New()
+
+// CanRead reports whether p may read m using the Default evaluator.
+func CanRead(p Principal, m proto.Message) bool { return Default.CanRead(p, m) }
+
+// CanWrite reports whether p may modify m using the Default evaluator.
+func CanWrite(p Principal, m proto.Message) bool { return Default.CanWrite(p, m) }
+
+// CanDelete reports whether p may delete m using the Default evaluator.
+func CanDelete(p Principal, m proto.Message) bool { return Default.CanDelete(p, m) }
+
+// CanRead reports whether p may read m. m is any message with owner, read
+// and write fields, or a model.Entity wrapping one. Messages without those
+// fields are not readable.
+func (e *Evaluator) CanRead(p Principal, m proto.Message) bool {
+	return e.readable(e.subject(p), m)
+}
+
+// CanWrite reports whether p may modify m.
+func (e *Evaluator) CanWrite(p Principal, m proto.Message) bool {
+	doc, ok := fieldsOf(m)
+	if !ok {
+		return false
+	}
+	s := e.subject(p)
+	return s.super || s.owns(doc) || s.matchesAny(doc.write)
+}
+
+// CanDelete reports whether p may delete m. Only the owner and superusers
+// may delete.
+func (e *Evaluator) CanDelete(p Principal, m proto.Message) bool {
+	doc, ok := fieldsOf(m)
+	if !ok {
+		return false
+	}
+	s := e.subject(p)
+	return s.super || s.owns(doc)
+}
+
+// Filter returns the elements of ms that p may read, preserving order. A
+// nil evaluator means Default. It works for slices of any document type as
+// well as []*model.Entity.
+func Filter[T proto.Message](e *Evaluator, p Principal, ms []T) []T {
+	if e == nil {
+		e = Default
+	}
+	out := make([]T, 0, len(ms))
+	for _, m := range ms {
+		if e.CanRead(p, m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (e *Evaluator) readable(s subject, m proto.Message) bool {
+	doc, ok := fieldsOf(m)
+	if !ok {
+		return false
+	}
+	return s.super || s.owns(doc) || s.matchesAny(doc.read) || s.matchesAny(doc.write)
+}
+
+// subject is a principal with its roles expanded.
+type subject struct {
+	Principal
+	roles map[string]bool
+	super bool
+}
+
+func (e *Evaluator) subject(p Principal) subject {
+	s := subject{Principal: p, roles: map[string]bool{}}
+	var expand func(string)
+	expand = func(r string) {
+		if s.roles[r] {
+			return
+		}
+		s.roles[r] = true
+		for _, parent := range e.inherits[r] {
+			expand(parent)
+		}
+	}
+	for _, r := range p.Roles {
+		expand(r)
+	}
+	for r := range s.roles {
+		if e.superusers[r] {
+			s.super = true
+		}
+	}
+	return s
+}
+
+func (s subject) owns(doc document) bool {
+	return s.ID != "" && doc.owner == s.ID
+}
+
+func (s subject) matchesAny(entries []string) bool {
+	for _, entry := range entries {
+		if s.matches(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s subject) matches(entry string) bool {
+	switch {
+	case entry == Everyone:
+		return true
+	case strings.HasPrefix(entry, GroupPrefix):
+		pattern := strings.TrimPrefix(entry, GroupPrefix)
+		for _, g := range s.Groups {
+			if match(pattern, g) {
+				return true
+			}
+		}
+	case strings.HasPrefix(entry, RolePrefix):
+		pattern := strings.TrimPrefix(entry, RolePrefix)
+		for r := range s.roles {
+			if match(pattern, r) {
+				return true
+			}
+		}
+	default:
+		return s.ID != "" && match(strings.TrimPrefix(entry, UserPrefix), s.ID)
+	}
+	return false
+}
+
+// match compares name with pattern, treating a trailing '*' as a prefix
+// wildcard.
+func match(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
+
+// document holds the access fields read from a message.
+type document struct {
+	owner string
+	read  []string
+	write []string
+}
+
+// fieldsOf reads the owner, read and write fields of m through reflection.
+// A message without them that has a single oneof, such as model.Entity, is
+// resolved to the message set in that oneof.
+func fieldsOf(m proto.Message) (document, bool) {
+	if m == nil {
+		return document{}, false
+	}
+	rm := m.ProtoReflect()
+	if !rm.IsValid() {
+		return document{}, false
+	}
+	if doc, ok := accessFields(rm); ok {
+		return doc, true
+	}
+	var oneof protoreflect.OneofDescriptor
+	oneofs := rm.Descriptor().Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		if o := oneofs.Get(i); !o.IsSynthetic() {
+			if oneof != nil {
+				return document{}, false
+			}
+			oneof = o
+		}
+	}
+	if oneof == nil {
+		return document{}, false
+	}
+	fd := rm.WhichOneof(oneof)
+	if fd == nil || fd.Message() == nil {
+		return document{}, false
+	}
+	return accessFields(rm.Get(fd).Message())
+}
+
+// accessFields reads the owner, read and write fields of rm itself.
+func accessFields(rm protoreflect.Message) (document, bool) {
+	fields := rm.Descriptor().Fields()
+	owner := fields.ByName("owner")
+	read := fields.ByName("read")
+	write := fields.ByName("write")
+	if owner == nil || read == nil || write == nil ||
+		owner.Kind() != protoreflect.StringKind || !read.IsList() || !write.IsList() {
+		return document{}, false
+	}
+	return document{
+		owner: rm.Get(owner).String(),
+		read:  stringList(rm.Get(read).List()),
+		write: stringList(rm.Get(write).List()),
+	}, true
+}
+
+func stringList(l protoreflect.List) []string {
+	out := make([]string, l.Len())
+	for i := range out {
+		out[i] = l.Get(i).String()
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package acl
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestAccess(t *testing.T) {
+	e := New(
+		WithRoleInheritance("lead", "analyst"),
+		WithRoleInheritance("analyst", "viewer"),
+		WithSuperusers("admin"),
+	)
+	alice := Principal{ID: "alice", Groups: []string{"team-red", "ops"}, Roles: []string{"lead"}}
+	bob := Principal{ID: "bob"}
+	admin := Principal{ID: "root", Roles: []string{"admin"}}
+	anonymous := Principal{}
+
+	tests := []struct {
+		name                   string
+		p                      Principal
+		m                      proto.Message
+		read, write, canDelete bool
+	}{
+		{"owner", alice, &model.Event{Owner: "alice"}, true, true, true},
+		{"not owner", bob, &model.Event{Owner: "alice"}, false, false, false},
+		{"read list", bob, &model.Event{Owner: "alice", Read: []string{"bob"}}, true, false, false},
+		{"user prefix", bob, &model.Event{Read: []string{"user:bob"}}, true, false, false},
+		{"write implies read", bob, &model.Event{Write: []string{"bob"}}, true, true, false},
+		{"group", alice, &model.Source{Read: []string{"group:ops"}}, true, false, false},
+		{"other group", bob, &model.Source{Read: []string{"group:ops"}}, false, false, false},
+		{"group wildcard", alice, &model.Source{Write: []string{"group:team-*"}}, true, true, false},
+		{"group wildcard mismatch", alice, &model.Source{Read: []string{"group:blue-*"}}, false, false, false},
+		{"role", alice, &model.Person{Read: []string{"role:lead"}}, true, false, false},
+		{"inherited role", alice, &model.Person{Write: []string{"role:viewer"}}, true, true, false},
+		{"role wildcard", alice, &model.Person{Read: []string{"role:anal*"}}, true, false, false},
+		{"user wildcard", bob, &model.Person{Read: []string{"b*"}}, true, false, false},
+		{"everyone", anonymous, &model.Website{Read: []string{"*"}}, true, false, false},
+		{"anonymous owns nothing", anonymous, &model.Website{}, false, false, false},
+		{"anonymous matches no user", anonymous, &model.Website{Read: []string{"*x"}}, false, false, false},
+		{"superuser", admin, &model.Organization{Owner: "alice"}, true, true, true},
+		{"entity", bob, &model.Entity{Entity: &model.Entity_Person{Person: &model.Person{Read: []string{"bob"}}}}, true, false, false},
+		{"empty entity", admin, &model.Entity{}, false, false, false},
+		{"no acl fields", admin, &model.LocationData{}, false, false, false},
+		{"nil", admin, nil, false, false, false},
+	}
+	for _, tt := range tests {
+		if got := e.CanRead(tt.p, tt.m); got != tt.read {
+			t.Errorf("%s: CanRead = %v, want %v", tt.name, got, tt.read)
+		}
+		if got := e.CanWrite(tt.p, tt.m); got != tt.write {
+			t.Errorf("%s: CanWrite = %v, want %v", tt.name, got, tt.write)
+		}
+		if got := e.CanDelete(tt.p, tt.m); got != tt.canDelete {
+			t.Errorf("%s: CanDelete = %v, want %v", tt.name, got, tt.canDelete)
+		}
+	}
+}
+
+func TestDefaultHasNoSuperusers(t *testing.T) {
+	admin := Principal{ID: "root", Roles: []string{"admin"}}
+	if CanRead(admin, &model.Event{Owner: "alice"}) {
+		t.Error("Default grants a role it was not configured with")
+	}
+	if !CanDelete(Principal{ID: "alice"}, &model.Event{Owner: "alice"}) {
+		t.Error("Default denies the owner")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	events := []*model.Event{
+		{Key: "a", Read: []string{"bob"}},
+		{Key: "b", Owner: "alice"},
+		{Key: "c", Write: []string{"*"}},
+	}
+	got := Filter(nil, Principal{ID: "bob"}, events)
+	if len(got) != 2 || got[0].GetKey() != "a" || got[1].GetKey() != "c" {
+		t.Errorf("Filter = %v, want events a and c", got)
+	}
+}
+
+// TestOwnFieldsBeforeOneof checks that a document with its own access
+// fields is not resolved to the message set in one of its oneofs.
+func TestOwnFieldsBeforeOneof(t *testing.T) {
+	md := envelope(t)
+	m := dynamicpb.NewMessage(md)
+	m.Set(md.Fields().ByName("owner"), protoreflect.ValueOfString("alice"))
+	inner := &model.Person{Owner: "bob"}
+	m.Set(md.Fields().ByName("person"), protoreflect.ValueOfMessage(inner.ProtoReflect()))
+
+	e := New()
+	if !e.CanDelete(Principal{ID: "alice"}, m) {
+		t.Error("owner of the envelope cannot delete it")
+	}
+	if e.CanRead(Principal{ID: "bob"}, m) {
+		t.Error("owner of the wrapped person can read the envelope")
+	}
+}
+
+// envelope builds a message type with access fields and a oneof.
+func envelope(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("acl_test/envelope.proto"),
+		Package:    proto.String("acltest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"model/v1/osint.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Envelope"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("owner"), Number: proto.Int32(1), Type: str, Label: optional},
+				{Name: proto.String("read"), Number: proto.Int32(2), Type: str, Label: repeated},
+				{Name: proto.String("write"), Number: proto.Int32(3), Type: str, Label: repeated},
+				{
+					Name: proto.String("person"), Number: proto.Int32(4), Label: optional,
+					Type:       descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName:   proto.String(".model.v1.Person"),
+					OneofIndex: proto.Int32(0),
+				},
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("payload")}},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages().Get(0)
+}
@@ -0,0 +1,128 @@
+package acl
+
+import (
+	"github.com/omnsight/omniscent-library/arango"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// RelationPolicy decides how the ACLs of a relation's endpoints affect the
+// visibility of the relation itself.
+type RelationPolicy int
+
+const (
+	// Intersection shows a relation only if the relation and both of its
+	// endpoints are readable. It is the default.
+	Intersection RelationPolicy = iota
+	// EdgeOnly uses the relation's own lists and ignores its endpoints.
+	// This may reveal the existence of hidden documents.
+	EdgeOnly
+	// EndpointDominant shows a relation whenever both endpoints are
+	// readable, ignoring the relation's own lists.
+	EndpointDominant
+)
+
+// WithRelationPolicy sets the policy used by CanReadRelation and the
+// relation filters.
+func WithRelationPolicy(policy RelationPolicy) Option {
+	return func(e *Evaluator) {
+		e.relationPolicy = policy
+	}
+}
+
+// Resolver looks up a document by its "collection/key" handle.
+type Resolver interface {
+	Resolve(handle string) (proto.Message, bool)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(handle string) (proto.Message, bool)
+
+func (f ResolverFunc) Resolve(handle string) (proto.Message, bool) { return f(handle) }
+
+// Documents is an in-memory Resolver keyed by document handle.
+type Documents map[string]proto.Message
+
+func (d Documents) Resolve(handle string) (proto.Message, bool) {
+	m, ok := d[handle]
+	return m, ok
+}
+
+// IndexDocuments builds a Documents map from ms using arango.HandleOf.
+// Messages without a resolvable handle are skipped.
+func IndexDocuments[T proto.Message](ms []T) Documents {
+	d := make(Documents, len(ms))
+	for _, m := range ms {
+		if h, err := arango.HandleOf(m); err == nil {
+			d[h.String()] = m
+		}
+	}
+	return d
+}
+
+// CanReadRelation reports whether p may see r under the evaluator's
+// relation policy. Endpoints are looked up through docs; an endpoint that
+// cannot be resolved counts as unreadable. Superusers see every relation,
+// including those whose endpoints are deleted or outside docs.
+func (e *Evaluator) CanReadRelation(p Principal, r *model.Relation, docs Resolver) bool {
+	return e.relationVisible(e.subject(p), r, docs, nil)
+}
+
+// FilterRelations returns the relations in rels that p may see, preserving
+// order. Endpoint readability is computed once per handle.
+func (e *Evaluator) FilterRelations(p Principal, rels []*model.Relation, docs Resolver) []*model.Relation {
+	s := e.subject(p)
+	seen := map[string]bool{}
+	out := make([]*model.Relation, 0, len(rels))
+	for _, r := range rels {
+		if e.relationVisible(s, r, docs, seen) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Subgraph is a set of documents and the relations between them.
+type Subgraph struct {
+	Documents []proto.Message
+	Relations []*model.Relation
+}
+
+// FilterSubgraph returns the part of g visible to p: readable documents and
+// the relations visible under the evaluator's policy, with endpoints
+// resolved among g.Documents.
+func (e *Evaluator) FilterSubgraph(p Principal, g Subgraph) Subgraph {
+	return Subgraph{
+		Documents: Filter(e, p, g.Documents),
+		Relations: e.FilterRelations(p, g.Relations, IndexDocuments(g.Documents)),
+	}
+}
+
+func (e *Evaluator) relationVisible(s subject, r *model.Relation, docs Resolver, seen map[string]bool) bool {
+	if s.super {
+		return true
+	}
+	if e.relationPolicy != EndpointDominant && !e.readable(s, r) {
+		return false
+	}
+	if e.relationPolicy == EdgeOnly {
+		return true
+	}
+	return e.endpointReadable(s, r.GetFrom(), docs, seen) && e.endpointReadable(s, r.GetTo(), docs, seen)
+}
+
+func (e *Evaluator) endpointReadable(s subject, handle string, docs Resolver, seen map[string]bool) bool {
+	if ok, cached := seen[handle]; cached {
+		return ok
+	}
+	ok := false
+	if docs != nil {
+		if m, found := docs.Resolve(handle); found {
+			ok = e.readable(s, m)
+		}
+	}
+	if seen != nil {
+		seen[handle] = ok
+	}
+	return ok
+}
@@ -0,0 +1,133 @@
+package acl
+
+import (
+	"slices"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCanReadRelation(t *testing.T) {
+	docs := IndexDocuments([]*model.Person{
+		{Key: "public", Read: []string{"*"}},
+		{Key: "hidden", Owner: "alice"},
+	})
+	open := []string{"*"}
+	bob := Principal{ID: "bob"}
+	admin := Principal{ID: "root", Roles: []string{"admin"}}
+
+	tests := []struct {
+		name     string
+		p        Principal
+		r        *model.Relation
+		policies map[RelationPolicy]bool
+	}{
+		{
+			"all readable", bob,
+			&model.Relation{From: "persons/public", To: "persons/public", Read: open},
+			map[RelationPolicy]bool{Intersection: true, EdgeOnly: true, EndpointDominant: true},
+		},
+		{
+			"hidden relation", bob,
+			&model.Relation{From: "persons/public", To: "persons/public", Owner: "alice"},
+			map[RelationPolicy]bool{Intersection: false, EdgeOnly: false, EndpointDominant: true},
+		},
+		{
+			"hidden endpoint", bob,
+			&model.Relation{From: "persons/public", To: "persons/hidden", Read: open},
+			map[RelationPolicy]bool{Intersection: false, EdgeOnly: true, EndpointDominant: false},
+		},
+		{
+			"unresolvable endpoint", bob,
+			&model.Relation{From: "persons/public", To: "persons/deleted", Read: open},
+			map[RelationPolicy]bool{Intersection: false, EdgeOnly: true, EndpointDominant: false},
+		},
+		{
+			"superuser, hidden endpoint", admin,
+			&model.Relation{From: "persons/public", To: "persons/hidden", Owner: "alice"},
+			map[RelationPolicy]bool{Intersection: true, EdgeOnly: true, EndpointDominant: true},
+		},
+		{
+			"superuser, unresolvable endpoint", admin,
+			&model.Relation{From: "persons/deleted", To: "persons/hidden", Owner: "alice"},
+			map[RelationPolicy]bool{Intersection: true, EdgeOnly: true, EndpointDominant: true},
+		},
+	}
+	for _, tt := range tests {
+		for policy, want := range tt.policies {
+			e := New(WithSuperusers("admin"), WithRelationPolicy(policy))
+			if got := e.CanReadRelation(tt.p, tt.r, docs); got != want {
+				t.Errorf("%s: CanReadRelation under policy %d = %v, want %v", tt.name, policy, got, want)
+			}
+		}
+	}
+}
+
+func TestCanReadRelationWithoutResolver(t *testing.T) {
+	r := &model.Relation{From: "persons/a", To: "persons/b", Read: []string{"*"}}
+	if New().CanReadRelation(Principal{ID: "bob"}, r, nil) {
+		t.Error("endpoints without a resolver are readable")
+	}
+	if !New(WithRelationPolicy(EdgeOnly)).CanReadRelation(Principal{ID: "bob"}, r, nil) {
+		t.Error("EdgeOnly needs a resolver")
+	}
+}
+
+func TestFilterRelationsResolvesOnce(t *testing.T) {
+	calls := map[string]int{}
+	docs := ResolverFunc(func(handle string) (proto.Message, bool) {
+		calls[handle]++
+		return &model.Event{Read: []string{"*"}}, true
+	})
+	rels := []*model.Relation{
+		{Key: "1", From: "events/a", To: "events/b", Read: []string{"*"}},
+		{Key: "2", From: "events/b", To: "events/a", Read: []string{"*"}},
+		{Key: "3", From: "events/a", To: "events/c"},
+	}
+	got := New().FilterRelations(Principal{ID: "bob"}, rels, docs)
+	if len(got) != 2 || got[0].GetKey() != "1" || got[1].GetKey() != "2" {
+		t.Errorf("FilterRelations = %v, want relations 1 and 2", got)
+	}
+	for h, n := range calls {
+		if n != 1 {
+			t.Errorf("%s resolved %d times", h, n)
+		}
+	}
+}
+
+func TestFilterSubgraph(t *testing.T) {
+	g := Subgraph{
+		Documents: []proto.Message{
+			&model.Person{Key: "a", Read: []string{"bob"}},
+			&model.Organization{Key: "b", Read: []string{"bob"}},
+			&model.Event{Key: "c", Owner: "alice"},
+		},
+		Relations: []*model.Relation{
+			{Key: "ab", From: "persons/a", To: "organizations/b", Read: []string{"*"}},
+			{Key: "ac", From: "persons/a", To: "events/c", Read: []string{"*"}},
+			{Key: "ax", From: "persons/a", To: "events/outside", Read: []string{"*"}},
+		},
+	}
+	tests := []struct {
+		name      string
+		p         Principal
+		docs      int
+		relations []string
+	}{
+		{"bob", Principal{ID: "bob"}, 2, []string{"ab"}},
+		{"alice", Principal{ID: "alice"}, 1, nil},
+		{"superuser", Principal{ID: "root", Roles: []string{"admin"}}, 3, []string{"ab", "ac", "ax"}},
+	}
+	e := New(WithSuperusers("admin"))
+	for _, tt := range tests {
+		got := e.FilterSubgraph(tt.p, g)
+		var keys []string
+		for _, r := range got.Relations {
+			keys = append(keys, r.GetKey())
+		}
+		if len(got.Documents) != tt.docs || !slices.Equal(keys, tt.relations) {
+			t.Errorf("%s: FilterSubgraph = %d documents, relations %v; want %d, %v", tt.name, len(got.Documents), keys, tt.docs, tt.relations)
+		}
+	}
+}
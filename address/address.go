@@ -0,0 +1,258 @@
+// Package address parses free-text postal addresses into the structured
+// fields of LocationData, normalizes their casing and abbreviations, and
+// derives a canonical form for comparing locations.
+//
+// Parsing is heuristic and covers the common layouts: North American and
+// Australian ("street, locality, REGION postcode, country"), British
+// ("street, locality POSTCODE, country") and continental European
+// ("street, postcode locality, country"). Components are separated by
+// commas or newlines; an address on a single line yields only what its
+// postcode and country reveal.
+package address
+
+import (
+	"strings"
+	"unicode"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/textfold"
+	"github.com/omnsight/omniscent-library/validate"
+)
+
+// Address is a parsed postal address. Empty fields were not found.
+type Address struct {
+	// Street holds the components before the locality, such as the house
+	// number, street and unit, joined by ", ".
+	Street             string
+	SubLocality        string
+	Locality           string
+	AdministrativeArea string
+	Postcode           string
+	// CountryCode is an ISO 3166-1 alpha-2 code.
+	CountryCode string
+}
+
+// Option configures Parse.
+type Option func(*parser)
+
+// WithCountry sets the ISO 3166-1 alpha-2 code of the country to assume
+// when the text names none. It selects the postcode format and regions.
+func WithCountry(code string) Option {
+	return func(p *parser) {
+		p.country = strings.ToUpper(code)
+	}
+}
+
+type parser struct {
+	country string
+}
+
+// Parse splits a free-text address into its components, normalizing
+// casing, street abbreviations, region names and postcode spacing.
+func Parse(s string, opts ...Option) Address {
+	p := &parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	var a Address
+	parts := split(s)
+	if n := len(parts); n > 0 {
+		if cc, ok := country(parts[n-1]); ok {
+			a.CountryCode = cc
+			parts = parts[:n-1]
+		}
+	}
+	cc := a.CountryCode
+	if cc == "" {
+		cc = p.country
+	}
+
+	// The postcode is in one of the last two components, at its start or
+	// end. The first component of several is the street, whose house
+	// number could pass for a postcode.
+	postcodeAt := -1
+	for i := len(parts) - 1; i >= 0 && i >= len(parts)-2 && (i > 0 || len(parts) == 1); i-- {
+		code, rest, found, ok := postcode(parts[i], cc)
+		if ok {
+			a.Postcode, parts[i], postcodeAt = code, rest, i
+			if cc == "" {
+				cc = found
+			}
+			break
+		}
+	}
+
+	// A region follows the locality, in the same component or on its own.
+	// A lone remaining component is kept as the locality.
+	if n := len(parts); n > 0 {
+		code, rcc, rest, ok := region(parts[n-1], cc, a.Postcode)
+		if ok && (rest != "" || n > 1 || postcodeAt == n-1) {
+			a.AdministrativeArea = regions[rcc][code]
+			parts[n-1] = rest
+			if cc == "" {
+				cc = rcc
+			}
+		}
+	}
+	parts = compact(parts)
+
+	if n := len(parts); n > 0 && !hasDigit(parts[n-1]) {
+		a.Locality = recase(parts[n-1])
+		parts = parts[:n-1]
+	}
+	if n := len(parts); n > 1 && !hasDigit(parts[n-1]) {
+		a.SubLocality = recase(parts[n-1])
+		parts = parts[:n-1]
+	}
+	a.Street = Normalize(strings.Join(parts, ", "))
+	a.CountryCode = cc
+	return a
+}
+
+// split breaks s into trimmed, non-empty components at commas, semicolons
+// and line breaks.
+func split(s string) []string {
+	return compact(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '\r'
+	}))
+}
+
+// compact trims the parts and drops empty ones.
+func compact(parts []string) []string {
+	out := parts[:0]
+	for _, s := range parts {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func hasDigit(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
+
+// country returns the code of a component naming a country, by name,
+// alias or ISO code. Two-letter codes must be upper case and must not
+// also be a region code.
+func country(s string) (string, bool) {
+	if cc, ok := countries[textfold.Fold(s)]; ok {
+		return cc, true
+	}
+	if len(s) == 2 && validate.IsCountryCode(s) && !regionCodes[s] {
+		return s, true
+	}
+	return "", false
+}
+
+// postcode finds a postcode at the start or end of s, in the format of
+// country cc or, when cc is empty, of any country with a distinctive
+// format or as a plain number. It returns the canonical postcode, s
+// without it, and the country the format identifies.
+func postcode(s, cc string) (code, rest, found string, ok bool) {
+	var formats []string
+	switch f, known := postcodes[cc]; {
+	case known:
+		if code, rest, ok := f.find(s); ok {
+			return code, rest, cc, true
+		}
+		return "", s, "", false
+	case cc == "":
+		formats = distinctive
+	}
+	for _, c := range formats {
+		if code, rest, ok := postcodes[c].find(s); ok {
+			return code, rest, c, true
+		}
+	}
+	if code, rest, ok := anyDigits.find(s); ok {
+		return code, rest, "", true
+	}
+	return "", s, "", false
+}
+
+// find returns the last match of f at the start or end of s.
+func (f postcodeFormat) find(s string) (code, rest string, ok bool) {
+	all := f.re.FindAllStringSubmatchIndex(s, -1)
+	for i := len(all) - 1; i >= 0; i-- {
+		loc := all[i]
+		if loc[0] != 0 && loc[1] != len(s) {
+			continue
+		}
+		m := make([]string, len(loc)/2)
+		for j := range m {
+			if loc[2*j] >= 0 {
+				m[j] = s[loc[2*j]:loc[2*j+1]]
+			}
+		}
+		return f.format(m), strings.TrimSpace(s[:loc[0]] + " " + s[loc[1]:]), true
+	}
+	return "", s, false
+}
+
+// region finds a region code or name at the end of s. Without a country,
+// the postcode length picks between the United States (five digits) and
+// Australia (four), whose region codes overlap.
+func region(s, cc, postcode string) (code, country, rest string, ok bool) {
+	candidates := []string{cc}
+	if cc == "" {
+		switch len(postcode) {
+		case 4:
+			candidates = []string{"AU"}
+		case 5, 10:
+			candidates = []string{"US"}
+		default:
+			candidates = []string{"US", "CA", "AU"}
+		}
+	}
+	words := strings.Fields(s)
+	for _, c := range candidates {
+		rs := regions[c]
+		if rs == nil || len(words) == 0 {
+			continue
+		}
+		// Codes such as "OR" and "IN" are also words, so a code must be
+		// written in upper case unless it stands alone.
+		last := strings.TrimSuffix(words[len(words)-1], ".")
+		if up := strings.ToUpper(last); rs[up] != "" && (up == last || len(words) == 1) {
+			return up, c, strings.Join(words[:len(words)-1], " "), true
+		}
+		for n := min(len(words), 4); n > 0; n-- {
+			if code := regionNames[c][textfold.Fold(strings.Join(words[len(words)-n:], " "))]; code != "" {
+				return code, c, strings.Join(words[:len(words)-n], " "), true
+			}
+		}
+	}
+	return "", "", s, false
+}
+
+// Apply sets the fields of l that are empty from a and reports whether it
+// changed l. The Address field itself is left alone.
+func (a Address) Apply(l *model.LocationData) bool {
+	changed := false
+	set := func(dst *string, v string) {
+		if *dst == "" && v != "" {
+			*dst, changed = v, true
+		}
+	}
+	set(&l.SubLocality, a.SubLocality)
+	set(&l.Locality, a.Locality)
+	set(&l.AdministrativeArea, a.AdministrativeArea)
+	set(&l.CountryCode, a.CountryCode)
+	if geo.PostalCode(l) == "" && a.Postcode != "" {
+		geo.SetPostalCode(l, a.Postcode)
+		changed = true
+	}
+	return changed
+}
+
+// Fill parses the Address field of l, assuming its CountryCode if set,
+// and fills the empty structured fields from it. It reports whether any
+// field was set.
+func Fill(l *model.LocationData) bool {
+	if l.GetAddress() == "" {
+		return false
+	}
+	return Parse(l.GetAddress(), WithCountry(l.GetCountryCode())).Apply(l)
+}
@@ -0,0 +1,135 @@
+package address
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		country string
+		want    Address
+	}{
+		{
+			"1600 Pennsylvania Ave NW, Washington, DC 20500, USA", "",
+			Address{Street: "1600 Pennsylvania Avenue Northwest", Locality: "Washington", AdministrativeArea: "District of Columbia", Postcode: "20500", CountryCode: "US"},
+		},
+		{
+			"350 fifth avenue, new york, ny 10118", "",
+			Address{Street: "350 Fifth Avenue", Locality: "New York", AdministrativeArea: "New York", Postcode: "10118", CountryCode: "US"},
+		},
+		{
+			"1 Infinite Loop, Cupertino, California 95014-2083", "",
+			Address{Street: "1 Infinite Loop", Locality: "Cupertino", AdministrativeArea: "California", Postcode: "95014-2083", CountryCode: "US"},
+		},
+		{
+			"Sydney Opera House, Bennelong Point, Sydney NSW 2000, Australia", "",
+			Address{Street: "Sydney Opera House", SubLocality: "Bennelong Point", Locality: "Sydney", AdministrativeArea: "New South Wales", Postcode: "2000", CountryCode: "AU"},
+		},
+		{
+			"10 Downing St, London SW1A 2AA, United Kingdom", "",
+			Address{Street: "10 Downing Street", Locality: "London", Postcode: "SW1A 2AA", CountryCode: "GB"},
+		},
+		{
+			"221 baker st, london, nw1 6xe", "",
+			Address{Street: "221 Baker Street", Locality: "London", Postcode: "NW1 6XE", CountryCode: "GB"},
+		},
+		{
+			"24 Sussex Drive, Ottawa, ON K1M 1M4, Canada", "",
+			Address{Street: "24 Sussex Drive", Locality: "Ottawa", AdministrativeArea: "Ontario", Postcode: "K1M 1M4", CountryCode: "CA"},
+		},
+		{
+			"Damrak 1, 1012lg Amsterdam, Nederland", "",
+			Address{Street: "Damrak 1", Locality: "Amsterdam", Postcode: "1012 LG", CountryCode: "NL"},
+		},
+		{
+			"Unter den Linden 77, 10117 Berlin, Germany", "",
+			Address{Street: "Unter den Linden 77", Locality: "Berlin", Postcode: "10117", CountryCode: "DE"},
+		},
+		{
+			"Hauptstr. 5, 80331 München", "de",
+			Address{Street: "Hauptstraße 5", Locality: "München", Postcode: "80331", CountryCode: "DE"},
+		},
+		{
+			"5 Avenue Anatole France, 75007 Paris, France", "",
+			Address{Street: "5 Avenue Anatole France", Locality: "Paris", Postcode: "75007", CountryCode: "FR"},
+		},
+		{
+			"1-1 Chiyoda, Chiyoda-ku, Tokyo 1008111, Japan", "",
+			Address{Street: "1-1 Chiyoda", SubLocality: "Chiyoda-ku", Locality: "Tokyo", Postcode: "100-8111", CountryCode: "JP"},
+		},
+		{
+			"Portland, OR 97201", "",
+			Address{Locality: "Portland", AdministrativeArea: "Oregon", Postcode: "97201", CountryCode: "US"},
+		},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in, WithCountry(tt.country)); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"123 MAIN ST APT 4", "123 Main Street Apartment 4"},
+		{"st james st", "St James Street"},
+		{"42 N Elm Rd", "42 North Elm Road"},
+		{"Rue Ste Catherine", "Rue Ste Catherine"},
+		{"rue de la paix", "Rue de la Paix"},
+		{"Goethestr. 12", "Goethestraße 12"},
+		{"5th ave, ste 200", "5th Avenue, Suite 200"},
+		{" , McDonald Rd", "McDonald Road"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalEqual(t *testing.T) {
+	tests := []struct {
+		a, b *model.LocationData
+	}{
+		{
+			&model.LocationData{Address: "350 Fifth Avenue, New York, NY 10118, USA"},
+			&model.LocationData{Address: "350 5th Ave", Locality: "new york", AdministrativeArea: "New York", CountryCode: "US", Postcode: "10118"},
+		},
+		{
+			&model.LocationData{Address: "Hauptstr. 5, 80331 München, Germany"},
+			&model.LocationData{Address: "Hauptstrasse 5", Locality: "MÜNCHEN", CountryCode: "DE", PostalCode: 80331},
+		},
+		{
+			&model.LocationData{Address: "10 Downing St, London SW1A2AA, UK"},
+			&model.LocationData{Address: "10 DOWNING STREET, LONDON, SW1A 2AA", CountryCode: "gb"},
+		},
+	}
+	for _, tt := range tests {
+		if a, b := Canonical(tt.a), Canonical(tt.b); a != b {
+			t.Errorf("Canonical(%v) = %q, Canonical(%v) = %q, want equal", tt.a, a, tt.b, b)
+		}
+	}
+	a := Canonical(&model.LocationData{Address: "1 Main St, Springfield, IL 62701"})
+	b := Canonical(&model.LocationData{Address: "1 Main St, Springfield, MA 01103"})
+	if a == b {
+		t.Errorf("Canonical of different places = %q", a)
+	}
+}
+
+func TestFill(t *testing.T) {
+	l := &model.LocationData{Address: "24 Sussex Drive, Ottawa, ON K1M 1M4", CountryCode: "CA", Locality: "Ottawa (City)"}
+	if !Fill(l) {
+		t.Fatal("Fill reported no change")
+	}
+	if l.GetLocality() != "Ottawa (City)" || l.GetAdministrativeArea() != "Ontario" || l.GetPostcode() != "K1M 1M4" {
+		t.Errorf("Fill = %v", l)
+	}
+	if Fill(l) {
+		t.Error("second Fill reported a change")
+	}
+}
@@ -0,0 +1,185 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/textfold"
+)
+
+// streetTypes expands English street-type abbreviations. They are only
+// expanded at the end of a street, so "St James St" keeps its saint.
+var streetTypes = map[string]string{
+	"st": "Street", "rd": "Road", "ave": "Avenue", "av": "Avenue",
+	"blvd": "Boulevard", "bd": "Boulevard", "dr": "Drive", "ln": "Lane", "ct": "Court",
+	"pl": "Place", "sq": "Square", "hwy": "Highway", "pkwy": "Parkway", "ter": "Terrace",
+	"cres": "Crescent", "cir": "Circle", "trl": "Trail", "pk": "Park", "gdns": "Gardens",
+}
+
+// directions expands compass point abbreviations.
+var directions = map[string]string{
+	"n": "North", "s": "South", "e": "East", "w": "West",
+	"ne": "Northeast", "nw": "Northwest", "se": "Southeast", "sw": "Southwest",
+}
+
+// units expands unit designators when followed by a number, so "Ste" in
+// "Rue Ste Catherine" stays.
+var units = map[string]string{
+	"apt": "Apartment", "ste": "Suite", "fl": "Floor", "bldg": "Building", "rm": "Room",
+}
+
+// particles stay lower case inside title-cased names.
+var particles = map[string]bool{
+	"of": true, "and": true, "de": true, "del": true, "della": true, "di": true, "da": true,
+	"du": true, "des": true, "la": true, "le": true, "van": true, "von": true, "der": true,
+	"den": true, "y": true,
+}
+
+// Normalize tidies one or more comma-separated address lines: single-case
+// text is title-cased, English street-type, direction and unit
+// abbreviations are spelled out, and German "-str." becomes "-straße".
+// Mixed-case words are otherwise left as written.
+func Normalize(s string) string {
+	var out []string
+	for _, line := range strings.Split(s, ",") {
+		if line = normalizeLine(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func normalizeLine(s string) string {
+	words := strings.Fields(s)
+	retitle := singleCase(s)
+	for i, w := range words {
+		if retitle {
+			w = titleWord(w, i)
+		}
+		key := strings.ToLower(strings.TrimSuffix(w, "."))
+		prev := ""
+		if i > 0 {
+			prev = strings.ToLower(strings.TrimSuffix(words[i-1], "."))
+		}
+		switch {
+		case streetTypes[key] != "" && i > 0 && trailing(words[i+1:]):
+			w = streetTypes[key]
+		case directions[key] != "" && len(words) > 1 && units[prev] == "" && prev != "unit" && prev != "flat":
+			w = directions[key]
+		case units[key] != "" && i+1 < len(words) && numbered(words[i+1]):
+			w = units[key]
+		case strings.HasSuffix(key, "str") && strings.HasSuffix(w, "."):
+			w = w[:len(w)-len("str.")] + "straße"
+		}
+		words[i] = w
+	}
+	return strings.Join(words, " ")
+}
+
+// trailing reports whether words can follow a street type: directions,
+// unit designators and numbers.
+func trailing(words []string) bool {
+	for _, w := range words {
+		key := strings.ToLower(strings.TrimSuffix(w, "."))
+		if directions[key] == "" && units[key] == "" && key != "unit" && key != "flat" && !numbered(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// numbered reports whether w is a number such as "4", "12B" or "#3".
+func numbered(w string) bool {
+	r, _ := utf8.DecodeRuneInString(w)
+	return r == '#' || unicode.IsDigit(r)
+}
+
+// singleCase reports whether the letters of s are all upper case or all
+// lower case.
+func singleCase(s string) bool {
+	return strings.IndexFunc(s, unicode.IsUpper) < 0 || strings.IndexFunc(s, unicode.IsLower) < 0
+}
+
+// recase title-cases s if it is single-case.
+func recase(s string) string {
+	if !singleCase(s) {
+		return s
+	}
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = titleWord(w, i)
+	}
+	return strings.Join(words, " ")
+}
+
+// titleWord title-cases the i-th word of a name. Particles after the first
+// word stay lower case, ordinals such as "5th" keep a lower-case suffix
+// and other words starting with a digit, such as "221B", are upper-cased.
+func titleWord(w string, i int) string {
+	lower := strings.ToLower(w)
+	if i > 0 && particles[lower] {
+		return lower
+	}
+	parts := strings.Split(lower, "-")
+	for j, p := range parts {
+		r, size := utf8.DecodeRuneInString(p)
+		switch {
+		case unicode.IsDigit(r):
+			if !strings.HasSuffix(p, "st") && !strings.HasSuffix(p, "nd") && !strings.HasSuffix(p, "rd") && !strings.HasSuffix(p, "th") {
+				parts[j] = strings.ToUpper(p)
+			}
+		case size > 0:
+			parts[j] = string(unicode.ToTitle(r)) + p[size:]
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// Canonical returns a string identifying the place l describes, equal for
+// two locations written differently: country, region, locality, postcode
+// and street, lower-cased with diacritics and punctuation folded, regions
+// reduced to their codes, abbreviations spelled out and ordinals written
+// as numerals. Components missing from the structured fields are parsed
+// from the Address field. Sub-localities and sub-administrative areas are
+// left out, as they are often omitted.
+func Canonical(l *model.LocationData) string {
+	a := Parse(l.GetAddress(), WithCountry(l.GetCountryCode()))
+	pick := func(v, parsed string) string {
+		if v != "" {
+			return v
+		}
+		return parsed
+	}
+	cc := strings.ToUpper(pick(l.GetCountryCode(), a.CountryCode))
+	area := textfold.Fold(pick(l.GetAdministrativeArea(), a.AdministrativeArea))
+	if code := regionNames[cc][area]; code != "" {
+		area = strings.ToLower(code)
+	}
+	return strings.Join([]string{
+		strings.ToLower(cc),
+		area,
+		textfold.Fold(pick(l.GetLocality(), a.Locality)),
+		strings.ReplaceAll(textfold.Fold(pick(geo.PostalCode(l), a.Postcode)), " ", ""),
+		canonicalStreet(a.Street),
+	}, "|")
+}
+
+// ordinals maps spelled-out ordinals to numerals, so "Fifth Avenue" and
+// "5th Avenue" compare equal.
+var ordinals = map[string]string{
+	"first": "1st", "second": "2nd", "third": "3rd", "fourth": "4th", "fifth": "5th",
+	"sixth": "6th", "seventh": "7th", "eighth": "8th", "ninth": "9th", "tenth": "10th",
+}
+
+func canonicalStreet(s string) string {
+	words := strings.Fields(textfold.Fold(s))
+	for i, w := range words {
+		if n := ordinals[w]; n != "" {
+			words[i] = n
+		}
+	}
+	return strings.Join(words, " ")
+}
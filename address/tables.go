@@ -0,0 +1,154 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/internal/textfold"
+)
+
+// countries maps folded country names and common aliases to ISO 3166-1
+// alpha-2 codes. Bare two-letter codes are recognized separately.
+var countries = map[string]string{
+	"afghanistan": "AF", "albania": "AL", "algeria": "DZ", "argentina": "AR",
+	"armenia": "AM", "australia": "AU", "austria": "AT", "osterreich": "AT",
+	"azerbaijan": "AZ", "bangladesh": "BD", "belarus": "BY", "belgium": "BE",
+	"belgique": "BE", "belgie": "BE", "bolivia": "BO", "bosnia and herzegovina": "BA",
+	"brazil": "BR", "brasil": "BR", "bulgaria": "BG", "cambodia": "KH",
+	"canada": "CA", "chile": "CL", "china": "CN", "people s republic of china": "CN",
+	"colombia": "CO", "costa rica": "CR", "croatia": "HR", "hrvatska": "HR",
+	"cuba": "CU", "cyprus": "CY", "czech republic": "CZ", "czechia": "CZ",
+	"denmark": "DK", "danmark": "DK", "dominican republic": "DO", "ecuador": "EC",
+	"egypt": "EG", "el salvador": "SV", "estonia": "EE", "ethiopia": "ET",
+	"finland": "FI", "suomi": "FI", "france": "FR",
+	"germany": "DE", "deutschland": "DE", "ghana": "GH", "greece": "GR",
+	"guatemala": "GT", "honduras": "HN", "hong kong": "HK", "hungary": "HU",
+	"magyarorszag": "HU", "iceland": "IS", "india": "IN", "indonesia": "ID",
+	"iran": "IR", "iraq": "IQ", "ireland": "IE", "israel": "IL",
+	"italy": "IT", "italia": "IT", "jamaica": "JM", "japan": "JP",
+	"nippon": "JP", "jordan": "JO", "kazakhstan": "KZ", "kenya": "KE",
+	"south korea": "KR", "korea": "KR", "republic of korea": "KR", "north korea": "KP",
+	"kuwait": "KW", "latvia": "LV", "lebanon": "LB", "libya": "LY",
+	"lithuania": "LT", "luxembourg": "LU", "malaysia": "MY", "mexico": "MX",
+	"moldova": "MD", "mongolia": "MN", "montenegro": "ME", "morocco": "MA",
+	"mozambique": "MZ", "myanmar": "MM", "nepal": "NP", "netherlands": "NL",
+	"the netherlands": "NL", "nederland": "NL", "holland": "NL", "new zealand": "NZ",
+	"nicaragua": "NI", "nigeria": "NG", "north macedonia": "MK", "norway": "NO",
+	"norge": "NO", "oman": "OM", "pakistan": "PK", "panama": "PA",
+	"paraguay": "PY", "peru": "PE", "philippines": "PH", "poland": "PL",
+	"polska": "PL", "portugal": "PT", "qatar": "QA", "romania": "RO",
+	"russia": "RU", "russian federation": "RU", "rwanda": "RW", "saudi arabia": "SA",
+	"senegal": "SN", "serbia": "RS", "singapore": "SG", "slovakia": "SK",
+	"slovenia": "SI", "somalia": "SO", "south africa": "ZA", "spain": "ES",
+	"espana": "ES", "sri lanka": "LK", "sudan": "SD", "sweden": "SE",
+	"sverige": "SE", "switzerland": "CH", "schweiz": "CH", "suisse": "CH",
+	"svizzera": "CH", "syria": "SY", "taiwan": "TW", "tanzania": "TZ",
+	"thailand": "TH", "tunisia": "TN", "turkey": "TR", "turkiye": "TR",
+	"uganda": "UG", "ukraine": "UA", "united arab emirates": "AE", "uae": "AE",
+	"united kingdom": "GB", "uk": "GB", "u k": "GB", "great britain": "GB",
+	"england": "GB", "scotland": "GB", "wales": "GB", "northern ireland": "GB",
+	"united states": "US", "united states of america": "US", "usa": "US", "u s a": "US",
+	"u s": "US", "america": "US", "uruguay": "UY", "uzbekistan": "UZ",
+	"venezuela": "VE", "vietnam": "VN", "viet nam": "VN", "yemen": "YE",
+	"zambia": "ZM", "zimbabwe": "ZW",
+}
+
+// regions holds, per country, the first-level divisions written after the
+// locality in postal addresses, by postal abbreviation.
+var regions = map[string]map[string]string{
+	"US": {
+		"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas",
+		"CA": "California", "CO": "Colorado", "CT": "Connecticut", "DE": "Delaware",
+		"DC": "District of Columbia", "FL": "Florida", "GA": "Georgia", "HI": "Hawaii",
+		"ID": "Idaho", "IL": "Illinois", "IN": "Indiana", "IA": "Iowa",
+		"KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine",
+		"MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota",
+		"MS": "Mississippi", "MO": "Missouri", "MT": "Montana", "NE": "Nebraska",
+		"NV": "Nevada", "NH": "New Hampshire", "NJ": "New Jersey", "NM": "New Mexico",
+		"NY": "New York", "NC": "North Carolina", "ND": "North Dakota", "OH": "Ohio",
+		"OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island",
+		"SC": "South Carolina", "SD": "South Dakota", "TN": "Tennessee", "TX": "Texas",
+		"UT": "Utah", "VT": "Vermont", "VA": "Virginia", "WA": "Washington",
+		"WV": "West Virginia", "WI": "Wisconsin", "WY": "Wyoming", "PR": "Puerto Rico",
+	},
+	"CA": {
+		"AB": "Alberta", "BC": "British Columbia", "MB": "Manitoba", "NB": "New Brunswick",
+		"NL": "Newfoundland and Labrador", "NS": "Nova Scotia", "NT": "Northwest Territories",
+		"NU": "Nunavut", "ON": "Ontario", "PE": "Prince Edward Island", "QC": "Quebec",
+		"SK": "Saskatchewan", "YT": "Yukon",
+	},
+	"AU": {
+		"ACT": "Australian Capital Territory", "NSW": "New South Wales", "NT": "Northern Territory",
+		"QLD": "Queensland", "SA": "South Australia", "TAS": "Tasmania", "VIC": "Victoria",
+		"WA": "Western Australia",
+	},
+}
+
+// regionNames maps, per country, folded region names to their codes.
+var regionNames = map[string]map[string]string{}
+
+// regionCodes holds every region code, which are not taken as country
+// codes: "CA" ends a Californian address far more often than a Canadian
+// one.
+var regionCodes = map[string]bool{}
+
+func init() {
+	for cc, rs := range regions {
+		regionNames[cc] = map[string]string{}
+		for code, name := range rs {
+			regionNames[cc][textfold.Fold(name)] = code
+			regionCodes[code] = true
+		}
+	}
+}
+
+// postcodeFormat is the postcode syntax of a country. The submatches of re
+// joined by sep give the canonical form.
+type postcodeFormat struct {
+	re  *regexp.Regexp
+	sep string
+}
+
+var (
+	fourDigits = postcodeFormat{regexp.MustCompile(`\b(\d{4})\b`), ""}
+	fiveDigits = postcodeFormat{regexp.MustCompile(`\b(\d{5})\b`), ""}
+	sixDigits  = postcodeFormat{regexp.MustCompile(`\b(\d{6})\b`), ""}
+	// anyDigits matches the postcodes of an unknown country, ZIP+4 codes
+	// included.
+	anyDigits = postcodeFormat{regexp.MustCompile(`\b(\d{4,6})(?:-(\d{4}))?\b`), "-"}
+)
+
+// postcodes holds the postcode formats of the countries with one.
+var postcodes = map[string]postcodeFormat{
+	"GB": {regexp.MustCompile(`(?i)\b([A-Z]{1,2}\d[A-Z\d]?) ?(\d[A-Z]{2})\b`), " "},
+	"CA": {regexp.MustCompile(`(?i)\b([A-Z]\d[A-Z]) ?(\d[A-Z]\d)\b`), " "},
+	"NL": {regexp.MustCompile(`(?i)\b(\d{4}) ?([A-Z]{2})\b`), " "},
+	"US": {regexp.MustCompile(`\b(\d{5})(?:-(\d{4}))?\b`), "-"},
+	"JP": {regexp.MustCompile(`\b(\d{3})-?(\d{4})\b`), "-"},
+	"BR": {regexp.MustCompile(`\b(\d{5})-?(\d{3})\b`), "-"},
+	"PL": {regexp.MustCompile(`\b(\d{2})-(\d{3})\b`), "-"},
+	"PT": {regexp.MustCompile(`\b(\d{4})-(\d{3})\b`), "-"},
+	"SE": {regexp.MustCompile(`\b(\d{3}) ?(\d{2})\b`), " "},
+	"DE": fiveDigits, "FR": fiveDigits, "IT": fiveDigits, "ES": fiveDigits,
+	"FI": fiveDigits, "MX": fiveDigits, "TR": fiveDigits, "MY": fiveDigits,
+	"TH": fiveDigits, "ID": fiveDigits, "UA": fiveDigits, "HR": fiveDigits,
+	"AU": fourDigits, "AT": fourDigits, "BE": fourDigits, "CH": fourDigits,
+	"DK": fourDigits, "NO": fourDigits, "HU": fourDigits, "NZ": fourDigits,
+	"ZA": fourDigits, "PH": fourDigits, "LU": fourDigits,
+	"IN": sixDigits, "RU": sixDigits, "CN": sixDigits, "SG": sixDigits,
+}
+
+// distinctive lists the countries whose postcodes identify them, tried in
+// order when the country is unknown.
+var distinctive = []string{"GB", "CA", "NL"}
+
+// format returns the canonical form of a postcode match.
+func (f postcodeFormat) format(m []string) string {
+	var parts []string
+	for _, s := range m[1:] {
+		if s != "" {
+			parts = append(parts, strings.ToUpper(s))
+		}
+	}
+	return strings.Join(parts, f.sep)
+}
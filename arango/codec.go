@@ -0,0 +1,523 @@
+package arango
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ErrInvalidDocument is returned when a document does not match the shape
+// of the message it is decoded into.
+var ErrInvalidDocument = errors.New("arango: invalid document")
+
+// Case selects the attribute naming of fields without an injected tag.
+type Case int
+
+const (
+	// SnakeCase uses the proto field names, e.g. "happened_at".
+	SnakeCase Case = iota
+	// CamelCase uses the proto JSON names, e.g. "happenedAt".
+	CamelCase
+)
+
+// Codec converts messages to and from ArangoDB documents.
+//
+// System attributes take the names of the struct tags injected into the
+// generated code (_id, _key, _rev, _from, _to); every other field is
+// named by the codec's Case. Attributes and other google.protobuf.Struct
+// fields are plain JSON objects, 64-bit integers are JSON numbers and enums
+// are their value names. Unpopulated fields are omitted, like the
+// omitempty tags of the generated structs.
+//
+// Decoding accepts both snake and camel case names and enums by name or
+// number. Unknown attributes, such as those added by AQL projections, are
+// ignored.
+type Codec struct {
+	naming Case
+}
+
+// CodecOption configures a Codec.
+type CodecOption func(*Codec)
+
+// WithCase sets the naming of fields without an injected tag.
+func WithCase(c Case) CodecOption {
+	return func(x *Codec) { x.naming = c }
+}
+
+// NewCodec returns a Codec. The default naming is SnakeCase.
+func NewCodec(opts ...CodecOption) *Codec {
+	c := &Codec{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// DefaultCodec is used by Marshal and Unmarshal.
+var DefaultCodec = NewCodec()
+
+// Marshal encodes m as an ArangoDB document with DefaultCodec.
+func Marshal(m proto.Message) ([]byte, error) {
+	return DefaultCodec.Marshal(m)
+}
+
+// Unmarshal decodes an ArangoDB document into m with DefaultCodec.
+func Unmarshal(data []byte, m proto.Message) error {
+	return DefaultCodec.Unmarshal(data, m)
+}
+
+// Marshal encodes m as an ArangoDB document.
+func (c *Codec) Marshal(m proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.encodeMessage(&buf, m.ProtoReflect()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes an ArangoDB document into m, which is reset first.
+func (c *Codec) Unmarshal(data []byte, m proto.Message) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	proto.Reset(m)
+	return c.decodeMessage(m.ProtoReflect(), v, "")
+}
+
+// fieldName is the attribute naming of one field.
+type fieldName struct {
+	fd  protoreflect.FieldDescriptor
+	tag string // injected tag name, or "" if the generated default is kept
+}
+
+// nameCache maps a message full name to its []fieldName.
+var nameCache sync.Map
+
+// namesFor returns the attribute names of md's fields, reading the struct
+// tags of the generated Go type once per message type.
+func namesFor(m protoreflect.Message) []fieldName {
+	md := m.Descriptor()
+	if v, ok := nameCache.Load(md.FullName()); ok {
+		return v.([]fieldName)
+	}
+	tags := map[protoreflect.FieldNumber]string{}
+	if t := reflect.TypeOf(m.Interface()); t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		st := t.Elem()
+		for i := 0; i < st.NumField(); i++ {
+			f := st.Field(i)
+			parts := strings.Split(f.Tag.Get("protobuf"), ",")
+			if len(parts) < 2 {
+				continue
+			}
+			n, err := strconv.Atoi(parts[1])
+			if err != nil {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			tags[protoreflect.FieldNumber(n)] = name
+		}
+	}
+	fields := md.Fields()
+	out := make([]fieldName, fields.Len())
+	for i := range out {
+		fd := fields.Get(i)
+		out[i] = fieldName{fd: fd}
+		if tag := tags[fd.Number()]; tag != "" && tag != "-" && tag != string(fd.Name()) {
+			out[i].tag = tag
+		}
+	}
+	v, _ := nameCache.LoadOrStore(md.FullName(), out)
+	return v.([]fieldName)
+}
+
+func (c *Codec) name(f fieldName) string {
+	switch {
+	case f.tag != "":
+		return f.tag
+	case c.naming == CamelCase:
+		return f.fd.JSONName()
+	}
+	return string(f.fd.Name())
+}
+
+func (c *Codec) encodeMessage(buf *bytes.Buffer, m protoreflect.Message) error {
+	if isStruct(m.Descriptor()) {
+		b, err := json.Marshal(plainJSON(m.Interface()))
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+	buf.WriteByte('{')
+	first := true
+	for _, f := range namesFor(m) {
+		if !m.Has(f.fd) {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		writeString(buf, c.name(f))
+		buf.WriteByte(':')
+		if err := c.encodeField(buf, f.fd, m.Get(f.fd)); err != nil {
+			return fmt.Errorf("%s: %w", f.fd.Name(), err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func (c *Codec) encodeField(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch {
+	case fd.IsList():
+		buf.WriteByte('[')
+		for i := 0; i < v.List().Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.encodeValue(buf, fd, v.List().Get(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case fd.IsMap():
+		buf.WriteByte('{')
+		var err error
+		first := true
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			writeString(buf, k.String())
+			buf.WriteByte(':')
+			err = c.encodeValue(buf, fd.MapValue(), mv)
+			return err == nil
+		})
+		buf.WriteByte('}')
+		return err
+	}
+	return c.encodeValue(buf, fd, v)
+}
+
+func (c *Codec) encodeValue(buf *bytes.Buffer, fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return c.encodeMessage(buf, v.Message())
+	case protoreflect.StringKind:
+		writeString(buf, v.String())
+	case protoreflect.BytesKind:
+		writeString(buf, base64.StdEncoding.EncodeToString(v.Bytes()))
+	case protoreflect.BoolKind:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			writeString(buf, string(ev.Name()))
+		} else {
+			buf.WriteString(strconv.Itoa(int(v.Enum())))
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("%w: %v is not representable in JSON", ErrInvalidDocument, f)
+		}
+		bits := 64
+		if fd.Kind() == protoreflect.FloatKind {
+			bits = 32
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	default:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	}
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// plainJSON returns the Go value of a Struct, Value or ListValue.
+func plainJSON(m proto.Message) any {
+	switch x := m.(type) {
+	case *structpb.Struct:
+		return x.AsMap()
+	case *structpb.ListValue:
+		return x.AsSlice()
+	case *structpb.Value:
+		return x.AsInterface()
+	}
+	return nil
+}
+
+func isStruct(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.ListValue":
+		return true
+	}
+	return false
+}
+
+func (c *Codec) decodeMessage(m protoreflect.Message, v any, path string) error {
+	if isStruct(m.Descriptor()) {
+		return decodeStruct(m, v, path)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: %s is not an object", ErrInvalidDocument, orRoot(path))
+	}
+	for _, f := range namesFor(m) {
+		val, ok := lookup(obj, f)
+		if !ok || val == nil {
+			continue
+		}
+		if err := c.decodeField(m, f.fd, val, join(path, string(f.fd.Name()))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup finds the attribute of f under its tag, proto or JSON name.
+func lookup(obj map[string]any, f fieldName) (any, bool) {
+	for _, name := range []string{f.tag, string(f.fd.Name()), f.fd.JSONName()} {
+		if name == "" {
+			continue
+		}
+		if v, ok := obj[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Codec) decodeField(m protoreflect.Message, fd protoreflect.FieldDescriptor, v any, path string) error {
+	switch {
+	case fd.IsList():
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%w: %s is not an array", ErrInvalidDocument, path)
+		}
+		list := m.Mutable(fd).List()
+		for i, item := range items {
+			ipath := path + "[" + strconv.Itoa(i) + "]"
+			if fd.Message() != nil {
+				elem := list.NewElement()
+				if err := c.decodeMessage(elem.Message(), item, ipath); err != nil {
+					return err
+				}
+				list.Append(elem)
+				continue
+			}
+			pv, err := decodeScalar(fd, item, ipath)
+			if err != nil {
+				return err
+			}
+			list.Append(pv)
+		}
+		return nil
+	case fd.IsMap():
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: %s is not an object", ErrInvalidDocument, path)
+		}
+		mp := m.Mutable(fd).Map()
+		for k, item := range obj {
+			key, err := decodeScalar(fd.MapKey(), mapKey(fd.MapKey(), k), path+"."+k)
+			if err != nil {
+				return err
+			}
+			if fd.MapValue().Message() != nil {
+				elem := mp.NewValue()
+				if err := c.decodeMessage(elem.Message(), item, path+"."+k); err != nil {
+					return err
+				}
+				mp.Set(key.MapKey(), elem)
+				continue
+			}
+			pv, err := decodeScalar(fd.MapValue(), item, path+"."+k)
+			if err != nil {
+				return err
+			}
+			mp.Set(key.MapKey(), pv)
+		}
+		return nil
+	case fd.Message() != nil:
+		return c.decodeMessage(m.Mutable(fd).Message(), v, path)
+	}
+	pv, err := decodeScalar(fd, v, path)
+	if err != nil {
+		return err
+	}
+	m.Set(fd, pv)
+	return nil
+}
+
+// mapKey converts a JSON object key to the JSON value of a map key field.
+func mapKey(fd protoreflect.FieldDescriptor, k string) any {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return k
+	case protoreflect.BoolKind:
+		return k == "true"
+	}
+	return json.Number(k)
+}
+
+func decodeScalar(fd protoreflect.FieldDescriptor, v any, path string) (protoreflect.Value, error) {
+	bad := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("%w: %s: unexpected %v for %s field", ErrInvalidDocument, path, v, fd.Kind())
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		if s, ok := v.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if s, ok := v.(string); ok {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return bad()
+			}
+			return protoreflect.ValueOfBytes(b), nil
+		}
+	case protoreflect.BoolKind:
+		if b, ok := v.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.EnumKind:
+		switch x := v.(type) {
+		case string:
+			if ev := fd.Enum().Values().ByName(protoreflect.Name(x)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+		case json.Number:
+			if n, err := strconv.ParseInt(string(x), 10, 32); err == nil {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+			}
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if n, ok := v.(json.Number); ok {
+			f, err := n.Float64()
+			if err != nil {
+				return bad()
+			}
+			if fd.Kind() == protoreflect.FloatKind {
+				return protoreflect.ValueOfFloat32(float32(f)), nil
+			}
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, ok := v.(json.Number); ok {
+			if i, err := strconv.ParseInt(string(n), 10, 32); err == nil {
+				return protoreflect.ValueOfInt32(int32(i)), nil
+			}
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := v.(json.Number); ok {
+			if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+				return protoreflect.ValueOfInt64(i), nil
+			}
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, ok := v.(json.Number); ok {
+			if i, err := strconv.ParseUint(string(n), 10, 32); err == nil {
+				return protoreflect.ValueOfUint32(uint32(i)), nil
+			}
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := v.(json.Number); ok {
+			if i, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+				return protoreflect.ValueOfUint64(i), nil
+			}
+		}
+	}
+	return bad()
+}
+
+// decodeStruct stores a plain JSON value in a Struct, Value or ListValue.
+func decodeStruct(m protoreflect.Message, v any, path string) error {
+	v = plain(v)
+	var src proto.Message
+	switch m.Interface().(type) {
+	case *structpb.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: %s is not an object", ErrInvalidDocument, orRoot(path))
+		}
+		s, err := structpb.NewStruct(obj)
+		if err != nil {
+			return err
+		}
+		src = s
+	case *structpb.ListValue:
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%w: %s is not an array", ErrInvalidDocument, orRoot(path))
+		}
+		l, err := structpb.NewList(items)
+		if err != nil {
+			return err
+		}
+		src = l
+	default:
+		val, err := structpb.NewValue(v)
+		if err != nil {
+			return err
+		}
+		src = val
+	}
+	proto.Merge(m.Interface(), src)
+	return nil
+}
+
+// plain replaces the json.Numbers in v with float64s.
+func plain(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, item := range x {
+			x[k] = plain(item)
+		}
+	case []any:
+		for i, item := range x {
+			x[i] = plain(item)
+		}
+	}
+	return v
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "document"
+	}
+	return path
+}
@@ -0,0 +1,172 @@
+package arango
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustStruct(m map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var attrs = mustStruct(map[string]any{"score": 3.5, "nested": map[string]any{"ok": true}, "list": []any{"a", 1}})
+
+// documents holds one populated message of each document type.
+var documents = []proto.Message{
+	&model.Event{
+		Id: "events/e1", Key: "e1", Rev: "_a1", Owner: "alice", Read: []string{"bob"}, Write: []string{"carol"},
+		Type: "meeting", Title: "Meeting", Description: "quarterly",
+		Location:   &model.LocationData{Latitude: 51.5074, Longitude: -0.1278, CountryCode: "GB", AdministrativeArea: "England", Locality: "London"},
+		HappenedAt: 1700000000, UpdatedAt: 1700003600, Tags: []string{"a", "b"}, Attributes: attrs,
+	},
+	&model.Source{
+		Key: "s1", Type: "article", Url: "https://example.com/report", Name: "report", Title: "The report",
+		Reliability: 80, CreatedAt: 1690000000, UpdatedAt: 1690000060, Attributes: attrs,
+	},
+	&model.Person{Key: "p1", Role: "analyst", Name: "Ada", Nationality: "GB", BirthDate: -4861728000, UpdatedAt: 1700000000, Aliases: []string{"Countess"}},
+	&model.Organization{Key: "o1", Type: "company", Name: "Acme", FoundedAt: 946684800, DiscoveredAt: 1600000000, LastVisited: 1700000000},
+	&model.Website{Key: "w1", Url: "https://example.com", Title: "Example", Description: "home", FoundedAt: 820454400, DiscoveredAt: 1600000000, LastVisited: 1700000000},
+	&model.Relation{Id: "relations/r1", Key: "r1", From: "persons/p1", To: "organizations/o1", Name: "employee", Confidence: 90, Label: "member_of", CreatedAt: 1690000000, UpdatedAt: 1690000060},
+	&model.Entity{Entity: &model.Entity_Person{Person: &model.Person{Key: "p2", Name: "Grace"}}},
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		naming      Case
+		present     []string
+		absent      []string
+		eventFields []string
+	}{
+		{SnakeCase, []string{"_id", "_key", "_rev"}, []string{"id", "key", "rev"}, []string{"happened_at", "updated_at"}},
+		{CamelCase, []string{"_id", "_key", "_rev"}, []string{"id", "key", "rev"}, []string{"happenedAt", "updatedAt"}},
+	}
+	for _, tt := range tests {
+		c := NewCodec(WithCase(tt.naming))
+		other := NewCodec(WithCase(CamelCase))
+		if tt.naming == CamelCase {
+			other = NewCodec(WithCase(SnakeCase))
+		}
+		for _, m := range documents {
+			name := string(m.ProtoReflect().Descriptor().Name())
+			b, err := c.Marshal(m)
+			if err != nil {
+				t.Fatalf("case %d: Marshal(%s): %v", tt.naming, name, err)
+			}
+			got := m.ProtoReflect().New().Interface()
+			if err := c.Unmarshal(b, got); err != nil {
+				t.Fatalf("case %d: Unmarshal(%s): %v", tt.naming, name, err)
+			}
+			if !proto.Equal(got, m) {
+				t.Errorf("case %d: %s round trip = %v, want %v", tt.naming, name, got, m)
+			}
+			// Documents written in either case decode with the other.
+			got = m.ProtoReflect().New().Interface()
+			if err := other.Unmarshal(b, got); err != nil || !proto.Equal(got, m) {
+				t.Errorf("case %d: %s read with the other case = %v, %v", tt.naming, name, got, err)
+			}
+		}
+
+		b, err := c.Marshal(documents[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(b, &obj); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range append(tt.present, tt.eventFields...) {
+			if _, ok := obj[k]; !ok {
+				t.Errorf("case %d: event document lacks %q: %s", tt.naming, k, b)
+			}
+		}
+		for _, k := range tt.absent {
+			if _, ok := obj[k]; ok {
+				t.Errorf("case %d: event document has %q: %s", tt.naming, k, b)
+			}
+		}
+	}
+}
+
+func TestCodecRelationAttributes(t *testing.T) {
+	b, err := Marshal(documents[5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(b, &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["_from"] != "persons/p1" || obj["_to"] != "organizations/o1" || obj["confidence"] != 90.0 {
+		t.Errorf("relation document = %s", b)
+	}
+}
+
+func TestCodecDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want proto.Message
+	}{
+		{"unknown attributes", `{"_key":"e1","location":{"latitude":1.5},"projected":true}`, &model.Event{Key: "e1", Location: &model.LocationData{Latitude: 1.5}}},
+		{"null fields", `{"_key":"p1","name":null,"aliases":null}`, &model.Person{Key: "p1"}},
+		{"enum by name", `{"location":{"source":"LOCATION_SOURCE_GPS"}}`, &model.Event{Location: &model.LocationData{Source: model.LocationSource_LOCATION_SOURCE_GPS}}},
+		{"enum by number", `{"location":{"source":2}}`, &model.Event{Location: &model.LocationData{Source: model.LocationSource_LOCATION_SOURCE_GEOCODED}}},
+		{"entity oneof", `{"website":{"_key":"w1","url":"https://example.com"}}`, &model.Entity{Entity: &model.Entity_Website{Website: &model.Website{Key: "w1", Url: "https://example.com"}}}},
+	}
+	for _, tt := range tests {
+		got := tt.want.ProtoReflect().New().Interface()
+		if err := Unmarshal([]byte(tt.doc), got); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCodecEnum(t *testing.T) {
+	tests := []struct {
+		source model.LocationSource
+		want   string
+	}{
+		{model.LocationSource_LOCATION_SOURCE_MANUAL, `{"source":"LOCATION_SOURCE_MANUAL"}`},
+		{model.LocationSource(9), `{"source":9}`},
+	}
+	for _, tt := range tests {
+		b, err := Marshal(&model.LocationData{Source: tt.source})
+		if err != nil || string(b) != tt.want {
+			t.Errorf("Marshal(source %d) = %s, %v, want %s", tt.source, b, err, tt.want)
+		}
+	}
+}
+
+func TestCodecErrors(t *testing.T) {
+	if _, err := Marshal(&model.Event{Location: &model.LocationData{Latitude: float32(math.NaN())}}); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Marshal(NaN) error = %v, want ErrInvalidDocument", err)
+	}
+	for _, doc := range []string{
+		`[]`,
+		`{"title":1}`,
+		`{"happened_at":"yesterday"}`,
+		`{"happened_at":1.5}`,
+		`{"tags":"a"}`,
+		`{"location":[]}`,
+		`{"attributes":"x"}`,
+		`{"location":{"source":"GPS"}}`,
+	} {
+		if err := Unmarshal([]byte(doc), &model.Event{}); !errors.Is(err, ErrInvalidDocument) {
+			t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidDocument", doc, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package arango
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+var (
+	ErrUnknownLabel = errors.New("arango: relation label has no endpoint rule")
+	ErrEndpoint     = errors.New("arango: relation endpoint not allowed")
+)
+
+// Endpoints lists the collections a relation label may connect.
+type Endpoints struct {
+	From []string
+	To   []string
+}
+
+var (
+	endpointsMu sync.RWMutex
+	endpoints   = map[string]Endpoints{}
+)
+
+// AllowEndpoints registers the collections that relations with label may
+// start from and point to. Registering a label again adds to its lists.
+// A label must be registered before CheckRelation accepts it; package edge
+// registers the labels of the kinds it defines.
+func AllowEndpoints(label string, from, to []string) {
+	endpointsMu.Lock()
+	defer endpointsMu.Unlock()
+	e := endpoints[label]
+	e.From = appendNew(e.From, from)
+	e.To = appendNew(e.To, to)
+	endpoints[label] = e
+}
+
+// EndpointsOf returns the registered endpoint rule of label.
+func EndpointsOf(label string) (Endpoints, bool) {
+	endpointsMu.RLock()
+	defer endpointsMu.RUnlock()
+	e, ok := endpoints[label]
+	return Endpoints{From: slices.Clone(e.From), To: slices.Clone(e.To)}, ok
+}
+
+// CheckRelation parses the _from and _to handles of r and verifies that
+// their collections are allowed endpoints for r's label.
+func CheckRelation(r *model.Relation) error {
+	from, err := ParseHandle(r.GetFrom())
+	if err != nil {
+		return fmt.Errorf("_from: %w", err)
+	}
+	to, err := ParseHandle(r.GetTo())
+	if err != nil {
+		return fmt.Errorf("_to: %w", err)
+	}
+	e, ok := EndpointsOf(r.GetLabel())
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLabel, r.GetLabel())
+	}
+	if !slices.Contains(e.From, from.Collection) {
+		return fmt.Errorf("%w: %q cannot start from %s", ErrEndpoint, r.GetLabel(), from.Collection)
+	}
+	if !slices.Contains(e.To, to.Collection) {
+		return fmt.Errorf("%w: %q cannot point to %s", ErrEndpoint, r.GetLabel(), to.Collection)
+	}
+	return nil
+}
+
+func appendNew(dst, src []string) []string {
+	for _, s := range src {
+		if !slices.Contains(dst, s) {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
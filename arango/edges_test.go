@@ -0,0 +1,44 @@
+package arango
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestCheckRelation(t *testing.T) {
+	AllowEndpoints("test_employs", []string{OrganizationCollection}, []string{PersonCollection})
+	tests := []struct {
+		label, from, to string
+		want            error
+	}{
+		{"test_employs", "organizations/a", "persons/b", nil},
+		{"test_employs", "persons/b", "organizations/a", ErrEndpoint},
+		{"test_employs", "organizations/a", "websites/b", ErrEndpoint},
+		{"test_unregistered", "persons/a", "events/b", ErrUnknownLabel},
+		{"test_employs", "organizations", "persons/b", ErrInvalidHandle},
+	}
+	for _, tt := range tests {
+		err := CheckRelation(&model.Relation{Label: tt.label, From: tt.from, To: tt.to})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("CheckRelation(%s %s %s) = %v, want %v", tt.from, tt.label, tt.to, err, tt.want)
+		}
+	}
+}
+
+func TestAllowEndpointsMerges(t *testing.T) {
+	AllowEndpoints("test_mentions", []string{SourceCollection}, []string{PersonCollection})
+	AllowEndpoints("test_mentions", []string{WebsiteCollection, SourceCollection}, []string{PersonCollection})
+	e, ok := EndpointsOf("test_mentions")
+	if !ok {
+		t.Fatal("test_mentions not registered")
+	}
+	if want := []string{SourceCollection, WebsiteCollection}; !slices.Equal(e.From, want) {
+		t.Errorf("From = %v, want %v", e.From, want)
+	}
+	if want := []string{PersonCollection}; !slices.Equal(e.To, want) {
+		t.Errorf("To = %v, want %v", e.To, want)
+	}
+}
@@ -0,0 +1,154 @@
+// Package arango maps the OSINT model onto ArangoDB documents: document
+// handles, collection names, edge endpoint rules and the JSON document
+// shape.
+package arango
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Canonical collection names of the model messages.
+const (
+	SourceCollection       = "sources"
+	PersonCollection       = "persons"
+	OrganizationCollection = "organizations"
+	WebsiteCollection      = "websites"
+	EventCollection        = "events"
+	RelationCollection     = "relations"
+)
+
+const (
+	maxKeyLength        = 254
+	maxCollectionLength = 256
+)
+
+var (
+	ErrInvalidKey        = errors.New("arango: invalid document key")
+	ErrInvalidCollection = errors.New("arango: invalid collection name")
+	ErrInvalidHandle     = errors.New("arango: invalid document handle")
+	ErrUnknownType       = errors.New("arango: message type has no collection")
+)
+
+// Handle is a document handle of the form "collection/key", as stored in
+// the _id, _from and _to attributes.
+type Handle struct {
+	Collection string
+	Key        string
+}
+
+// NewHandle builds a handle after validating both parts.
+func NewHandle(collection, key string) (Handle, error) {
+	if err := ValidateCollection(collection); err != nil {
+		return Handle{}, err
+	}
+	if err := ValidateKey(key); err != nil {
+		return Handle{}, err
+	}
+	return Handle{Collection: collection, Key: key}, nil
+}
+
+// ParseHandle splits and validates a "collection/key" handle.
+func ParseHandle(s string) (Handle, error) {
+	collection, key, ok := strings.Cut(s, "/")
+	if !ok {
+		return Handle{}, fmt.Errorf("%w: %q has no '/'", ErrInvalidHandle, s)
+	}
+	return NewHandle(collection, key)
+}
+
+func (h Handle) String() string {
+	return h.Collection + "/" + h.Key
+}
+
+// IsZero reports whether h is the empty handle.
+func (h Handle) IsZero() bool {
+	return h.Collection == "" && h.Key == ""
+}
+
+// ValidateKey checks a document key against the ArangoDB traditional key
+// rules: 1 to 254 bytes of letters, digits and _-:.@()+,=;$!*'%.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return fmt.Errorf("%w: length %d not in [1, %d]", ErrInvalidKey, len(key), maxKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if !isKeyChar(key[i]) {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidKey, key, key[i])
+		}
+	}
+	return nil
+}
+
+func isKeyChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("_-:.@()+,=;$!*'%", c) >= 0
+}
+
+// ValidateCollection checks a collection name against the ArangoDB
+// traditional naming rules: a letter or underscore followed by letters,
+// digits, underscores or dashes, at most 256 bytes.
+func ValidateCollection(name string) error {
+	if name == "" || len(name) > maxCollectionLength {
+		return fmt.Errorf("%w: length %d not in [1, %d]", ErrInvalidCollection, len(name), maxCollectionLength)
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		ok := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' ||
+			i > 0 && (c >= '0' && c <= '9' || c == '-')
+		if !ok {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidCollection, name, c)
+		}
+	}
+	return nil
+}
+
+// CollectionOf returns the canonical collection of a model message. An
+// Entity resolves to the collection of the message set in its oneof.
+func CollectionOf(m proto.Message) (string, error) {
+	switch entity.KindOf(m) {
+	case entity.KindSource:
+		return SourceCollection, nil
+	case entity.KindPerson:
+		return PersonCollection, nil
+	case entity.KindOrganization:
+		return OrganizationCollection, nil
+	case entity.KindWebsite:
+		return WebsiteCollection, nil
+	case entity.KindEvent:
+		return EventCollection, nil
+	case entity.KindRelation:
+		return RelationCollection, nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnknownType, m)
+}
+
+// HandleOf returns the handle of a document. The _id is used when set;
+// otherwise the handle is built from the canonical collection and _key.
+func HandleOf(m proto.Message) (Handle, error) {
+	if e, ok := m.(*model.Entity); ok {
+		if d := entity.Unwrap(e); d != nil {
+			m = d
+		}
+	}
+	doc, ok := m.(entity.Document)
+	if !ok {
+		return Handle{}, fmt.Errorf("%w: %T", ErrUnknownType, m)
+	}
+	if id := doc.GetId(); id != "" {
+		return ParseHandle(id)
+	}
+	collection, err := CollectionOf(m)
+	if err != nil {
+		return Handle{}, err
+	}
+	return NewHandle(collection, doc.GetKey())
+}
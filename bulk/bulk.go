@@ -0,0 +1,176 @@
+// Package bulk reads and writes model messages as CSV and newline-delimited
+// JSON.
+//
+// CSV columns are named by proto field path: top-level fields by name,
+// nested messages with a dot ("location.latitude") and attributes as
+// "attributes.<key>" with further dots for nested objects. Repeated fields
+// are written as one cell joined by the list separator, with separators and
+// backslashes inside values escaped by a backslash. Attribute cells holding
+// JSON keep its type, so strings that read as JSON, such as "123" or
+// "true", are written quoted. Headers can be renamed with WithColumns.
+//
+// Deprecated fields are not written. A location read with only the
+// deprecated postal_code gets its postcode from it, and one read with a
+// postcode gets postal_code kept in step, as geo.SetPostalCode does.
+//
+// Readers never abort on a bad row: rows that fail to parse, or to
+// validate when WithValidation is set, are reported as RowErrors and
+// skipped, and the remaining rows are returned.
+package bulk
+
+import (
+	"fmt"
+	"strings"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultListSeparator joins repeated field values in a CSV cell.
+const DefaultListSeparator = ";"
+
+// Skip maps a CSV header to no field; such columns are ignored on read.
+const Skip = "-"
+
+// RowError reports a row that was skipped. Row is the 1-based line of the
+// row in the input; Column is the header concerned, if any.
+type RowError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Option configures readers and writers.
+type Option func(*config)
+
+type config struct {
+	columns   map[string]string // header -> field path
+	separator string
+	validate  bool
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{columns: map[string]string{}, separator: DefaultListSeparator}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithColumns maps CSV headers to field paths, e.g. {"Full Name": "name"}.
+// Writers use the inverse mapping for headers. Map a header to Skip to
+// ignore it on read.
+func WithColumns(columns map[string]string) Option {
+	return func(c *config) {
+		for header, path := range columns {
+			c.columns[header] = path
+		}
+	}
+}
+
+// WithListSeparator sets the separator of repeated values in CSV cells.
+func WithListSeparator(sep string) Option {
+	return func(c *config) {
+		c.separator = sep
+	}
+}
+
+// WithValidation runs validate.Message on every decoded row and reports
+// violations as row errors.
+func WithValidation() Option {
+	return func(c *config) {
+		c.validate = true
+	}
+}
+
+// path returns the field path of a header.
+func (c *config) path(header string) string {
+	if p, ok := c.columns[header]; ok {
+		return p
+	}
+	return strings.TrimSpace(header)
+}
+
+// header returns the header of a field path.
+func (c *config) header(path string) string {
+	for h, p := range c.columns {
+		if p == path {
+			return h
+		}
+	}
+	return path
+}
+
+func (c *config) check(m proto.Message) error {
+	if !c.validate {
+		return nil
+	}
+	return validate.Message(m)
+}
+
+// newMessage returns an empty message of type T.
+func newMessage[T proto.Message]() T {
+	var zero T
+	return zero.ProtoReflect().New().Interface().(T)
+}
+
+// deprecated reports whether fd is marked deprecated in its proto file.
+func deprecated(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	return ok && opts.GetDeprecated()
+}
+
+// syncPostcodes brings the postcode and deprecated postal_code of every
+// LocationData in m in step, whichever of them was read.
+func syncPostcodes(m protoreflect.Message) {
+	if l, ok := m.Interface().(*model.LocationData); ok {
+		geo.SetPostalCode(l, geo.PostalCode(l))
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil || fd.IsMap():
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				syncPostcodes(v.List().Get(i).Message())
+			}
+		default:
+			syncPostcodes(v.Message())
+		}
+		return true
+	})
+}
+
+// clearDeprecated clears the deprecated fields of m and of every message
+// nested in it.
+func clearDeprecated(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case deprecated(fd):
+			m.Clear(fd)
+		case fd.Message() == nil || fd.IsMap():
+		case fd.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				clearDeprecated(v.List().Get(i).Message())
+			}
+		default:
+			clearDeprecated(v.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,397 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const structName = "google.protobuf.Struct"
+
+// ReadCSV decodes rows of a CSV file with a header line into messages of
+// type T. An unknown header or a read error fails the whole file; every
+// other problem, malformed CSV included, is reported per row.
+func ReadCSV[T proto.Message](r io.Reader, opts ...Option) ([]T, []*RowError, error) {
+	c := newConfig(opts)
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	md := newMessage[T]().ProtoReflect().Descriptor()
+	setters := make([]setter, len(header))
+	for i, h := range header {
+		path := c.path(h)
+		if path == Skip || path == "" {
+			continue
+		}
+		if setters[i], err = c.resolve(md, path); err != nil {
+			return nil, nil, fmt.Errorf("bulk: column %q: %w", h, err)
+		}
+	}
+
+	var out []T
+	var errs []*RowError
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return out, errs, err
+			}
+			errs = append(errs, &RowError{Row: pe.StartLine, Err: pe.Err})
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		m := newMessage[T]()
+		ok := true
+		for i, cell := range record {
+			if setters[i] == nil || cell == "" {
+				continue
+			}
+			if err := setters[i](m.ProtoReflect(), cell); err != nil {
+				errs = append(errs, &RowError{Row: line, Column: header[i], Err: err})
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+		syncPostcodes(m.ProtoReflect())
+		if err := c.check(m); err != nil {
+			errs = append(errs, &RowError{Row: line, Err: err})
+			continue
+		}
+		out = append(out, m)
+	}
+	return out, errs, nil
+}
+
+// setter stores a cell into a message.
+type setter func(m protoreflect.Message, cell string) error
+
+// resolve builds the setter of a dotted field path.
+func (c *config) resolve(md protoreflect.MessageDescriptor, path string) (setter, error) {
+	parts := strings.Split(path, ".")
+	var chain []protoreflect.FieldDescriptor
+	for i, part := range parts {
+		fd := md.Fields().ByName(protoreflect.Name(part))
+		if fd == nil || fd.IsMap() {
+			return nil, fmt.Errorf("unknown field %q", strings.Join(parts[:i+1], "."))
+		}
+		chain = append(chain, fd)
+		if fd.Message() != nil && fd.Message().FullName() == structName {
+			keys := parts[i+1:]
+			if len(keys) == 0 || fd.IsList() {
+				return nil, fmt.Errorf("%q needs an attribute key", path)
+			}
+			return func(m protoreflect.Message, cell string) error {
+				s := parent(m, chain).Mutable(fd).Message().Interface().(*structpb.Struct)
+				return setAttribute(s, keys, cell)
+			}, nil
+		}
+		if fd.Message() != nil {
+			if fd.IsList() || i == len(parts)-1 {
+				return nil, fmt.Errorf("%q is a message, not a value", path)
+			}
+			md = fd.Message()
+			continue
+		}
+		if i != len(parts)-1 {
+			return nil, fmt.Errorf("%q is not a message", strings.Join(parts[:i+1], "."))
+		}
+	}
+	fd := chain[len(chain)-1]
+	return func(m protoreflect.Message, cell string) error {
+		target := parent(m, chain)
+		if !fd.IsList() {
+			v, err := parseScalar(fd, cell)
+			if err != nil {
+				return err
+			}
+			target.Set(fd, v)
+			return nil
+		}
+		list := target.Mutable(fd).List()
+		for _, item := range splitList(cell, c.separator) {
+			v, err := parseScalar(fd, strings.TrimSpace(item))
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	}, nil
+}
+
+// parent returns the message holding the last field of chain, creating
+// intermediate messages.
+func parent(m protoreflect.Message, chain []protoreflect.FieldDescriptor) protoreflect.Message {
+	for _, fd := range chain[:len(chain)-1] {
+		m = m.Mutable(fd).Message()
+	}
+	return m
+}
+
+func parseScalar(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("unknown %s value %q", fd.Enum().Name(), s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+}
+
+// setAttribute stores cell at the nested key path of s. Cells holding JSON
+// numbers, booleans, arrays or objects keep their type, and a JSON string
+// is unquoted; anything else is a string as written.
+func setAttribute(s *structpb.Struct, keys []string, cell string) error {
+	for _, k := range keys[:len(keys)-1] {
+		if s.Fields == nil {
+			s.Fields = map[string]*structpb.Value{}
+		}
+		next := s.Fields[k].GetStructValue()
+		if next == nil {
+			next = &structpb.Struct{}
+			s.Fields[k] = structpb.NewStructValue(next)
+		}
+		s = next
+	}
+	var v any = cell
+	var decoded any
+	if err := json.Unmarshal([]byte(cell), &decoded); err == nil && decoded != nil {
+		v = decoded
+	}
+	pv, err := structpb.NewValue(v)
+	if err != nil {
+		return err
+	}
+	if s.Fields == nil {
+		s.Fields = map[string]*structpb.Value{}
+	}
+	s.Fields[keys[len(keys)-1]] = pv
+	return nil
+}
+
+// WriteCSV encodes ms with a header line. Columns follow the field order of
+// T, leaving out deprecated fields; attribute columns are the union of keys
+// present in ms, sorted.
+func WriteCSV[T proto.Message](w io.Writer, ms []T, opts ...Option) error {
+	c := newConfig(opts)
+	md := newMessage[T]().ProtoReflect().Descriptor()
+	attrKeys := map[string]map[string]bool{}
+	for _, m := range ms {
+		collectAttributes(m.ProtoReflect(), "", attrKeys)
+	}
+	paths := columnPaths(md, "", attrKeys)
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(paths))
+	for i, p := range paths {
+		header[i] = c.header(p)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, m := range ms {
+		record := make([]string, len(paths))
+		for i, p := range paths {
+			record[i] = c.cell(m.ProtoReflect(), strings.Split(p, "."))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// collectAttributes records the flattened attribute keys of every Struct
+// field below m, indexed by the field's path.
+func collectAttributes(m protoreflect.Message, prefix string, keys map[string]map[string]bool) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return true
+		}
+		path := prefix + string(fd.Name())
+		if fd.Message().FullName() == structName {
+			if keys[path] == nil {
+				keys[path] = map[string]bool{}
+			}
+			flattenKeys(v.Message().Interface().(*structpb.Struct), "", keys[path])
+			return true
+		}
+		collectAttributes(v.Message(), path+".", keys)
+		return true
+	})
+}
+
+func flattenKeys(s *structpb.Struct, prefix string, out map[string]bool) {
+	for k, v := range s.GetFields() {
+		if nested := v.GetStructValue(); nested != nil && len(nested.GetFields()) > 0 {
+			flattenKeys(nested, prefix+k+".", out)
+			continue
+		}
+		out[prefix+k] = true
+	}
+}
+
+func columnPaths(md protoreflect.MessageDescriptor, prefix string, attrKeys map[string]map[string]bool) []string {
+	var paths []string
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap(), deprecated(fd):
+		case fd.Message() != nil && fd.Message().FullName() == structName:
+			keys := make([]string, 0, len(attrKeys[path]))
+			for k := range attrKeys[path] {
+				keys = append(keys, path+"."+k)
+			}
+			sort.Strings(keys)
+			paths = append(paths, keys...)
+		case fd.Message() != nil:
+			if !fd.IsList() {
+				paths = append(paths, columnPaths(fd.Message(), path+".", attrKeys)...)
+			}
+		default:
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// cell formats the value at path, or "" when it is unset.
+func (c *config) cell(m protoreflect.Message, path []string) string {
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if fd == nil || !m.Has(fd) {
+		return ""
+	}
+	v := m.Get(fd)
+	switch {
+	case fd.Message() != nil && fd.Message().FullName() == structName:
+		return attributeCell(v.Message().Interface().(*structpb.Struct), path[1:])
+	case fd.Message() != nil:
+		return c.cell(v.Message(), path[1:])
+	case fd.IsList():
+		items := make([]string, v.List().Len())
+		for i := range items {
+			items[i] = formatScalar(fd, v.List().Get(i))
+		}
+		return joinList(items, c.separator)
+	}
+	return formatScalar(fd, v)
+}
+
+// joinList joins the items of a repeated field with sep. Backslashes and
+// separators inside items are escaped with a backslash.
+func joinList(items []string, sep string) string {
+	if sep == "" {
+		return strings.Join(items, sep)
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, sep, `\`+sep)
+	escaped := make([]string, len(items))
+	for i, s := range items {
+		escaped[i] = escaper.Replace(s)
+	}
+	return strings.Join(escaped, sep)
+}
+
+// splitList splits a cell written by joinList. A backslash not followed by
+// a backslash or sep is kept as written.
+func splitList(cell, sep string) []string {
+	if sep == "" {
+		return []string{cell}
+	}
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(cell); {
+		switch rest := cell[i:]; {
+		case strings.HasPrefix(rest, `\`+sep):
+			b.WriteString(sep)
+			i += 1 + len(sep)
+		case strings.HasPrefix(rest, `\\`):
+			b.WriteByte('\\')
+			i += 2
+		case strings.HasPrefix(rest, sep):
+			items = append(items, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(cell[i])
+			i++
+		}
+	}
+	return append(items, b.String())
+}
+
+func attributeCell(s *structpb.Struct, keys []string) string {
+	v, ok := s.GetFields()[keys[0]]
+	if !ok {
+		return ""
+	}
+	if len(keys) > 1 {
+		return attributeCell(v.GetStructValue(), keys[1:])
+	}
+	// Strings are written as they are unless setAttribute would decode
+	// them as JSON, as with "123" or "true"; those are quoted.
+	if str, ok := v.GetKind().(*structpb.Value_StringValue); ok && !json.Valid([]byte(str.StringValue)) {
+		return str.StringValue
+	}
+	b, _ := json.Marshal(v.AsInterface())
+	return string(b)
+}
+
+func formatScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+	}
+	return v.String()
+}
@@ -0,0 +1,242 @@
+package bulk
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestReadCSVRowErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		names   []string
+		rows    []int
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			input: "name,birth_date\nAda,100\nBob,200\n",
+			names: []string{"Ada", "Bob"},
+		},
+		{
+			name:    "bare quote",
+			input:   "name,aliases,birth_date\nBo\"b,x,1\nAda,y,2\n",
+			names:   []string{"Ada"},
+			rows:    []int{2},
+			wantErr: csv.ErrBareQuote,
+		},
+		{
+			name:    "field count",
+			input:   "name,birth_date\nAda\nBob,2\n",
+			names:   []string{"Bob"},
+			rows:    []int{2},
+			wantErr: csv.ErrFieldCount,
+		},
+		{
+			name:  "bad number",
+			input: "name,birth_date\nAda,soon\nBob,2\n",
+			names: []string{"Bob"},
+			rows:  []int{2},
+		},
+		{
+			name:    "unterminated quote",
+			input:   "name,birth_date\nAda,1\n\"Bob,2\n",
+			names:   []string{"Ada"},
+			rows:    []int{3},
+			wantErr: csv.ErrQuote,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			people, errs, err := ReadCSV[*model.Person](strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, p := range people {
+				names = append(names, p.GetName())
+			}
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names = %q, want %q", names, tt.names)
+			}
+			var rows []int
+			for _, e := range errs {
+				rows = append(rows, e.Row)
+			}
+			if !reflect.DeepEqual(rows, tt.rows) {
+				t.Errorf("error rows = %v, want %v (%v)", rows, tt.rows, errs)
+			}
+			if tt.wantErr != nil && (len(errs) == 0 || !errors.Is(errs[0], tt.wantErr)) {
+				t.Errorf("errors = %v, want %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSVListRoundTrip(t *testing.T) {
+	tests := []struct {
+		sep  string
+		tags []string
+	}{
+		{DefaultListSeparator, []string{"plain", "a;b", `back\slash`, `trailing\`, `\;`}},
+		{"|", []string{"x|y", "z"}},
+		{", ", []string{"one, two", "three"}},
+	}
+	for _, tt := range tests {
+		in := []*model.Person{{Key: "p1", Name: "Ada", Tags: tt.tags}}
+		var buf bytes.Buffer
+		if err := WriteCSV(&buf, in, WithListSeparator(tt.sep)); err != nil {
+			t.Fatal(err)
+		}
+		out, errs, err := ReadCSV[*model.Person](&buf, WithListSeparator(tt.sep))
+		if err != nil || len(errs) > 0 {
+			t.Fatalf("ReadCSV: %v %v", err, errs)
+		}
+		if len(out) != 1 || !proto.Equal(out[0], in[0]) {
+			t.Errorf("separator %q: read back %v, want %v", tt.sep, out, in)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		cell string
+		want []string
+	}{
+		{"a;b", []string{"a", "b"}},
+		{`a\;b;c`, []string{"a;b", "c"}},
+		{`C:\dir;x`, []string{`C:\dir`, "x"}},
+		{`a\\;b`, []string{`a\`, "b"}},
+		{"", []string{""}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.cell, ";"); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.cell, got, tt.want)
+		}
+	}
+}
+
+func TestCSVAttributeRoundTrip(t *testing.T) {
+	attrs, err := structpb.NewStruct(map[string]any{
+		"count":   3,
+		"flag":    true,
+		"digits":  "123",
+		"word":    "true",
+		"quoted":  `"hi"`,
+		"null":    "null",
+		"plain":   "hello world",
+		"list":    []any{"a", 1},
+		"nested":  map[string]any{"zip": "01234"},
+		"padded":  " 7 ",
+		"unicode": "Zürich",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := []*model.Person{{Key: "p1", Attributes: attrs}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	out, errs, err := ReadCSV[*model.Person](&buf)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("ReadCSV: %v %v", err, errs)
+	}
+	if len(out) != 1 || !proto.Equal(out[0], in[0]) {
+		t.Errorf("read back %v, want %v", out, in)
+	}
+}
+
+var locationEvents = []*model.Event{
+	{Key: "e1", Title: "gps", Location: &model.LocationData{
+		Latitude: 51.501, Longitude: -0.1416, Altitude: 12.5, Accuracy: 30,
+		Source: model.LocationSource_LOCATION_SOURCE_GPS, Postcode: "SW1A 1AA",
+	}},
+	{Key: "e2", Title: "numeric", Location: &model.LocationData{
+		Latitude: 48.86, Longitude: 2.34, Postcode: "75001", PostalCode: 75001,
+		Source: model.LocationSource_LOCATION_SOURCE_GEOCODED,
+	}},
+	{Key: "e3", Title: "leading zero", Location: &model.LocationData{Postcode: "02134"}},
+}
+
+func TestCSVLocationRoundTrip(t *testing.T) {
+	in := locationEvents
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	header, _, _ := strings.Cut(buf.String(), "\n")
+	for _, col := range []string{"location.postcode", "location.accuracy", "location.altitude", "location.source"} {
+		if !strings.Contains(header, col) {
+			t.Errorf("header %q lacks %s", header, col)
+		}
+	}
+	if strings.Contains(header, "location.postal_code") {
+		t.Errorf("header %q has the deprecated location.postal_code", header)
+	}
+	out, errs, err := ReadCSV[*model.Event](&buf)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("ReadCSV: %v %v", err, errs)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("read %d events, want %d", len(out), len(in))
+	}
+	for i := range in {
+		if !proto.Equal(out[i], in[i]) {
+			t.Errorf("read back %v, want %v", out[i], in[i])
+		}
+	}
+}
+
+func TestNDJSONLocationRoundTrip(t *testing.T) {
+	in := locationEvents
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "postal_code") {
+		t.Errorf("output has the deprecated postal_code:\n%s", buf.String())
+	}
+	if in[1].GetLocation().GetPostalCode() != 75001 {
+		t.Error("WriteNDJSON modified its input")
+	}
+	out, errs, err := ReadNDJSON[*model.Event](&buf)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("ReadNDJSON: %v %v", err, errs)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("read %d events, want %d", len(out), len(in))
+	}
+	for i := range in {
+		if !proto.Equal(out[i], in[i]) {
+			t.Errorf("read back %v, want %v", out[i], in[i])
+		}
+	}
+}
+
+func TestReadLegacyPostalCode(t *testing.T) {
+	input := "title,location.postal_code\nold,75001\n"
+	out, errs, err := ReadCSV[*model.Event](strings.NewReader(input))
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("ReadCSV: %v %v", err, errs)
+	}
+	if got := out[0].GetLocation().GetPostcode(); got != "75001" {
+		t.Errorf("postcode = %q, want 75001", got)
+	}
+
+	back, errs, err := ReadNDJSON[*model.Event](strings.NewReader(`{"title":"old","location":{"postal_code":10115}}`))
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("ReadNDJSON: %v %v", err, errs)
+	}
+	if l := back[0].GetLocation(); l.GetPostcode() != "10115" || l.GetPostalCode() != 10115 {
+		t.Errorf("location = %v, want postcode 10115", l)
+	}
+}
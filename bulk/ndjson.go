@@ -0,0 +1,60 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxLine bounds the length of one NDJSON line.
+const maxLine = 16 << 20
+
+// ReadNDJSON decodes one message of type T per non-blank line. Both proto
+// and JSON field names are accepted and unknown fields are ignored.
+func ReadNDJSON[T proto.Message](r io.Reader, opts ...Option) ([]T, []*RowError, error) {
+	c := newConfig(opts)
+	dec := protojson.UnmarshalOptions{DiscardUnknown: true}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxLine)
+	var out []T
+	var errs []*RowError
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		m := newMessage[T]()
+		if err := dec.Unmarshal(b, m); err != nil {
+			errs = append(errs, &RowError{Row: line, Err: err})
+			continue
+		}
+		syncPostcodes(m.ProtoReflect())
+		if err := c.check(m); err != nil {
+			errs = append(errs, &RowError{Row: line, Err: err})
+			continue
+		}
+		out = append(out, m)
+	}
+	return out, errs, sc.Err()
+}
+
+// WriteNDJSON encodes each message on its own line using proto field names,
+// leaving out deprecated fields.
+func WriteNDJSON[T proto.Message](w io.Writer, ms []T) error {
+	enc := protojson.MarshalOptions{UseProtoNames: true}
+	bw := bufio.NewWriter(w)
+	for _, m := range ms {
+		m = proto.Clone(m).(T)
+		clearDeprecated(m.ProtoReflect())
+		b, err := enc.Marshal(m)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
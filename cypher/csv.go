@@ -0,0 +1,205 @@
+package cypher
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/graph"
+)
+
+// ArrayDelimiter separates list elements in CSV cells, matching the
+// neo4j-admin default.
+const ArrayDelimiter = ";"
+
+// File is one neo4j-admin import file.
+type File struct {
+	Name          string
+	Relationships bool
+	Data          []byte
+}
+
+// Arg returns the neo4j-admin database import argument loading f.
+func (f File) Arg() string {
+	if f.Relationships {
+		return "--relationships=" + f.Name
+	}
+	return "--nodes=" + f.Name
+}
+
+// ImportCSV renders g as neo4j-admin import files: one node file per label
+// with the _key as ID in a per-label ID space, and one relationship file per
+// pair of endpoint labels. Relations whose endpoints are not nodes of g are
+// skipped; a relation without a label is an error.
+func ImportCSV(g graph.Graph) ([]File, error) {
+	nodeRows := map[string]*table{}
+	nodes := map[string]bool{}
+	for _, e := range g.Entities {
+		doc := entity.Unwrap(e)
+		label, ok := nodeLabels[entity.KindOf(doc)]
+		if !ok {
+			continue
+		}
+		h, err := arango.HandleOf(doc)
+		if err != nil {
+			return nil, err
+		}
+		nodes[h.String()] = true
+		t := nodeRows[label]
+		if t == nil {
+			t = newTable(PropKey+":ID("+label+")", ":LABEL")
+			nodeRows[label] = t
+		}
+		t.add([]string{h.Key, label}, properties(doc.ProtoReflect()))
+	}
+
+	relRows := map[[2]string]*table{}
+	for _, r := range g.Relations {
+		if !nodes[r.GetFrom()] || !nodes[r.GetTo()] {
+			continue
+		}
+		typ, err := relationshipType(r)
+		if err != nil {
+			return nil, err
+		}
+		from, _ := arango.ParseHandle(r.GetFrom())
+		to, _ := arango.ParseHandle(r.GetTo())
+		pair := [2]string{collectionLabels[from.Collection], collectionLabels[to.Collection]}
+		t := relRows[pair]
+		if t == nil {
+			t = newTable(":START_ID("+pair[0]+")", ":END_ID("+pair[1]+")", ":TYPE")
+			relRows[pair] = t
+		}
+		props := properties(r.ProtoReflect())
+		if r.GetKey() != "" {
+			props = append(props, property{PropKey, r.GetKey()})
+		}
+		t.add([]string{from.Key, to.Key, typ}, props)
+	}
+
+	var files []File
+	for _, k := range labelOrder {
+		label := nodeLabels[k]
+		if t := nodeRows[label]; t != nil {
+			data, err := t.encode()
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{Name: "nodes_" + strings.ToLower(label) + ".csv", Data: data})
+		}
+	}
+	pairs := make([][2]string, 0, len(relRows))
+	for p := range relRows {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+	})
+	for _, p := range pairs {
+		data, err := relRows[p].encode()
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("relationships_%s_%s.csv", strings.ToLower(p[0]), strings.ToLower(p[1]))
+		files = append(files, File{Name: name, Relationships: true, Data: data})
+	}
+	return files, nil
+}
+
+// table accumulates rows whose property columns are discovered as rows
+// are added.
+type table struct {
+	fixed []string
+	types map[string]string
+	rows  []row
+}
+
+type row struct {
+	fixed []string
+	props map[string]any
+}
+
+func newTable(fixed ...string) *table {
+	return &table{fixed: fixed, types: map[string]string{}}
+}
+
+func (t *table) add(fixed []string, props []property) {
+	r := row{fixed: fixed, props: map[string]any{}}
+	for _, p := range props {
+		typ := csvType(p.value)
+		if old, ok := t.types[p.name]; ok && old != typ {
+			typ = "string"
+		}
+		t.types[p.name] = typ
+		r.props[p.name] = p.value
+	}
+	t.rows = append(t.rows, r)
+}
+
+func (t *table) encode() ([]byte, error) {
+	names := make([]string, 0, len(t.types))
+	for name := range t.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	header := append([]string(nil), t.fixed...)
+	for _, name := range names {
+		header = append(header, name+":"+t.types[name])
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	for _, r := range t.rows {
+		rec := append([]string(nil), r.fixed...)
+		for _, name := range names {
+			v, ok := r.props[name]
+			if !ok {
+				rec = append(rec, "")
+				continue
+			}
+			rec = append(rec, csvValue(v))
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvType(v any) string {
+	switch v.(type) {
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "boolean"
+	case []string:
+		return "string[]"
+	case point:
+		return "point"
+	}
+	return "string"
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ArrayDelimiter)
+	case point:
+		return fmt.Sprintf("{latitude:%s,longitude:%s}", literal(v.lat), literal(v.lon))
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return literal(v)
+}
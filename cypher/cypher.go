@@ -0,0 +1,175 @@
+// Package cypher exports entities and relations to Neo4j, either as
+// idempotent Cypher MERGE statements or as neo4j-admin import CSV files.
+//
+// Nodes are labelled by entity kind (Person, Organization, Website, Source,
+// Event) and identified by their _key in the "key" property, which is
+// unique per label. Relationship types are the upper-cased relation label.
+// All other fields, including the owner, read and write ACL fields, become
+// properties; attributes are flattened into "attributes.<name>"
+// properties and a location adds a WGS-84 "location" point.
+package cypher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+	"github.com/omnsight/omniscent-library/internal/errwriter"
+)
+
+// ErrNoLabel is returned for a relation without a label, which has no
+// relationship type.
+var ErrNoLabel = errors.New("cypher: relation has no label")
+
+// nodeLabels maps entity kinds to Neo4j labels.
+var nodeLabels = map[entity.Kind]string{
+	entity.KindSource:       "Source",
+	entity.KindPerson:       "Person",
+	entity.KindOrganization: "Organization",
+	entity.KindWebsite:      "Website",
+	entity.KindEvent:        "Event",
+}
+
+// labelOrder fixes the output order of labels.
+var labelOrder = []entity.Kind{
+	entity.KindSource,
+	entity.KindPerson,
+	entity.KindOrganization,
+	entity.KindWebsite,
+	entity.KindEvent,
+}
+
+// collectionLabels maps arango collections to Neo4j labels.
+var collectionLabels = map[string]string{
+	arango.SourceCollection:       "Source",
+	arango.PersonCollection:       "Person",
+	arango.OrganizationCollection: "Organization",
+	arango.WebsiteCollection:      "Website",
+	arango.EventCollection:        "Event",
+}
+
+// RelationshipType returns the Neo4j relationship type of a relation label.
+func RelationshipType(label string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, label))
+}
+
+// WriteCypher writes a Cypher script that creates a uniqueness constraint
+// per label, then merges every node and relationship. Running it again
+// updates properties without creating duplicates. Relations whose endpoints
+// are not nodes of g are skipped; a relation without a label is an error.
+func WriteCypher(w io.Writer, g graph.Graph) error {
+	cw := errwriter.New(w)
+	for _, k := range labelOrder {
+		label := nodeLabels[k]
+		cw.Printf("CREATE CONSTRAINT %s_key IF NOT EXISTS FOR (n:%s) REQUIRE n.%s IS UNIQUE;\n",
+			strings.ToLower(label), label, PropKey)
+	}
+	nodes := map[string]bool{}
+	for _, e := range g.Entities {
+		doc := entity.Unwrap(e)
+		label, ok := nodeLabels[entity.KindOf(doc)]
+		if !ok {
+			continue
+		}
+		h, err := arango.HandleOf(doc)
+		if err != nil {
+			return err
+		}
+		nodes[h.String()] = true
+		cw.Printf("MERGE (n:%s {%s: %s})", label, PropKey, literal(h.Key))
+		if props := properties(doc.ProtoReflect()); len(props) > 0 {
+			cw.Printf(" SET n += %s", mapLiteral(props))
+		}
+		cw.Printf(";\n")
+	}
+	for _, r := range g.Relations {
+		if !nodes[r.GetFrom()] || !nodes[r.GetTo()] {
+			continue
+		}
+		typ, err := relationshipType(r)
+		if err != nil {
+			return err
+		}
+		from, _ := arango.ParseHandle(r.GetFrom())
+		to, _ := arango.ParseHandle(r.GetTo())
+		cw.Printf("MATCH (a:%s {%s: %s}), (b:%s {%s: %s}) ",
+			collectionLabels[from.Collection], PropKey, literal(from.Key),
+			collectionLabels[to.Collection], PropKey, literal(to.Key))
+		identity := ""
+		if r.GetKey() != "" {
+			identity = fmt.Sprintf(" {%s: %s}", PropKey, literal(r.GetKey()))
+		}
+		cw.Printf("MERGE (a)-[r:%s%s]->(b)", quoteName(typ), identity)
+		if props := properties(r.ProtoReflect()); len(props) > 0 {
+			cw.Printf(" SET r += %s", mapLiteral(props))
+		}
+		cw.Printf(";\n")
+	}
+	return cw.Err()
+}
+
+// relationshipType returns the relationship type of r, or ErrNoLabel.
+func relationshipType(r *model.Relation) (string, error) {
+	if r.GetLabel() == "" {
+		return "", fmt.Errorf("%w: %s -> %s", ErrNoLabel, r.GetFrom(), r.GetTo())
+	}
+	return RelationshipType(r.GetLabel()), nil
+}
+
+func mapLiteral(props []property) string {
+	parts := make([]string, len(props))
+	for i, p := range props {
+		parts[i] = quoteName(p.name) + ": " + literal(p.value)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// literal formats a property value as a Cypher literal.
+func literal(v any) string {
+	switch v := v.(type) {
+	case string:
+		return quote(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		parts := make([]string, len(v))
+		for i, s := range v {
+			parts[i] = quote(s)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case point:
+		return fmt.Sprintf("point({latitude: %s, longitude: %s})", literal(v.lat), literal(v.lon))
+	}
+	return "null"
+}
+
+var quoter = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quote(s string) string {
+	return "'" + quoter.Replace(s) + "'"
+}
+
+// quoteName backtick-quotes identifiers that are not plain words.
+func quoteName(name string) string {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+	}
+	return name
+}
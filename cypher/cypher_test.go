@@ -0,0 +1,109 @@
+package cypher
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func mustStruct(m map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var goldenGraphs = map[string]graph.Graph{
+	"basic": {
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Person{Key: "p1", Name: "Ada Lovelace", Role: "analyst", Tags: []string{"vip", "uk"}}),
+			entity.MustNew(&model.Organization{Key: "o1", Name: "Acme", Type: "company", FoundedAt: 946684800}),
+			entity.MustNew(&model.Event{
+				Key:        "e1",
+				Title:      "Meeting",
+				HappenedAt: 1700000000,
+				Location:   &model.LocationData{Latitude: 51.5074, Longitude: -0.1278, Locality: "London", CountryCode: "GB"},
+				Attributes: mustStruct(map[string]any{"attendees": 3, "public": false}),
+			}),
+		},
+		Relations: []*model.Relation{
+			{Key: "r1", From: "persons/p1", To: "organizations/o1", Label: "member_of", Confidence: 80},
+			{From: "persons/p1", To: "events/e1", Label: "participated_in"},
+			{From: "persons/p1", To: "websites/missing", Label: "related_to"},
+		},
+	},
+	"quoting": {
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Website{Key: "w-1", Url: "https://example.com/it's", Title: "Line one\nline two", Description: `back\slash`}),
+			entity.MustNew(&model.Source{Key: "s:1", Name: "Tab\there", Reliability: 2}),
+		},
+		Relations: []*model.Relation{
+			{From: "websites/w-1", To: "sources/s:1", Label: "reported by", Name: "O'Brien"},
+			{From: "sources/s:1", To: "websites/w-1", Label: "cites-page"},
+		},
+	},
+}
+
+func TestWriteCypherGolden(t *testing.T) {
+	for name, g := range goldenGraphs {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCypher(&buf, g); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", name+".cypher")
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("WriteCypher output differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestNoLabel(t *testing.T) {
+	g := graph.Graph{
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Person{Key: "p1"}),
+			entity.MustNew(&model.Organization{Key: "o1"}),
+		},
+		Relations: []*model.Relation{{From: "persons/p1", To: "organizations/o1"}},
+	}
+	if err := WriteCypher(&bytes.Buffer{}, g); !errors.Is(err, ErrNoLabel) {
+		t.Errorf("WriteCypher error = %v, want ErrNoLabel", err)
+	}
+	if _, err := ImportCSV(g); !errors.Is(err, ErrNoLabel) {
+		t.Errorf("ImportCSV error = %v, want ErrNoLabel", err)
+	}
+}
+
+func TestRelationshipType(t *testing.T) {
+	tests := []struct{ label, want string }{
+		{"member_of", "MEMBER_OF"},
+		{"reported by", "REPORTED_BY"},
+		{"cites-page", "CITES_PAGE"},
+	}
+	for _, tt := range tests {
+		if got := RelationshipType(tt.label); got != tt.want {
+			t.Errorf("RelationshipType(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cypher
+
+import (
+	"encoding/json"
+	"sort"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/floatconv"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// property is one flattened node or relationship property.
+type property struct {
+	name  string
+	value any // string, int64, float64, bool or []string
+}
+
+// Property names with special meaning.
+const (
+	PropKey      = "key"
+	PropLocation = "location"
+)
+
+// skipped fields are mapped to the node identity or the relationship
+// endpoints instead of properties.
+var skipped = map[protoreflect.Name]bool{
+	"id": true, "key": true, "rev": true, "from": true, "to": true, "label": true,
+}
+
+// properties flattens the populated fields of m. Nested messages are
+// prefixed with their field name, attributes are expanded into
+// "attributes.<name>" properties, and a location also yields a "location"
+// point holding its coordinates.
+func properties(m protoreflect.Message) []property {
+	var props []property
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if skipped[fd.Name()] {
+			return true
+		}
+		props = append(props, flatten(fd, v, string(fd.Name()))...)
+		return true
+	})
+	sort.Slice(props, func(i, j int) bool { return props[i].name < props[j].name })
+	return props
+}
+
+func flatten(fd protoreflect.FieldDescriptor, v protoreflect.Value, path string) []property {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		out := make([]string, list.Len())
+		for i := range out {
+			out[i] = list.Get(i).String()
+		}
+		return []property{{path, out}}
+	case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Struct":
+		return structProperties(path, v.Message().Interface().(*structpb.Struct).AsMap())
+	case fd.Message() != nil:
+		var out []property
+		if loc, ok := v.Message().Interface().(*model.LocationData); ok {
+			out = append(out, property{path, point{lat: floatconv.Widen(loc.GetLatitude()), lon: floatconv.Widen(loc.GetLongitude())}})
+		}
+		v.Message().Range(func(sub protoreflect.FieldDescriptor, sv protoreflect.Value) bool {
+			out = append(out, flatten(sub, sv, path+"."+string(sub.Name()))...)
+			return true
+		})
+		return out
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return []property{{path, v.Bool()}}
+	case protoreflect.FloatKind:
+		return []property{{path, floatconv.Widen(float32(v.Float()))}}
+	case protoreflect.DoubleKind:
+		return []property{{path, v.Float()}}
+	case protoreflect.StringKind:
+		return []property{{path, v.String()}}
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return []property{{path, string(ev.Name())}}
+		}
+		return []property{{path, int64(v.Enum())}}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return []property{{path, int64(v.Uint())}}
+	}
+	return []property{{path, v.Int()}}
+}
+
+// structProperties expands a JSON object into dotted properties. Lists of
+// strings are kept as lists; other lists are stored as JSON text.
+func structProperties(prefix string, m map[string]any) []property {
+	var out []property
+	for k, v := range m {
+		path := prefix + "." + k
+		switch v := v.(type) {
+		case map[string]any:
+			out = append(out, structProperties(path, v)...)
+		case []any:
+			if ss, ok := stringList(v); ok {
+				out = append(out, property{path, ss})
+			} else {
+				b, _ := json.Marshal(v)
+				out = append(out, property{path, string(b)})
+			}
+		case float64, string, bool:
+			out = append(out, property{path, v})
+		}
+	}
+	return out
+}
+
+func stringList(l []any) ([]string, bool) {
+	out := make([]string, len(l))
+	for i, v := range l {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		out[i] = s
+	}
+	return out, true
+}
+
+// point is a WGS-84 Neo4j point.
+type point struct {
+	lat, lon float64
+}
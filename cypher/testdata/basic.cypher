@@ -0,0 +1,10 @@
+CREATE CONSTRAINT source_key IF NOT EXISTS FOR (n:Source) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT person_key IF NOT EXISTS FOR (n:Person) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT organization_key IF NOT EXISTS FOR (n:Organization) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT website_key IF NOT EXISTS FOR (n:Website) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT event_key IF NOT EXISTS FOR (n:Event) REQUIRE n.key IS UNIQUE;
+MERGE (n:Person {key: 'p1'}) SET n += {name: 'Ada Lovelace', role: 'analyst', tags: ['vip', 'uk']};
+MERGE (n:Organization {key: 'o1'}) SET n += {founded_at: 946684800, name: 'Acme', type: 'company'};
+MERGE (n:Event {key: 'e1'}) SET n += {`attributes.attendees`: 3, `attributes.public`: false, happened_at: 1700000000, location: point({latitude: 51.5074, longitude: -0.1278}), `location.country_code`: 'GB', `location.latitude`: 51.5074, `location.locality`: 'London', `location.longitude`: -0.1278, title: 'Meeting'};
+MATCH (a:Person {key: 'p1'}), (b:Organization {key: 'o1'}) MERGE (a)-[r:MEMBER_OF {key: 'r1'}]->(b) SET r += {confidence: 80};
+MATCH (a:Person {key: 'p1'}), (b:Event {key: 'e1'}) MERGE (a)-[r:PARTICIPATED_IN]->(b);
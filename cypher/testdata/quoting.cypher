@@ -0,0 +1,9 @@
+CREATE CONSTRAINT source_key IF NOT EXISTS FOR (n:Source) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT person_key IF NOT EXISTS FOR (n:Person) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT organization_key IF NOT EXISTS FOR (n:Organization) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT website_key IF NOT EXISTS FOR (n:Website) REQUIRE n.key IS UNIQUE;
+CREATE CONSTRAINT event_key IF NOT EXISTS FOR (n:Event) REQUIRE n.key IS UNIQUE;
+MERGE (n:Website {key: 'w-1'}) SET n += {description: 'back\\slash', title: 'Line one\nline two', url: 'https://example.com/it\'s'};
+MERGE (n:Source {key: 's:1'}) SET n += {name: 'Tab\there', reliability: 2};
+MATCH (a:Website {key: 'w-1'}), (b:Source {key: 's:1'}) MERGE (a)-[r:REPORTED_BY]->(b) SET r += {name: 'O\'Brien'};
+MATCH (a:Source {key: 's:1'}), (b:Website {key: 'w-1'}) MERGE (a)-[r:CITES_PAGE]->(b);
@@ -0,0 +1,197 @@
+// Package edge defines the typed schema of Relation labels: which entity
+// collections a label may connect, in which direction, and its inverse.
+//
+// Registering a kind also registers its endpoints with the arango package,
+// so arango.CheckRelation accepts every relation this package can build.
+// It may accept more, as other packages can register endpoints there too.
+package edge
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/omnsight/omniscent-library/arango"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrUnknownKind  = errors.New("edge: unknown relation kind")
+	ErrInvalidKind  = errors.New("edge: invalid relation kind")
+	ErrEndpointType = errors.New("edge: endpoints do not fit relation kind")
+)
+
+// Kind describes one relation label. From and To hold the arango collection
+// names of the allowed source and target entities. An undirected kind
+// accepts its endpoints in either order and is its own inverse.
+type Kind struct {
+	Label       string
+	Description string
+	From        []string
+	To          []string
+	Directed    bool
+	// Inverse is the label of the same relation read from To to From.
+	Inverse string
+	// InverseDescription describes the inverse kind Register adds for a
+	// directed kind. If empty, one is derived from Description.
+	InverseDescription string
+}
+
+// Allows reports whether a relation of kind k may go from the collection
+// from to the collection to.
+func (k Kind) Allows(from, to string) bool {
+	if slices.Contains(k.From, from) && slices.Contains(k.To, to) {
+		return true
+	}
+	return !k.Directed && slices.Contains(k.From, to) && slices.Contains(k.To, from)
+}
+
+var (
+	mu    sync.RWMutex
+	kinds = map[string]Kind{}
+)
+
+// Register adds k to the registry. For a directed kind with an Inverse
+// label that is not yet registered, the inverse kind is registered too.
+func Register(k Kind) error {
+	if k.Label == "" || len(k.From) == 0 || len(k.To) == 0 {
+		return fmt.Errorf("%w: label and endpoints are required", ErrInvalidKind)
+	}
+	if !k.Directed {
+		if k.Inverse != "" && k.Inverse != k.Label {
+			return fmt.Errorf("%w: undirected %q cannot have inverse %q", ErrInvalidKind, k.Label, k.Inverse)
+		}
+		k.Inverse = k.Label
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := kinds[k.Label]; ok {
+		return fmt.Errorf("%w: %q already registered", ErrInvalidKind, k.Label)
+	}
+	if inv, ok := kinds[k.Inverse]; ok && k.Directed && inv.Inverse != k.Label {
+		return fmt.Errorf("%w: %q is the inverse of %q, not %q", ErrInvalidKind, k.Inverse, inv.Inverse, k.Label)
+	}
+	add(k)
+	if _, ok := kinds[k.Inverse]; !ok && k.Inverse != "" {
+		desc := k.InverseDescription
+		if desc == "" {
+			desc = fmt.Sprintf("inverse of %s: %s", k.Label, k.Description)
+		}
+		add(Kind{
+			Label:       k.Inverse,
+			Description: desc,
+			From:        k.To,
+			To:          k.From,
+			Directed:    true,
+			Inverse:     k.Label,
+		})
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func MustRegister(k Kind) {
+	if err := Register(k); err != nil {
+		panic(err)
+	}
+}
+
+func add(k Kind) {
+	kinds[k.Label] = k
+	arango.AllowEndpoints(k.Label, k.From, k.To)
+	if !k.Directed {
+		arango.AllowEndpoints(k.Label, k.To, k.From)
+	}
+}
+
+// Lookup returns the kind registered under label.
+func Lookup(label string) (Kind, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	k, ok := kinds[label]
+	return k, ok
+}
+
+// Kinds returns all registered kinds sorted by label.
+func Kinds() []Kind {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Kind, 0, len(kinds))
+	for _, k := range kinds {
+		out = append(out, k)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Label < out[j].Label })
+	return out
+}
+
+// New builds a Relation with the given label between two entities. It fails
+// if the label is unknown, the entity types do not fit the kind, or an
+// entity's _id names a collection other than that of its type.
+func New(label string, from, to *model.Entity) (*model.Relation, error) {
+	k, ok := Lookup(label)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, label)
+	}
+	fh, err := endpoint(from)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	th, err := endpoint(to)
+	if err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	if !k.Allows(fh.Collection, th.Collection) {
+		return nil, fmt.Errorf("%w: %s %q %s", ErrEndpointType, fh.Collection, label, th.Collection)
+	}
+	return &model.Relation{
+		From:  fh.String(),
+		To:    th.String(),
+		Label: label,
+	}, nil
+}
+
+// endpoint returns the handle of e after checking that it lies in the
+// collection of the message e wraps.
+func endpoint(e *model.Entity) (arango.Handle, error) {
+	collection, err := arango.CollectionOf(e)
+	if err != nil {
+		return arango.Handle{}, err
+	}
+	h, err := arango.HandleOf(e)
+	if err != nil {
+		return arango.Handle{}, err
+	}
+	if h.Collection != collection {
+		return arango.Handle{}, fmt.Errorf("%w: %s is not in %s", arango.ErrInvalidHandle, h, collection)
+	}
+	return h, nil
+}
+
+// Inverse returns a copy of r read in the opposite direction: endpoints are
+// swapped and the label replaced by the inverse label. Document identity
+// fields (_id, _key, _rev) are cleared.
+func Inverse(r *model.Relation) (*model.Relation, error) {
+	k, ok := Lookup(r.GetLabel())
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, r.GetLabel())
+	}
+	if k.Inverse == "" {
+		return nil, fmt.Errorf("%w: %q has no inverse", ErrInvalidKind, k.Label)
+	}
+	return &model.Relation{
+		From:       r.GetTo(),
+		To:         r.GetFrom(),
+		Owner:      r.GetOwner(),
+		Read:       slices.Clone(r.GetRead()),
+		Write:      slices.Clone(r.GetWrite()),
+		Name:       r.GetName(),
+		Confidence: r.GetConfidence(),
+		Label:      k.Inverse,
+		CreatedAt:  r.GetCreatedAt(),
+		UpdatedAt:  r.GetUpdatedAt(),
+		Attributes: proto.CloneOf(r.GetAttributes()),
+	}, nil
+}
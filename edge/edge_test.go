@@ -0,0 +1,88 @@
+package edge
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/arango"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestNew(t *testing.T) {
+	person := &model.Entity{Entity: &model.Entity_Person{Person: &model.Person{Key: "p"}}}
+	org := &model.Entity{Entity: &model.Entity_Organization{Organization: &model.Organization{Key: "o"}}}
+	disguised := &model.Entity{Entity: &model.Entity_Event{Event: &model.Event{Id: "persons/x"}}}
+
+	r, err := New(MemberOf, person, org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetFrom() != "persons/p" || r.GetTo() != "organizations/o" {
+		t.Errorf("New = %v", r)
+	}
+	if _, err := New(MemberOf, org, person); !errors.Is(err, ErrEndpointType) {
+		t.Errorf("reversed endpoints: err = %v, want ErrEndpointType", err)
+	}
+	if _, err := New(MemberOf, disguised, org); !errors.Is(err, arango.ErrInvalidHandle) {
+		t.Errorf("event with person _id: err = %v, want ErrInvalidHandle", err)
+	}
+	if _, err := New("born_in", person, org); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("unknown label: err = %v, want ErrUnknownKind", err)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	attrs, _ := structpb.NewStruct(map[string]any{"role": "cfo"})
+	r := &model.Relation{Key: "r", From: "persons/p", To: "organizations/o", Label: MemberOf, Attributes: attrs}
+	inv, err := Inverse(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.GetLabel() != HasMember || inv.GetFrom() != r.GetTo() || inv.GetTo() != r.GetFrom() || inv.GetKey() != "" {
+		t.Errorf("Inverse = %v", inv)
+	}
+	inv.GetAttributes().Fields["role"] = structpb.NewStringValue("ceo")
+	if got := r.GetAttributes().GetFields()["role"].GetStringValue(); got != "cfo" {
+		t.Errorf("changing the inverse changed the original attributes to %q", got)
+	}
+	if _, err := Inverse(&model.Relation{Label: MemberOf}); err != nil {
+		t.Errorf("Inverse without attributes: %v", err)
+	}
+}
+
+func TestInverseDescription(t *testing.T) {
+	MustRegister(Kind{
+		Label:              "test_employs",
+		Description:        "an organization employs a person",
+		InverseDescription: "a person works for an organization",
+		From:               []string{arango.OrganizationCollection},
+		To:                 []string{arango.PersonCollection},
+		Directed:           true,
+		Inverse:            "test_employed_by",
+	})
+	MustRegister(Kind{
+		Label:       "test_funds",
+		Description: "an organization funds an event",
+		From:        []string{arango.OrganizationCollection},
+		To:          []string{arango.EventCollection},
+		Directed:    true,
+		Inverse:     "test_funded_by",
+	})
+	tests := []struct {
+		label, want string
+	}{
+		{"test_employed_by", "a person works for an organization"},
+		{"test_funded_by", "inverse of test_funds: an organization funds an event"},
+		{HasMember, "an organization counts a person or organization among its members"},
+	}
+	for _, tt := range tests {
+		k, ok := Lookup(tt.label)
+		if !ok {
+			t.Fatalf("%s not registered", tt.label)
+		}
+		if k.Description != tt.want {
+			t.Errorf("Lookup(%q).Description = %q, want %q", tt.label, k.Description, tt.want)
+		}
+	}
+}
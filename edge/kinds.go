@@ -0,0 +1,94 @@
+package edge
+
+import "github.com/omnsight/omniscent-library/arango"
+
+// Labels of the built-in relation kinds.
+const (
+	MemberOf       = "member_of"
+	HasMember      = "has_member"
+	LocatedAt      = "located_at"
+	LocationOf     = "location_of"
+	ReportedBy     = "reported_by"
+	Reports        = "reports"
+	OwnsDomain     = "owns_domain"
+	DomainOwnedBy  = "domain_owned_by"
+	ParticipatedIn = "participated_in"
+	HasParticipant = "has_participant"
+	Mentions       = "mentions"
+	MentionedIn    = "mentioned_in"
+	RelatedTo      = "related_to"
+)
+
+var (
+	actors   = []string{arango.PersonCollection, arango.OrganizationCollection}
+	entities = []string{
+		arango.SourceCollection,
+		arango.PersonCollection,
+		arango.OrganizationCollection,
+		arango.WebsiteCollection,
+		arango.EventCollection,
+	}
+)
+
+func init() {
+	MustRegister(Kind{
+		Label:              MemberOf,
+		Description:        "a person or organization belongs to an organization",
+		InverseDescription: "an organization counts a person or organization among its members",
+		From:               actors,
+		To:                 []string{arango.OrganizationCollection},
+		Directed:           true,
+		Inverse:            HasMember,
+	})
+	MustRegister(Kind{
+		Label:              LocatedAt,
+		Description:        "an actor or event is sited at premises run by an organization",
+		InverseDescription: "an organization runs the premises where an actor or event is sited",
+		From:               []string{arango.PersonCollection, arango.OrganizationCollection, arango.EventCollection},
+		To:                 []string{arango.OrganizationCollection},
+		Directed:           true,
+		Inverse:            LocationOf,
+	})
+	MustRegister(Kind{
+		Label:              ReportedBy,
+		Description:        "information about an entity comes from a source",
+		InverseDescription: "a source provides information about an entity",
+		From:               []string{arango.PersonCollection, arango.OrganizationCollection, arango.WebsiteCollection, arango.EventCollection},
+		To:                 []string{arango.SourceCollection},
+		Directed:           true,
+		Inverse:            Reports,
+	})
+	MustRegister(Kind{
+		Label:              OwnsDomain,
+		Description:        "an actor owns or operates a website",
+		InverseDescription: "a website is owned or operated by an actor",
+		From:               actors,
+		To:                 []string{arango.WebsiteCollection},
+		Directed:           true,
+		Inverse:            DomainOwnedBy,
+	})
+	MustRegister(Kind{
+		Label:              ParticipatedIn,
+		Description:        "an actor took part in an event",
+		InverseDescription: "an event had an actor among its participants",
+		From:               actors,
+		To:                 []string{arango.EventCollection},
+		Directed:           true,
+		Inverse:            HasParticipant,
+	})
+	MustRegister(Kind{
+		Label:              Mentions,
+		Description:        "a source or website refers to an entity",
+		InverseDescription: "an entity is referred to by a source or website",
+		From:               []string{arango.SourceCollection, arango.WebsiteCollection},
+		To:                 []string{arango.PersonCollection, arango.OrganizationCollection, arango.WebsiteCollection, arango.EventCollection},
+		Directed:           true,
+		Inverse:            MentionedIn,
+	})
+	MustRegister(Kind{
+		Label:       RelatedTo,
+		Description: "an unspecified association between two entities",
+		From:        entities,
+		To:          entities,
+	})
+}
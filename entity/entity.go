@@ -0,0 +1,220 @@
+// Package entity provides a uniform API over the model.Entity oneof and the
+// document messages it wraps.
+package entity
+
+import (
+	"fmt"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Document is implemented by every OSINT document message, Relation
+// included, so generic code can be written once against it.
+type Document interface {
+	proto.Message
+	GetId() string
+	GetKey() string
+	GetRev() string
+	GetOwner() string
+	GetRead() []string
+	GetWrite() []string
+	GetAttributes() *structpb.Struct
+}
+
+// Tagged is implemented by the five messages that may be wrapped in an
+// Entity.
+type Tagged interface {
+	Document
+	GetTags() []string
+}
+
+var (
+	_ Tagged   = (*model.Source)(nil)
+	_ Tagged   = (*model.Person)(nil)
+	_ Tagged   = (*model.Organization)(nil)
+	_ Tagged   = (*model.Website)(nil)
+	_ Tagged   = (*model.Event)(nil)
+	_ Document = (*model.Relation)(nil)
+)
+
+// Kind identifies the message type of a document.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindSource
+	KindPerson
+	KindOrganization
+	KindWebsite
+	KindEvent
+	KindRelation
+)
+
+var kindNames = [...]string{
+	KindUnknown:      "unknown",
+	KindSource:       "source",
+	KindPerson:       "person",
+	KindOrganization: "organization",
+	KindWebsite:      "website",
+	KindEvent:        "event",
+	KindRelation:     "relation",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// ParseKind returns the Kind named s, as produced by Kind.String.
+func ParseKind(s string) (Kind, bool) {
+	for k, name := range kindNames {
+		if name == s && k != int(KindUnknown) {
+			return Kind(k), true
+		}
+	}
+	return KindUnknown, false
+}
+
+// KindOf returns the kind of m. An Entity reports the kind of the message
+// set in its oneof.
+func KindOf(m proto.Message) Kind {
+	switch v := m.(type) {
+	case *model.Source:
+		return KindSource
+	case *model.Person:
+		return KindPerson
+	case *model.Organization:
+		return KindOrganization
+	case *model.Website:
+		return KindWebsite
+	case *model.Event:
+		return KindEvent
+	case *model.Relation:
+		return KindRelation
+	case *model.Entity:
+		if d := Unwrap(v); d != nil {
+			return KindOf(d)
+		}
+	}
+	return KindUnknown
+}
+
+// New wraps a Source, Person, Organization, Website or Event in an Entity.
+// An Entity is returned unchanged.
+func New(m proto.Message) (*model.Entity, error) {
+	switch v := m.(type) {
+	case *model.Source:
+		return &model.Entity{Entity: &model.Entity_Source{Source: v}}, nil
+	case *model.Person:
+		return &model.Entity{Entity: &model.Entity_Person{Person: v}}, nil
+	case *model.Organization:
+		return &model.Entity{Entity: &model.Entity_Organization{Organization: v}}, nil
+	case *model.Website:
+		return &model.Entity{Entity: &model.Entity_Website{Website: v}}, nil
+	case *model.Event:
+		return &model.Entity{Entity: &model.Entity_Event{Event: v}}, nil
+	case *model.Entity:
+		return v, nil
+	}
+	return nil, fmt.Errorf("entity: %T cannot be wrapped in an Entity", m)
+}
+
+// MustNew is like New but panics on error.
+func MustNew(m proto.Message) *model.Entity {
+	e, err := New(m)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Unwrap returns the message set in e's oneof, or nil if none is set. A
+// case wrapping a nil message also yields nil, never a typed nil.
+func Unwrap(e *model.Entity) Tagged {
+	switch v := e.GetEntity().(type) {
+	case *model.Entity_Source:
+		if v.Source != nil {
+			return v.Source
+		}
+	case *model.Entity_Person:
+		if v.Person != nil {
+			return v.Person
+		}
+	case *model.Entity_Organization:
+		if v.Organization != nil {
+			return v.Organization
+		}
+	case *model.Entity_Website:
+		if v.Website != nil {
+			return v.Website
+		}
+	case *model.Entity_Event:
+		if v.Event != nil {
+			return v.Event
+		}
+	}
+	return nil
+}
+
+// ID returns the _id of the wrapped document.
+func ID(e *model.Entity) string {
+	if d := Unwrap(e); d != nil {
+		return d.GetId()
+	}
+	return ""
+}
+
+// Key returns the _key of the wrapped document.
+func Key(e *model.Entity) string {
+	if d := Unwrap(e); d != nil {
+		return d.GetKey()
+	}
+	return ""
+}
+
+// Owner returns the owner of the wrapped document.
+func Owner(e *model.Entity) string {
+	if d := Unwrap(e); d != nil {
+		return d.GetOwner()
+	}
+	return ""
+}
+
+// Tags returns the tags of the wrapped document.
+func Tags(e *model.Entity) []string {
+	if d := Unwrap(e); d != nil {
+		return d.GetTags()
+	}
+	return nil
+}
+
+// Attributes returns the attributes of the wrapped document.
+func Attributes(e *model.Entity) *structpb.Struct {
+	if d := Unwrap(e); d != nil {
+		return d.GetAttributes()
+	}
+	return nil
+}
+
+// UpdatedAt returns the last modification time of the wrapped document.
+// Organization and Website have no updated_at; for them the later of
+// discovered_at and last_visited is returned.
+func UpdatedAt(e *model.Entity) int64 {
+	switch v := e.GetEntity().(type) {
+	case *model.Entity_Source:
+		return v.Source.GetUpdatedAt()
+	case *model.Entity_Person:
+		return v.Person.GetUpdatedAt()
+	case *model.Entity_Organization:
+		return max(v.Organization.GetDiscoveredAt(), v.Organization.GetLastVisited())
+	case *model.Entity_Website:
+		return max(v.Website.GetDiscoveredAt(), v.Website.GetLastVisited())
+	case *model.Entity_Event:
+		return v.Event.GetUpdatedAt()
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		m    proto.Message
+		want Kind
+	}{
+		{&model.Source{}, KindSource},
+		{&model.Person{}, KindPerson},
+		{&model.Organization{}, KindOrganization},
+		{&model.Website{}, KindWebsite},
+		{&model.Event{}, KindEvent},
+		{&model.Relation{}, KindRelation},
+		{MustNew(&model.Website{}), KindWebsite},
+		{&model.Entity{}, KindUnknown},
+		{&model.Entity{Entity: &model.Entity_Person{}}, KindUnknown},
+		{&model.LocationData{}, KindUnknown},
+		{nil, KindUnknown},
+	}
+	for _, tt := range tests {
+		if got := KindOf(tt.m); got != tt.want {
+			t.Errorf("KindOf(%v) = %v, want %v", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for k := KindSource; k <= KindRelation; k++ {
+		if got, ok := ParseKind(k.String()); !ok || got != k {
+			t.Errorf("ParseKind(%q) = %v, %v, want %v", k.String(), got, ok, k)
+		}
+	}
+	for _, s := range []string{"unknown", "Person", ""} {
+		if got, ok := ParseKind(s); ok {
+			t.Errorf("ParseKind(%q) = %v, want not ok", s, got)
+		}
+	}
+	if got := Kind(42).String(); got != "Kind(42)" {
+		t.Errorf("Kind(42).String() = %q", got)
+	}
+}
+
+func TestNewUnwrap(t *testing.T) {
+	docs := []Tagged{
+		&model.Source{Key: "s"},
+		&model.Person{Key: "p"},
+		&model.Organization{Key: "o"},
+		&model.Website{Key: "w"},
+		&model.Event{Key: "e"},
+	}
+	for _, d := range docs {
+		e, err := New(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Unwrap(e); got != d {
+			t.Errorf("Unwrap(New(%v)) = %v", d, got)
+		}
+		if again := MustNew(e); again != e {
+			t.Errorf("New(entity) did not return it unchanged")
+		}
+	}
+	if _, err := New(&model.Relation{}); err == nil {
+		t.Error("New(Relation) succeeded")
+	}
+}
+
+func TestUnwrapNil(t *testing.T) {
+	tests := []*model.Entity{
+		nil,
+		{},
+		{Entity: &model.Entity_Source{}},
+		{Entity: &model.Entity_Person{}},
+		{Entity: &model.Entity_Organization{}},
+		{Entity: &model.Entity_Website{}},
+		{Entity: &model.Entity_Event{}},
+	}
+	for _, e := range tests {
+		if d := Unwrap(e); d != nil {
+			t.Errorf("Unwrap(%v) = %#v, want nil", e, d)
+		}
+		if ID(e) != "" || Key(e) != "" || Owner(e) != "" || Tags(e) != nil || Attributes(e) != nil || UpdatedAt(e) != 0 {
+			t.Errorf("accessors of %v are not empty", e)
+		}
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	attrs, _ := structpb.NewStruct(map[string]any{"k": "v"})
+	e := MustNew(&model.Person{Id: "persons/p", Key: "p", Owner: "alice", Tags: []string{"a"}, Attributes: attrs, UpdatedAt: 5})
+	if ID(e) != "persons/p" || Key(e) != "p" || Owner(e) != "alice" || len(Tags(e)) != 1 || Attributes(e) != attrs {
+		t.Errorf("accessors of %v", e)
+	}
+	tests := []struct {
+		e    *model.Entity
+		want int64
+	}{
+		{e, 5},
+		{MustNew(&model.Organization{DiscoveredAt: 10, LastVisited: 20}), 20},
+		{MustNew(&model.Website{DiscoveredAt: 30, LastVisited: 20}), 30},
+		{MustNew(&model.Event{UpdatedAt: 7}), 7},
+		{MustNew(&model.Source{UpdatedAt: 8}), 8},
+	}
+	for _, tt := range tests {
+		if got := UpdatedAt(tt.e); got != tt.want {
+			t.Errorf("UpdatedAt(%v) = %d, want %d", tt.e, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package epoch
+
+import (
+	"time"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Documents holding each timestamp field shared by several message types.
+type (
+	withCreatedAt interface {
+		proto.Message
+		GetCreatedAt() int64
+	}
+	withUpdatedAt interface {
+		proto.Message
+		GetUpdatedAt() int64
+	}
+	withFoundedAt interface {
+		proto.Message
+		GetFoundedAt() int64
+	}
+	withDiscoveredAt interface {
+		proto.Message
+		GetDiscoveredAt() int64
+	}
+	withLastVisited interface {
+		proto.Message
+		GetLastVisited() int64
+	}
+)
+
+// HappenedAt returns the happened_at time of e with Default.
+func HappenedAt(e *model.Event) time.Time { return get(e, "happened_at") }
+
+// SetHappenedAt stores t in the happened_at field of e with Default.
+func SetHappenedAt(e *model.Event, t time.Time) { set(e, "happened_at", t) }
+
+// BirthDate returns the birth_date time of p with Default.
+func BirthDate(p *model.Person) time.Time { return get(p, "birth_date") }
+
+// SetBirthDate stores t in the birth_date field of p with Default.
+func SetBirthDate(p *model.Person, t time.Time) { set(p, "birth_date", t) }
+
+// CreatedAt returns the created_at time of a Relation or Source with
+// Default.
+func CreatedAt(m withCreatedAt) time.Time { return get(m, "created_at") }
+
+// SetCreatedAt stores t in the created_at field of a Relation or Source
+// with Default.
+func SetCreatedAt(m withCreatedAt, t time.Time) { set(m, "created_at", t) }
+
+// UpdatedAt returns the updated_at time of a Relation, Event, Source or
+// Person with Default.
+func UpdatedAt(m withUpdatedAt) time.Time { return get(m, "updated_at") }
+
+// SetUpdatedAt stores t in the updated_at field of a Relation, Event,
+// Source or Person with Default.
+func SetUpdatedAt(m withUpdatedAt, t time.Time) { set(m, "updated_at", t) }
+
+// FoundedAt returns the founded_at time of an Organization or Website with
+// Default.
+func FoundedAt(m withFoundedAt) time.Time { return get(m, "founded_at") }
+
+// SetFoundedAt stores t in the founded_at field of an Organization or
+// Website with Default.
+func SetFoundedAt(m withFoundedAt, t time.Time) { set(m, "founded_at", t) }
+
+// DiscoveredAt returns the discovered_at time of an Organization or
+// Website with Default.
+func DiscoveredAt(m withDiscoveredAt) time.Time { return get(m, "discovered_at") }
+
+// SetDiscoveredAt stores t in the discovered_at field of an Organization
+// or Website with Default.
+func SetDiscoveredAt(m withDiscoveredAt, t time.Time) { set(m, "discovered_at", t) }
+
+// LastVisited returns the last_visited time of an Organization or Website
+// with Default.
+func LastVisited(m withLastVisited) time.Time { return get(m, "last_visited") }
+
+// SetLastVisited stores t in the last_visited field of an Organization or
+// Website with Default.
+func SetLastVisited(m withLastVisited, t time.Time) { set(m, "last_visited", t) }
+
+// get and set access a timestamp field of m. They panic when m lacks it,
+// which only happens for messages outside the model.
+func get(m proto.Message, field string) time.Time {
+	t, err := Default.Get(m, field)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func set(m proto.Message, field string, t time.Time) {
+	if err := Default.Set(m, field, t); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,178 @@
+// Package epoch converts the int64 timestamp fields of the model to and
+// from time.Time.
+//
+// Timestamp fields are those annotated with (rules).timestamp in the
+// protos; the model stores them as Unix seconds and zero means unset.
+// Older data may hold milliseconds or finer units, so a Converter can be
+// told the unit of a field or detect the unit of values too large to be
+// seconds, and Normalize and Migrate rewrite stored values to a single
+// unit. HappenedAt, SetHappenedAt and their siblings access the timestamp
+// fields of the model with Default.
+package epoch
+
+import (
+	"fmt"
+	"time"
+)
+
+// Unit is the resolution of a stored timestamp.
+type Unit int
+
+const (
+	Seconds Unit = iota
+	Milliseconds
+	Microseconds
+	Nanoseconds
+)
+
+var unitNames = [...]string{"seconds", "milliseconds", "microseconds", "nanoseconds"}
+
+func (u Unit) String() string {
+	if u < 0 || int(u) >= len(unitNames) {
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+	return unitNames[u]
+}
+
+// perSecond returns the number of u in one second.
+func (u Unit) perSecond() int64 {
+	switch u {
+	case Milliseconds:
+		return 1e3
+	case Microseconds:
+		return 1e6
+	case Nanoseconds:
+		return 1e9
+	}
+	return 1
+}
+
+// maxSeconds bounds the magnitude of plausible values in seconds: dates
+// between the years 940 and 3000.
+var maxSeconds = time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+// Detect infers the unit of a non-zero timestamp from its magnitude: it is
+// the coarsest unit in which v falls between the years 940 and 3000. A
+// value that is plausible in seconds is always read as seconds, so dates
+// before 1900 keep their meaning; the price is that values in finer units
+// within about a year of 1970 are read as seconds too. Use WithFieldUnit
+// when a field is known to hold a finer unit. The boolean is false for 0,
+// which has no unit.
+func Detect(v int64) (Unit, bool) {
+	if v == 0 {
+		return Seconds, false
+	}
+	for u := Seconds; u < Nanoseconds; u++ {
+		if s := v / u.perSecond(); -maxSeconds <= s && s <= maxSeconds {
+			return u, true
+		}
+	}
+	return Nanoseconds, true
+}
+
+// Convert rescales v from one unit to another, truncating toward zero when
+// converting to a coarser unit.
+func Convert(v int64, from, to Unit) int64 {
+	f, t := from.perSecond(), to.perSecond()
+	if f > t {
+		return v / (f / t)
+	}
+	return v * (t / f)
+}
+
+// Converter reads and writes timestamps in a configured unit.
+type Converter struct {
+	unit   Unit
+	detect bool
+	fields map[string]Unit
+}
+
+// Option configures a Converter.
+type Option func(*Converter)
+
+// WithUnit sets the unit assumed when reading and used when writing.
+func WithUnit(u Unit) Option {
+	return func(c *Converter) { c.unit = u }
+}
+
+// WithDetection makes the Converter detect the unit of each value it
+// reads, using the configured unit only for values Detect rejects.
+func WithDetection() Option {
+	return func(c *Converter) { c.detect = true }
+}
+
+// WithFieldUnit sets the unit of the named timestamp field in every
+// message type. Get, Set and Normalize read and write the field in u,
+// without detection.
+func WithFieldUnit(field string, u Unit) Option {
+	return func(c *Converter) {
+		if c.fields == nil {
+			c.fields = map[string]Unit{}
+		}
+		c.fields[field] = u
+	}
+}
+
+// New returns a Converter. The default unit is Seconds without detection.
+func New(opts ...Option) *Converter {
+	c := &Converter{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Default reads seconds with detection and writes seconds. It is used by
+// the package-level functions.
+var Default = New(WithDetection())
+
+// UnitOf returns the unit c reads v in.
+func (c *Converter) UnitOf(v int64) Unit {
+	if c.detect {
+		if u, ok := Detect(v); ok {
+			return u
+		}
+	}
+	return c.unit
+}
+
+// fieldUnit returns the unit c reads v of the named field in.
+func (c *Converter) fieldUnit(field string, v int64) Unit {
+	if u, ok := c.fields[field]; ok {
+		return u
+	}
+	return c.UnitOf(v)
+}
+
+// Time returns the time of v, or the zero time if v is 0.
+func (c *Converter) Time(v int64) time.Time {
+	return timeIn(v, c.UnitOf(v))
+}
+
+func timeIn(v int64, u Unit) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	n := u.perSecond()
+	return time.Unix(v/n, (v%n)*(1e9/n)).UTC()
+}
+
+// Unix returns t in c's unit, or 0 for the zero time.
+func (c *Converter) Unix(t time.Time) int64 {
+	return unixIn(t, c.unit)
+}
+
+func unixIn(t time.Time, u Unit) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	switch u {
+	case Milliseconds:
+		return t.UnixMilli()
+	case Microseconds:
+		return t.UnixMicro()
+	case Nanoseconds:
+		return t.UnixNano()
+	}
+	return t.Unix()
+}
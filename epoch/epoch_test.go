@@ -0,0 +1,100 @@
+package epoch
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		v    int64
+		want Unit
+		ok   bool
+	}{
+		{"zero", 0, Seconds, false},
+		{"1850 in seconds", date(1850, 6, 1).Unix(), Seconds, true},
+		{"1899-12-31 in seconds", date(1899, 12, 31).Unix(), Seconds, true},
+		{"1900-01-01 in seconds", date(1900, 1, 1).Unix(), Seconds, true},
+		{"1969-12-31 in seconds", date(1969, 12, 31).Unix(), Seconds, true},
+		{"one second before 1970", -1, Seconds, true},
+		{"one second after 1970", 1, Seconds, true},
+		{"1970-01-02 in seconds", date(1970, 1, 2).Unix(), Seconds, true},
+		{"2024 in seconds", date(2024, 3, 14).Unix(), Seconds, true},
+		{"2999 in seconds", date(2999, 12, 31).Unix(), Seconds, true},
+		{"1850 in milliseconds", date(1850, 6, 1).UnixMilli(), Milliseconds, true},
+		{"1899-12-31 in milliseconds", date(1899, 12, 31).UnixMilli(), Milliseconds, true},
+		{"1900-01-01 in milliseconds", date(1900, 1, 1).UnixMilli(), Milliseconds, true},
+		{"1969-06-01 in milliseconds", date(1969, 6, 1).UnixMilli(), Seconds, true},
+		{"1972 in milliseconds", date(1972, 1, 1).UnixMilli(), Milliseconds, true},
+		{"2024 in milliseconds", date(2024, 3, 14).UnixMilli(), Milliseconds, true},
+		{"2024 in microseconds", date(2024, 3, 14).UnixMicro(), Microseconds, true},
+		{"2024 in nanoseconds", date(2024, 3, 14).UnixNano(), Nanoseconds, true},
+		{"1900 in nanoseconds", date(1900, 1, 1).UnixNano(), Nanoseconds, true},
+	}
+	for _, tt := range tests {
+		u, ok := Detect(tt.v)
+		if u != tt.want || ok != tt.ok {
+			t.Errorf("%s: Detect(%d) = %s, %v, want %s, %v", tt.name, tt.v, u, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTimeKeepsSecondsBefore1900(t *testing.T) {
+	for _, want := range []time.Time{date(1850, 6, 1), date(1899, 12, 31), date(1900, 1, 1), date(1969, 12, 31), date(1970, 1, 1).Add(time.Second)} {
+		if got := Default.Time(want.Unix()); !got.Equal(want) {
+			t.Errorf("Time(%d) = %s, want %s", want.Unix(), got, want)
+		}
+	}
+}
+
+func TestFieldUnit(t *testing.T) {
+	c := New(WithDetection(), WithFieldUnit("happened_at", Milliseconds))
+	at := date(1969, 6, 1)
+	e := &model.Event{HappenedAt: at.UnixMilli(), UpdatedAt: at.Unix()}
+	if got, err := c.Get(e, "happened_at"); err != nil || !got.Equal(at) {
+		t.Errorf("happened_at = %s, %v, want %s", got, err, at)
+	}
+	if got, err := c.Get(e, "updated_at"); err != nil || !got.Equal(at) {
+		t.Errorf("updated_at = %s, %v, want %s", got, err, at)
+	}
+	if err := c.Set(e, "happened_at", at.Add(time.Second)); err != nil || e.HappenedAt != at.UnixMilli()+1000 {
+		t.Errorf("Set stored %d, %v", e.HappenedAt, err)
+	}
+	if !c.Normalize(e, Seconds) || e.HappenedAt != at.Unix()+1 || e.UpdatedAt != at.Unix() {
+		t.Errorf("Normalize = %d, %d", e.HappenedAt, e.UpdatedAt)
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	at := date(1850, 6, 1)
+	e := &model.Event{}
+	SetHappenedAt(e, at)
+	if e.HappenedAt != at.Unix() || !HappenedAt(e).Equal(at) {
+		t.Errorf("happened_at = %d, %s", e.HappenedAt, HappenedAt(e))
+	}
+	p := &model.Person{}
+	SetBirthDate(p, at)
+	SetUpdatedAt(p, at)
+	if !BirthDate(p).Equal(at) || !UpdatedAt(p).Equal(at) {
+		t.Errorf("person = %v", p)
+	}
+	o := &model.Organization{}
+	SetFoundedAt(o, at)
+	SetDiscoveredAt(o, at)
+	SetLastVisited(o, time.Time{})
+	if !FoundedAt(o).Equal(at) || !DiscoveredAt(o).Equal(at) || !LastVisited(o).IsZero() {
+		t.Errorf("organization = %v", o)
+	}
+	r := &model.Relation{}
+	SetCreatedAt(r, at)
+	if !CreatedAt(r).Equal(at) || r.CreatedAt != at.Unix() {
+		t.Errorf("relation = %v", r)
+	}
+}
@@ -0,0 +1,138 @@
+package epoch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrNotTimestamp is returned for fields that are not timestamp fields.
+var ErrNotTimestamp = errors.New("epoch: not a timestamp field")
+
+// IsTimestamp reports whether fd is an int64 field annotated with
+// (rules).timestamp.
+func IsTimestamp(fd protoreflect.FieldDescriptor) bool {
+	if fd.Kind() != protoreflect.Int64Kind || fd.IsList() || fd.IsMap() {
+		return false
+	}
+	opts := fd.Options()
+	if opts == nil || !proto.HasExtension(opts, model.E_Rules) {
+		return false
+	}
+	return proto.GetExtension(opts, model.E_Rules).(*model.FieldRules).GetTimestamp()
+}
+
+// Fields returns the names of the timestamp fields of m's type, in field
+// order. An Entity reports the fields of the message set in its oneof.
+func Fields(m proto.Message) []string {
+	var out []string
+	fields := target(m).Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); IsTimestamp(fd) {
+			out = append(out, string(fd.Name()))
+		}
+	}
+	return out
+}
+
+// Get returns the timestamp field of m as a time with Default.
+func Get(m proto.Message, field string) (time.Time, error) {
+	return Default.Get(m, field)
+}
+
+// Set stores t in the timestamp field of m with Default.
+func Set(m proto.Message, field string, t time.Time) error {
+	return Default.Set(m, field, t)
+}
+
+// Get returns the named timestamp field of m as a time, or the zero time
+// when it is unset.
+func (c *Converter) Get(m proto.Message, field string) (time.Time, error) {
+	pm, fd, err := lookup(m, field)
+	if err != nil {
+		return time.Time{}, err
+	}
+	v := pm.Get(fd).Int()
+	return timeIn(v, c.fieldUnit(field, v)), nil
+}
+
+// Set stores t in the named timestamp field of m in c's unit, or the unit
+// set for the field with WithFieldUnit. The zero time clears the field.
+func (c *Converter) Set(m proto.Message, field string, t time.Time) error {
+	pm, fd, err := lookup(m, field)
+	if err != nil {
+		return err
+	}
+	u, ok := c.fields[field]
+	if !ok {
+		u = c.unit
+	}
+	pm.Set(fd, protoreflect.ValueOfInt64(unixIn(t, u)))
+	return nil
+}
+
+// Normalize rewrites every timestamp field of m, including those of nested
+// messages, from the unit c reads it in to the unit to. It reports whether
+// any value changed.
+func (c *Converter) Normalize(m proto.Message, to Unit) bool {
+	return c.normalize(target(m), to)
+}
+
+func (c *Converter) normalize(m protoreflect.Message, to Unit) bool {
+	changed := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case IsTimestamp(fd):
+			if n := Convert(v.Int(), c.fieldUnit(string(fd.Name()), v.Int()), to); n != v.Int() {
+				m.Set(fd, protoreflect.ValueOfInt64(n))
+				changed = true
+			}
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				changed = c.normalize(v.List().Get(i).Message(), to) || changed
+			}
+		case fd.Message() != nil && !fd.IsMap():
+			changed = c.normalize(v.Message(), to) || changed
+		}
+		return true
+	})
+	return changed
+}
+
+// Migrate normalizes ms in place to the unit to and returns the number of
+// messages that changed. A nil Converter means Default.
+func Migrate[T proto.Message](c *Converter, ms []T, to Unit) int {
+	if c == nil {
+		c = Default
+	}
+	n := 0
+	for _, m := range ms {
+		if c.Normalize(m, to) {
+			n++
+		}
+	}
+	return n
+}
+
+func target(m proto.Message) protoreflect.Message {
+	if e, ok := m.(*model.Entity); ok {
+		if d := entity.Unwrap(e); d != nil {
+			return d.ProtoReflect()
+		}
+	}
+	return m.ProtoReflect()
+}
+
+func lookup(m proto.Message, field string) (protoreflect.Message, protoreflect.FieldDescriptor, error) {
+	pm := target(m)
+	fd := pm.Descriptor().Fields().ByName(protoreflect.Name(field))
+	if fd == nil || !IsTimestamp(fd) {
+		return nil, nil, fmt.Errorf("%w: %s.%s", ErrNotTimestamp, pm.Descriptor().Name(), field)
+	}
+	return pm, fd, nil
+}
@@ -0,0 +1,300 @@
+// Package fuzzydate builds, compares and formats FuzzyDate values and maps
+// them onto the exact int64 date fields of Person and Organization.
+//
+// A FuzzyDate covers an interval of time: "1970" is the whole year, a
+// month-precision range "2015-07/2015-09" is the third quarter of 2015.
+// Circa dates are widened by one unit of their precision on each side, so
+// "around 1970" covers 1969 through 1971. Intervals are computed in UTC.
+//
+// Format and Parse use the Extended Date/Time Format (EDTF) subset that
+// FuzzyDate can express: "1970", "1970-05", "1970-05-12", a "~" suffix for
+// circa and "start/end" for ranges.
+//
+// The int64 fields hold the start of a date as a timestamp, where 0 means
+// unset. A date starting on 1970-01-01 therefore leaves them unset and
+// lives in the FuzzyDate field alone.
+package fuzzydate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalid is returned for dates whose fields contradict each other or
+// their precision.
+var ErrInvalid = errors.New("fuzzydate: invalid date")
+
+// Precision values, re-exported for brevity.
+const (
+	PrecisionYear  = model.FuzzyDate_PRECISION_YEAR
+	PrecisionMonth = model.FuzzyDate_PRECISION_MONTH
+	PrecisionDay   = model.FuzzyDate_PRECISION_DAY
+)
+
+// Year returns the date covering year y.
+func Year(y int) *model.FuzzyDate {
+	return &model.FuzzyDate{Year: int32(y), Precision: PrecisionYear}
+}
+
+// Month returns the date covering month m of year y.
+func Month(y, m int) *model.FuzzyDate {
+	return &model.FuzzyDate{Year: int32(y), Month: int32(m), Precision: PrecisionMonth}
+}
+
+// Day returns the exact day y-m-d.
+func Day(y, m, d int) *model.FuzzyDate {
+	return &model.FuzzyDate{Year: int32(y), Month: int32(m), Day: int32(d), Precision: PrecisionDay}
+}
+
+// Quarter returns quarter q (1-4) of year y as a month range.
+func Quarter(y, q int) (*model.FuzzyDate, error) {
+	if q < 1 || q > 4 {
+		return nil, fmt.Errorf("%w: quarter %d", ErrInvalid, q)
+	}
+	return &model.FuzzyDate{
+		Year: int32(y), Month: int32(3*q - 2),
+		EndYear: int32(y), EndMonth: int32(3 * q),
+		Precision: PrecisionMonth,
+	}, nil
+}
+
+// Between returns the range from the start of from to the end of to. Both
+// must have the same precision and must not be ranges themselves.
+func Between(from, to *model.FuzzyDate) (*model.FuzzyDate, error) {
+	if from.GetPrecision() != to.GetPrecision() || IsRange(from) || IsRange(to) {
+		return nil, fmt.Errorf("%w: range ends must be single dates of the same precision", ErrInvalid)
+	}
+	d := proto.Clone(from).(*model.FuzzyDate)
+	d.EndYear, d.EndMonth, d.EndDay = to.GetYear(), to.GetMonth(), to.GetDay()
+	d.Circa = from.GetCirca() || to.GetCirca()
+	return d, Validate(d)
+}
+
+// Circa returns a copy of d marked as approximate.
+func Circa(d *model.FuzzyDate) *model.FuzzyDate {
+	c := proto.Clone(d).(*model.FuzzyDate)
+	c.Circa = true
+	return c
+}
+
+// FromTime returns the day of t in UTC. The zero time yields nil.
+func FromTime(t time.Time) *model.FuzzyDate {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return Day(t.Year(), int(t.Month()), t.Day())
+}
+
+// IsRange reports whether d has an end. An end of all zeros means none, so
+// a year-precision range cannot end in year 0 (1 BC).
+func IsRange(d *model.FuzzyDate) bool {
+	return d.GetEndYear() != 0 || d.GetEndMonth() != 0 || d.GetEndDay() != 0
+}
+
+// Validate checks that d has a precision, that its fields match it, that
+// days exist in their month and that a range does not end before it
+// starts.
+func Validate(d *model.FuzzyDate) error {
+	if d == nil {
+		return nil
+	}
+	if err := checkFields(d.GetPrecision(), d.GetYear(), d.GetMonth(), d.GetDay()); err != nil {
+		return err
+	}
+	if !IsRange(d) {
+		return nil
+	}
+	if err := checkFields(d.GetPrecision(), d.GetEndYear(), d.GetEndMonth(), d.GetEndDay()); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start, _ := interval(d.GetPrecision(), d.GetEndYear(), d.GetEndMonth(), d.GetEndDay()); start.Before(startOf(d)) {
+		return fmt.Errorf("%w: range ends before it starts", ErrInvalid)
+	}
+	return nil
+}
+
+func checkFields(p model.FuzzyDate_Precision, y, m, d int32) error {
+	switch p {
+	case PrecisionYear:
+		if m != 0 || d != 0 {
+			return fmt.Errorf("%w: year precision with month or day set", ErrInvalid)
+		}
+	case PrecisionMonth:
+		if m < 1 || m > 12 || d != 0 {
+			return fmt.Errorf("%w: month precision needs a month 1-12 and no day", ErrInvalid)
+		}
+	case PrecisionDay:
+		if m < 1 || m > 12 || d < 1 || int(d) > daysIn(int(y), time.Month(m)) {
+			return fmt.Errorf("%w: %04d-%02d-%02d is not a day", ErrInvalid, y, m, d)
+		}
+	default:
+		return fmt.Errorf("%w: no precision", ErrInvalid)
+	}
+	return nil
+}
+
+func daysIn(y int, m time.Month) int {
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// interval returns the half-open interval of one date at precision p.
+func interval(p model.FuzzyDate_Precision, y, m, d int32) (start, end time.Time) {
+	switch p {
+	case PrecisionDay:
+		start = time.Date(int(y), time.Month(m), int(d), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	case PrecisionMonth:
+		start = time.Date(int(y), time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start = time.Date(int(y), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
+
+func startOf(d *model.FuzzyDate) time.Time {
+	start, _ := interval(d.GetPrecision(), d.GetYear(), d.GetMonth(), d.GetDay())
+	return start
+}
+
+// Bounds returns the half-open interval [start, end) covered by d,
+// including the circa margin. A nil date yields zero times.
+func Bounds(d *model.FuzzyDate) (start, end time.Time) {
+	if d == nil {
+		return time.Time{}, time.Time{}
+	}
+	p := d.GetPrecision()
+	start, end = interval(p, d.GetYear(), d.GetMonth(), d.GetDay())
+	if IsRange(d) {
+		_, end = interval(p, d.GetEndYear(), d.GetEndMonth(), d.GetEndDay())
+	}
+	if d.GetCirca() {
+		switch p {
+		case PrecisionDay:
+			start, end = start.AddDate(0, 0, -1), end.AddDate(0, 0, 1)
+		case PrecisionMonth:
+			start, end = start.AddDate(0, -1, 0), end.AddDate(0, 1, 0)
+		default:
+			start, end = start.AddDate(-1, 0, 0), end.AddDate(1, 0, 0)
+		}
+	}
+	return start, end
+}
+
+// Compare orders dates by the start and then the end of their bounds. It
+// returns -1, 0 or +1. Nil dates sort first.
+func Compare(a, b *model.FuzzyDate) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	as, ae := Bounds(a)
+	bs, be := Bounds(b)
+	if c := as.Compare(bs); c != 0 {
+		return c
+	}
+	return ae.Compare(be)
+}
+
+// Overlaps reports whether the bounds of a and b share any instant.
+func Overlaps(a, b *model.FuzzyDate) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	as, ae := Bounds(a)
+	bs, be := Bounds(b)
+	return as.Before(be) && bs.Before(ae)
+}
+
+// Contains reports whether t lies within the bounds of d.
+func Contains(d *model.FuzzyDate, t time.Time) bool {
+	if d == nil {
+		return false
+	}
+	start, end := Bounds(d)
+	return !t.Before(start) && t.Before(end)
+}
+
+// Format returns d in EDTF, or "" for nil.
+func Format(d *model.FuzzyDate) string {
+	if d == nil {
+		return ""
+	}
+	s := formatOne(d.GetPrecision(), d.GetYear(), d.GetMonth(), d.GetDay(), d.GetCirca())
+	if IsRange(d) {
+		s += "/" + formatOne(d.GetPrecision(), d.GetEndYear(), d.GetEndMonth(), d.GetEndDay(), d.GetCirca())
+	}
+	return s
+}
+
+func formatOne(p model.FuzzyDate_Precision, y, m, d int32, circa bool) string {
+	var s string
+	switch p {
+	case PrecisionDay:
+		s = fmt.Sprintf("%04d-%02d-%02d", y, m, d)
+	case PrecisionMonth:
+		s = fmt.Sprintf("%04d-%02d", y, m)
+	default:
+		s = fmt.Sprintf("%04d", y)
+	}
+	if circa {
+		s += "~"
+	}
+	return s
+}
+
+// Parse reads a date written by Format. Both ends of a range must have the
+// same precision.
+func Parse(s string) (*model.FuzzyDate, error) {
+	first, second, isRange := strings.Cut(strings.TrimSpace(s), "/")
+	d, err := parseOne(first)
+	if err != nil {
+		return nil, err
+	}
+	if !isRange {
+		return d, Validate(d)
+	}
+	end, err := parseOne(second)
+	if err != nil {
+		return nil, err
+	}
+	return Between(d, end)
+}
+
+func parseOne(s string) (*model.FuzzyDate, error) {
+	circa := strings.HasSuffix(s, "~")
+	s = strings.TrimSuffix(s, "~")
+	parts := strings.Split(s, "-")
+	if len(parts) > 3 || len(parts[0]) < 4 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	n := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		n[i] = v
+	}
+	var d *model.FuzzyDate
+	switch len(n) {
+	case 1:
+		d = Year(n[0])
+	case 2:
+		d = Month(n[0], n[1])
+	default:
+		d = Day(n[0], n[1], n[2])
+	}
+	d.Circa = circa
+	return d, nil
+}
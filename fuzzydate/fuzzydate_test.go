@@ -0,0 +1,186 @@
+package fuzzydate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func mustQuarter(t *testing.T, y, q int) *model.FuzzyDate {
+	t.Helper()
+	d, err := Quarter(y, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestQuarter(t *testing.T) {
+	if got := Format(mustQuarter(t, 2015, 3)); got != "2015-07/2015-09" {
+		t.Errorf("Quarter(2015, 3) = %s, want 2015-07/2015-09", got)
+	}
+	for _, q := range []int{0, 5, -1} {
+		if _, err := Quarter(2015, q); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Quarter(2015, %d) error = %v, want ErrInvalid", q, err)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		d          *model.FuzzyDate
+		start, end time.Time
+	}{
+		{Year(1970), date(1970, 1, 1), date(1971, 1, 1)},
+		{Month(2024, 2), date(2024, 2, 1), date(2024, 3, 1)},
+		{Day(2024, 2, 29), date(2024, 2, 29), date(2024, 3, 1)},
+		{Circa(Year(1970)), date(1969, 1, 1), date(1972, 1, 1)},
+		{Circa(Month(2000, 1)), date(1999, 12, 1), date(2000, 3, 1)},
+		{Circa(Day(2000, 3, 1)), date(2000, 2, 29), date(2000, 3, 3)},
+		{&model.FuzzyDate{Year: 2015, Month: 7, EndYear: 2015, EndMonth: 9, Precision: PrecisionMonth}, date(2015, 7, 1), date(2015, 10, 1)},
+		{nil, time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		start, end := Bounds(tt.d)
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("Bounds(%s) = %v, %v, want %v, %v", Format(tt.d), start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b *model.FuzzyDate
+		want int
+	}{
+		{Year(1970), Year(1971), -1},
+		{Year(1970), Month(1970, 1), 1},
+		{Month(1970, 1), Day(1970, 1, 1), 1},
+		{Day(1970, 1, 2), Month(1970, 1), 1},
+		{Circa(Year(1970)), Year(1970), -1},
+		{Year(1970), Year(1970), 0},
+		{nil, Year(1970), -1},
+		{Year(1970), nil, 1},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", Format(tt.a), Format(tt.b), got, tt.want)
+		}
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		a, b *model.FuzzyDate
+		want bool
+	}{
+		{Year(1970), Day(1970, 6, 1), true},
+		{Year(1970), Year(1971), false},
+		{Circa(Year(1970)), Year(1971), true},
+		{mustQuarter(t, 2015, 3), Month(2015, 9), true},
+		{mustQuarter(t, 2015, 3), Month(2015, 10), false},
+		{Year(1970), nil, false},
+	}
+	for _, tt := range tests {
+		if got := Overlaps(tt.a, tt.b); got != tt.want {
+			t.Errorf("Overlaps(%q, %q) = %v, want %v", Format(tt.a), Format(tt.b), got, tt.want)
+		}
+	}
+	if !Contains(Month(2024, 2), date(2024, 2, 29)) || Contains(Month(2024, 2), date(2024, 3, 1)) {
+		t.Error("Contains does not treat the end as exclusive")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		s    string
+		want *model.FuzzyDate
+	}{
+		{"1970", Year(1970)},
+		{"1970-05", Month(1970, 5)},
+		{"1970-05-12", Day(1970, 5, 12)},
+		{"1970~", Circa(Year(1970))},
+		{"0900", Year(900)},
+		{"2015-07/2015-09", mustQuarter(t, 2015, 3)},
+		{"1990~/1995~", &model.FuzzyDate{Year: 1990, EndYear: 1995, Precision: PrecisionYear, Circa: true}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.s, err)
+			continue
+		}
+		if !proto.Equal(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+		if s := Format(got); s != tt.s {
+			t.Errorf("Format(Parse(%q)) = %q", tt.s, s)
+		}
+	}
+	for _, s := range []string{"", "70", "1970-13", "1970-02-30", "1970/1970-05", "1971/1970", "1970-05-12-01", "next year"} {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", s, err)
+		}
+	}
+}
+
+func TestRangeEndingInYearZero(t *testing.T) {
+	d := &model.FuzzyDate{Year: -5, EndYear: 0, Precision: PrecisionYear}
+	if IsRange(d) {
+		t.Error("a range ending in year 0 should read as having no end")
+	}
+	if got := Format(d); got != "-005" {
+		t.Errorf("Format = %q, want the start year alone", got)
+	}
+}
+
+func TestModelMapping(t *testing.T) {
+	tests := []struct {
+		d    *model.FuzzyDate
+		want int64
+	}{
+		{Day(1985, 7, 21), 490752000},
+		{Circa(Year(1985)), 473385600},
+		{Month(1969, 12), -2678400},
+		{Day(1970, 1, 1), 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		p := &model.Person{BirthDate: 1}
+		if err := SetBirthDate(p, tt.d); err != nil {
+			t.Fatal(err)
+		}
+		if p.GetBirthDate() != tt.want {
+			t.Errorf("SetBirthDate(%q): birth_date = %d, want %d", Format(tt.d), p.GetBirthDate(), tt.want)
+		}
+		if got := BirthDate(p); !proto.Equal(got, tt.d) {
+			t.Errorf("BirthDate after SetBirthDate(%q) = %q", Format(tt.d), Format(got))
+		}
+
+		o := &model.Organization{}
+		if err := SetFoundedAt(o, tt.d); err != nil {
+			t.Fatal(err)
+		}
+		if o.GetFoundedAt() != tt.want || !proto.Equal(FoundedAt(o), tt.d) {
+			t.Errorf("SetFoundedAt(%q): founded_at = %d, FoundedAt = %q", Format(tt.d), o.GetFoundedAt(), Format(FoundedAt(o)))
+		}
+	}
+
+	if got := BirthDate(&model.Person{BirthDate: 490752000 + 3600}); !proto.Equal(got, Day(1985, 7, 21)) {
+		t.Errorf("BirthDate from birth_date = %q, want 1985-07-21", Format(got))
+	}
+	if got := BirthDate(&model.Person{BirthDate: 490752000000}); !proto.Equal(got, Day(1985, 7, 21)) {
+		t.Errorf("BirthDate from milliseconds = %q, want 1985-07-21", Format(got))
+	}
+	if err := SetBirthDate(&model.Person{}, Month(1970, 13)); !errors.Is(err, ErrInvalid) {
+		t.Errorf("SetBirthDate(1970-13) error = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,56 @@
+package fuzzydate
+
+import (
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// BirthDate returns the birth date of p: fuzzy_birth_date when set,
+// otherwise the day of birth_date, or nil when neither is set.
+func BirthDate(p *model.Person) *model.FuzzyDate {
+	if d := p.GetFuzzyBirthDate(); d != nil {
+		return d
+	}
+	return FromTime(epoch.BirthDate(p))
+}
+
+// SetBirthDate stores d in fuzzy_birth_date and its start in birth_date. A
+// nil date clears both.
+func SetBirthDate(p *model.Person, d *model.FuzzyDate) error {
+	if err := Validate(d); err != nil {
+		return err
+	}
+	p.FuzzyBirthDate = d
+	p.BirthDate = exact(d)
+	return nil
+}
+
+// FoundedAt returns the founding date of o: fuzzy_founded_at when set,
+// otherwise the day of founded_at, or nil when neither is set.
+func FoundedAt(o *model.Organization) *model.FuzzyDate {
+	if d := o.GetFuzzyFoundedAt(); d != nil {
+		return d
+	}
+	return FromTime(epoch.FoundedAt(o))
+}
+
+// SetFoundedAt stores d in fuzzy_founded_at and its start in founded_at. A
+// nil date clears both.
+func SetFoundedAt(o *model.Organization, d *model.FuzzyDate) error {
+	if err := Validate(d); err != nil {
+		return err
+	}
+	o.FuzzyFoundedAt = d
+	o.FoundedAt = exact(d)
+	return nil
+}
+
+// exact returns the start of d, without the circa margin, as a timestamp.
+// A date starting on 1970-01-01 maps to 0 and so leaves the exact field
+// unset.
+func exact(d *model.FuzzyDate) int64 {
+	if d == nil {
+		return 0
+	}
+	return epoch.Default.Unix(startOf(d))
+}
@@ -21,6 +21,118 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// LocationSource is how the coordinates of a LocationData were obtained.
+type LocationSource int32
+
+const (
+	LocationSource_LOCATION_SOURCE_UNSPECIFIED LocationSource = 0
+	// Measured by a satellite receiver.
+	LocationSource_LOCATION_SOURCE_GPS LocationSource = 1
+	// Looked up from a place name or address.
+	LocationSource_LOCATION_SOURCE_GEOCODED LocationSource = 2
+	// Derived from other data, such as an IP address or related documents.
+	LocationSource_LOCATION_SOURCE_INFERRED LocationSource = 3
+	// Entered by an analyst.
+	LocationSource_LOCATION_SOURCE_MANUAL LocationSource = 4
+)
+
+// Enum value maps for LocationSource.
+var (
+	LocationSource_name = map[int32]string{
+		0: "LOCATION_SOURCE_UNSPECIFIED",
+		1: "LOCATION_SOURCE_GPS",
+		2: "LOCATION_SOURCE_GEOCODED",
+		3: "LOCATION_SOURCE_INFERRED",
+		4: "LOCATION_SOURCE_MANUAL",
+	}
+	LocationSource_value = map[string]int32{
+		"LOCATION_SOURCE_UNSPECIFIED": 0,
+		"LOCATION_SOURCE_GPS":         1,
+		"LOCATION_SOURCE_GEOCODED":    2,
+		"LOCATION_SOURCE_INFERRED":    3,
+		"LOCATION_SOURCE_MANUAL":      4,
+	}
+)
+
+func (x LocationSource) Enum() *LocationSource {
+	p := new(LocationSource)
+	*p = x
+	return p
+}
+
+func (x LocationSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LocationSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_v1_common_proto_enumTypes[0].Descriptor()
+}
+
+func (LocationSource) Type() protoreflect.EnumType {
+	return &file_model_v1_common_proto_enumTypes[0]
+}
+
+func (x LocationSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LocationSource.Descriptor instead.
+func (LocationSource) EnumDescriptor() ([]byte, []int) {
+	return file_model_v1_common_proto_rawDescGZIP(), []int{0}
+}
+
+type FuzzyDate_Precision int32
+
+const (
+	FuzzyDate_PRECISION_UNSPECIFIED FuzzyDate_Precision = 0
+	FuzzyDate_PRECISION_YEAR        FuzzyDate_Precision = 1
+	FuzzyDate_PRECISION_MONTH       FuzzyDate_Precision = 2
+	FuzzyDate_PRECISION_DAY         FuzzyDate_Precision = 3
+)
+
+// Enum value maps for FuzzyDate_Precision.
+var (
+	FuzzyDate_Precision_name = map[int32]string{
+		0: "PRECISION_UNSPECIFIED",
+		1: "PRECISION_YEAR",
+		2: "PRECISION_MONTH",
+		3: "PRECISION_DAY",
+	}
+	FuzzyDate_Precision_value = map[string]int32{
+		"PRECISION_UNSPECIFIED": 0,
+		"PRECISION_YEAR":        1,
+		"PRECISION_MONTH":       2,
+		"PRECISION_DAY":         3,
+	}
+)
+
+func (x FuzzyDate_Precision) Enum() *FuzzyDate_Precision {
+	p := new(FuzzyDate_Precision)
+	*p = x
+	return p
+}
+
+func (x FuzzyDate_Precision) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FuzzyDate_Precision) Descriptor() protoreflect.EnumDescriptor {
+	return file_model_v1_common_proto_enumTypes[1].Descriptor()
+}
+
+func (FuzzyDate_Precision) Type() protoreflect.EnumType {
+	return &file_model_v1_common_proto_enumTypes[1]
+}
+
+func (x FuzzyDate_Precision) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FuzzyDate_Precision.Descriptor instead.
+func (FuzzyDate_Precision) EnumDescriptor() ([]byte, []int) {
+	return file_model_v1_common_proto_rawDescGZIP(), []int{1, 0}
+}
+
 type LocationData struct {
 	state                 protoimpl.MessageState `protogen:"open.v1"`
 	Latitude              float32                `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
@@ -31,9 +143,20 @@ type LocationData struct {
 	Locality              string                 `protobuf:"bytes,7,opt,name=locality,proto3" json:"locality,omitempty"`
 	SubLocality           string                 `protobuf:"bytes,8,opt,name=sub_locality,json=subLocality,proto3" json:"sub_locality,omitempty"`
 	Address               string                 `protobuf:"bytes,9,opt,name=address,proto3" json:"address,omitempty"`
-	PostalCode            int32                  `protobuf:"varint,10,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	// Deprecated: numeric postal codes lose leading zeros and cannot hold
+	// alphanumeric postcodes. Use postcode.
+	//
+	// Deprecated: Marked as deprecated in model/v1/common.proto.
+	PostalCode int32  `protobuf:"varint,10,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Postcode   string `protobuf:"bytes,11,opt,name=postcode,proto3" json:"postcode,omitempty"`
+	// Radius in metres within which the true position lies, if known.
+	Accuracy float32 `protobuf:"fixed32,12,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	// Metres above mean sea level.
+	Altitude float32 `protobuf:"fixed32,13,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	// How the coordinates were obtained.
+	Source        LocationSource `protobuf:"varint,14,opt,name=source,proto3,enum=model.v1.LocationSource" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LocationData) Reset() {
@@ -122,6 +245,7 @@ func (x *LocationData) GetAddress() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in model/v1/common.proto.
 func (x *LocationData) GetPostalCode() int32 {
 	if x != nil {
 		return x.PostalCode
@@ -129,23 +253,180 @@ func (x *LocationData) GetPostalCode() int32 {
 	return 0
 }
 
+func (x *LocationData) GetPostcode() string {
+	if x != nil {
+		return x.Postcode
+	}
+	return ""
+}
+
+func (x *LocationData) GetAccuracy() float32 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *LocationData) GetAltitude() float32 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *LocationData) GetSource() LocationSource {
+	if x != nil {
+		return x.Source
+	}
+	return LocationSource_LOCATION_SOURCE_UNSPECIFIED
+}
+
+// FuzzyDate is a calendar date known only to some precision, such as "born
+// around 1970" or "founded in Q3 2015". The start is given by year, month
+// and day; fields finer than the precision are zero. A range additionally
+// sets the end fields, inclusive and at the same precision.
+type FuzzyDate struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Year      int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month     int32                  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day       int32                  `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+	Precision FuzzyDate_Precision    `protobuf:"varint,4,opt,name=precision,proto3,enum=model.v1.FuzzyDate_Precision" json:"precision,omitempty"`
+	EndYear   int32                  `protobuf:"varint,5,opt,name=end_year,json=endYear,proto3" json:"end_year,omitempty"`
+	EndMonth  int32                  `protobuf:"varint,6,opt,name=end_month,json=endMonth,proto3" json:"end_month,omitempty"`
+	EndDay    int32                  `protobuf:"varint,7,opt,name=end_day,json=endDay,proto3" json:"end_day,omitempty"`
+	// The date is approximate.
+	Circa         bool `protobuf:"varint,8,opt,name=circa,proto3" json:"circa,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FuzzyDate) Reset() {
+	*x = FuzzyDate{}
+	mi := &file_model_v1_common_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FuzzyDate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FuzzyDate) ProtoMessage() {}
+
+func (x *FuzzyDate) ProtoReflect() protoreflect.Message {
+	mi := &file_model_v1_common_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FuzzyDate.ProtoReflect.Descriptor instead.
+func (*FuzzyDate) Descriptor() ([]byte, []int) {
+	return file_model_v1_common_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FuzzyDate) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetPrecision() FuzzyDate_Precision {
+	if x != nil {
+		return x.Precision
+	}
+	return FuzzyDate_PRECISION_UNSPECIFIED
+}
+
+func (x *FuzzyDate) GetEndYear() int32 {
+	if x != nil {
+		return x.EndYear
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetEndMonth() int32 {
+	if x != nil {
+		return x.EndMonth
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetEndDay() int32 {
+	if x != nil {
+		return x.EndDay
+	}
+	return 0
+}
+
+func (x *FuzzyDate) GetCirca() bool {
+	if x != nil {
+		return x.Circa
+	}
+	return false
+}
+
 var File_model_v1_common_proto protoreflect.FileDescriptor
 
 const file_model_v1_common_proto_rawDesc = "" +
 	"\n" +
-	"\x15model/v1/common.proto\x12\bmodel.v1\"\xce\x02\n" +
-	"\fLocationData\x12\x1a\n" +
-	"\blatitude\x18\x01 \x01(\x02R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x02R\tlongitude\x12!\n" +
-	"\fcountry_code\x18\x03 \x01(\tR\vcountryCode\x12/\n" +
+	"\x15model/v1/common.proto\x12\bmodel.v1\x1a\x14model/v1/rules.proto\"\x9f\x04\n" +
+	"\fLocationData\x122\n" +
+	"\blatitude\x18\x01 \x01(\x02B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x80V\xc0\x11\x00\x00\x00\x00\x00\x80V@R\blatitude\x124\n" +
+	"\tlongitude\x18\x02 \x01(\x02B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x80f\xc0\x11\x00\x00\x00\x00\x00\x80f@R\tlongitude\x12)\n" +
+	"\fcountry_code\x18\x03 \x01(\tB\x06\x8a\xb5\x18\x02 \x01R\vcountryCode\x12/\n" +
 	"\x13administrative_area\x18\x04 \x01(\tR\x12administrativeArea\x126\n" +
 	"\x17sub_administrative_area\x18\x05 \x01(\tR\x15subAdministrativeArea\x12\x1a\n" +
 	"\blocality\x18\a \x01(\tR\blocality\x12!\n" +
 	"\fsub_locality\x18\b \x01(\tR\vsubLocality\x12\x18\n" +
-	"\aaddress\x18\t \x01(\tR\aaddress\x12\x1f\n" +
+	"\aaddress\x18\t \x01(\tR\aaddress\x12#\n" +
 	"\vpostal_code\x18\n" +
-	" \x01(\x05R\n" +
-	"postalCodeB:Z8github.com/omnsight/omniscent-library/gen/model/v1;modelb\x06proto3"
+	" \x01(\x05B\x02\x18\x01R\n" +
+	"postalCode\x12\x1a\n" +
+	"\bpostcode\x18\v \x01(\tR\bpostcode\x12)\n" +
+	"\baccuracy\x18\f \x01(\x02B\r\x8a\xb5\x18\t\t\x00\x00\x00\x00\x00\x00\x00\x00R\baccuracy\x12\x1a\n" +
+	"\baltitude\x18\r \x01(\x02R\baltitude\x120\n" +
+	"\x06source\x18\x0e \x01(\x0e2\x18.model.v1.LocationSourceR\x06source\"\xaf\x03\n" +
+	"\tFuzzyDate\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12,\n" +
+	"\x05month\x18\x02 \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00(@R\x05month\x12(\n" +
+	"\x03day\x18\x03 \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00?@R\x03day\x12;\n" +
+	"\tprecision\x18\x04 \x01(\x0e2\x1d.model.v1.FuzzyDate.PrecisionR\tprecision\x12\x19\n" +
+	"\bend_year\x18\x05 \x01(\x05R\aendYear\x123\n" +
+	"\tend_month\x18\x06 \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00(@R\bendMonth\x12/\n" +
+	"\aend_day\x18\a \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00?@R\x06endDay\x12\x14\n" +
+	"\x05circa\x18\b \x01(\bR\x05circa\"b\n" +
+	"\tPrecision\x12\x19\n" +
+	"\x15PRECISION_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0ePRECISION_YEAR\x10\x01\x12\x13\n" +
+	"\x0fPRECISION_MONTH\x10\x02\x12\x11\n" +
+	"\rPRECISION_DAY\x10\x03*\xa2\x01\n" +
+	"\x0eLocationSource\x12\x1f\n" +
+	"\x1bLOCATION_SOURCE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13LOCATION_SOURCE_GPS\x10\x01\x12\x1c\n" +
+	"\x18LOCATION_SOURCE_GEOCODED\x10\x02\x12\x1c\n" +
+	"\x18LOCATION_SOURCE_INFERRED\x10\x03\x12\x1a\n" +
+	"\x16LOCATION_SOURCE_MANUAL\x10\x04B:Z8github.com/omnsight/omniscent-library/gen/model/v1;modelb\x06proto3"
 
 var (
 	file_model_v1_common_proto_rawDescOnce sync.Once
@@ -159,16 +440,22 @@ func file_model_v1_common_proto_rawDescGZIP() []byte {
 	return file_model_v1_common_proto_rawDescData
 }
 
-var file_model_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_model_v1_common_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_model_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_model_v1_common_proto_goTypes = []any{
-	(*LocationData)(nil), // 0: model.v1.LocationData
+	(LocationSource)(0),      // 0: model.v1.LocationSource
+	(FuzzyDate_Precision)(0), // 1: model.v1.FuzzyDate.Precision
+	(*LocationData)(nil),     // 2: model.v1.LocationData
+	(*FuzzyDate)(nil),        // 3: model.v1.FuzzyDate
 }
 var file_model_v1_common_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: model.v1.LocationData.source:type_name -> model.v1.LocationSource
+	1, // 1: model.v1.FuzzyDate.precision:type_name -> model.v1.FuzzyDate.Precision
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_model_v1_common_proto_init() }
@@ -176,18 +463,20 @@ func file_model_v1_common_proto_init() {
 	if File_model_v1_common_proto != nil {
 		return
 	}
+	file_model_v1_rules_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_model_v1_common_proto_rawDesc), len(file_model_v1_common_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      2,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_model_v1_common_proto_goTypes,
 		DependencyIndexes: file_model_v1_common_proto_depIdxs,
+		EnumInfos:         file_model_v1_common_proto_enumTypes,
 		MessageInfos:      file_model_v1_common_proto_msgTypes,
 	}.Build()
 	File_model_v1_common_proto = out.File
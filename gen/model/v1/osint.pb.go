@@ -524,6 +524,9 @@ type Person struct {
 	// Time data
 	BirthDate int64 `protobuf:"varint,20,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
 	UpdatedAt int64 `protobuf:"varint,21,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Uncertain birth date. Writers keep birth_date set to its start for
+	// readers that predate this field.
+	FuzzyBirthDate *FuzzyDate `protobuf:"bytes,22,opt,name=fuzzy_birth_date,json=fuzzyBirthDate,proto3" json:"fuzzy_birth_date,omitempty"`
 	// Additional
 	Tags          []string         `protobuf:"bytes,30,rep,name=tags,proto3" json:"tags,omitempty"`
 	Aliases       []string         `protobuf:"bytes,31,rep,name=aliases,proto3" json:"aliases,omitempty"`
@@ -639,6 +642,13 @@ func (x *Person) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *Person) GetFuzzyBirthDate() *FuzzyDate {
+	if x != nil {
+		return x.FuzzyBirthDate
+	}
+	return nil
+}
+
 func (x *Person) GetTags() []string {
 	if x != nil {
 		return x.Tags
@@ -679,6 +689,9 @@ type Organization struct {
 	FoundedAt    int64 `protobuf:"varint,20,opt,name=founded_at,json=foundedAt,proto3" json:"founded_at,omitempty"`
 	DiscoveredAt int64 `protobuf:"varint,21,opt,name=discovered_at,json=discoveredAt,proto3" json:"discovered_at,omitempty"`
 	LastVisited  int64 `protobuf:"varint,22,opt,name=last_visited,json=lastVisited,proto3" json:"last_visited,omitempty"`
+	// Uncertain founding date. Writers keep founded_at set to its start for
+	// readers that predate this field.
+	FuzzyFoundedAt *FuzzyDate `protobuf:"bytes,23,opt,name=fuzzy_founded_at,json=fuzzyFoundedAt,proto3" json:"fuzzy_founded_at,omitempty"`
 	// Additional
 	Tags          []string         `protobuf:"bytes,30,rep,name=tags,proto3" json:"tags,omitempty"`
 	Attributes    *structpb.Struct `protobuf:"bytes,31,opt,name=attributes,proto3" json:"attributes,omitempty"`
@@ -793,6 +806,13 @@ func (x *Organization) GetLastVisited() int64 {
 	return 0
 }
 
+func (x *Organization) GetFuzzyFoundedAt() *FuzzyDate {
+	if x != nil {
+		return x.FuzzyFoundedAt
+	}
+	return nil
+}
+
 func (x *Organization) GetTags() []string {
 	if x != nil {
 		return x.Tags
@@ -1096,7 +1116,7 @@ var File_model_v1_osint_proto protoreflect.FileDescriptor
 
 const file_model_v1_osint_proto_rawDesc = "" +
 	"\n" +
-	"\x14model/v1/osint.proto\x12\bmodel.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x15model/v1/common.proto\"\xe3\x02\n" +
+	"\x14model/v1/osint.proto\x12\bmodel.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x15model/v1/common.proto\x1a\x14model/v1/rules.proto\"\xa9\x03\n" +
 	"\bRelation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
@@ -1107,18 +1127,19 @@ const file_model_v1_osint_proto_rawDesc = "" +
 	"\x04read\x18\a \x03(\tR\x04read\x12\x14\n" +
 	"\x05write\x18\b \x03(\tR\x05write\x12\x12\n" +
 	"\x04name\x18\n" +
-	" \x01(\tR\x04name\x12\x1e\n" +
+	" \x01(\tR\x04name\x126\n" +
 	"\n" +
-	"confidence\x18\v \x01(\x05R\n" +
+	"confidence\x18\v \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00Y@R\n" +
 	"confidence\x12\x14\n" +
-	"\x05label\x18\f \x01(\tR\x05label\x12\x1d\n" +
+	"\x05label\x18\f \x01(\tR\x05label\x12.\n" +
 	"\n" +
-	"created_at\x18\x14 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"created_at\x18\x14 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\tcreatedAt\x12:\n" +
 	"\n" +
-	"updated_at\x18\x15 \x01(\x03R\tupdatedAt\x127\n" +
+	"updated_at\x18\x15 \x01(\x03B\x1b\x8a\xb5\x18\x17\t\x00\x00\x00\x00\x00\x00\x00\x00*\n" +
+	"created_at0\x01R\tupdatedAt\x127\n" +
 	"\n" +
 	"attributes\x18\x1e \x01(\v2\x17.google.protobuf.StructR\n" +
-	"attributes\"\x88\x03\n" +
+	"attributes\"\xb7\x03\n" +
 	"\x05Event\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
@@ -1130,15 +1151,15 @@ const file_model_v1_osint_proto_rawDesc = "" +
 	" \x01(\tR\x04type\x122\n" +
 	"\blocation\x18\v \x01(\v2\x16.model.v1.LocationDataR\blocation\x12\x14\n" +
 	"\x05title\x18\f \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\r \x01(\tR\vdescription\x12\x1f\n" +
-	"\vhappened_at\x18\x14 \x01(\x03R\n" +
-	"happenedAt\x12\x1d\n" +
+	"\vdescription\x18\r \x01(\tR\vdescription\x120\n" +
+	"\vhappened_at\x18\x14 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\n" +
+	"happenedAt\x12;\n" +
 	"\n" +
-	"updated_at\x18\x15 \x01(\x03R\tupdatedAt\x12\x12\n" +
+	"updated_at\x18\x15 \x01(\x03B\x1c\x8a\xb5\x18\x18\t\x00\x00\x00\x00\x00\x00\x00\x00*\vhappened_at0\x01R\tupdatedAt\x12\x12\n" +
 	"\x04tags\x18\x1e \x03(\tR\x04tags\x127\n" +
 	"\n" +
 	"attributes\x18\x1f \x01(\v2\x17.google.protobuf.StructR\n" +
-	"attributes\"\x9b\x03\n" +
+	"attributes\"\xe9\x03\n" +
 	"\x06Source\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
@@ -1147,20 +1168,21 @@ const file_model_v1_osint_proto_rawDesc = "" +
 	"\x04read\x18\x05 \x03(\tR\x04read\x12\x14\n" +
 	"\x05write\x18\x06 \x03(\tR\x05write\x12\x12\n" +
 	"\x04type\x18\n" +
-	" \x01(\tR\x04type\x12\x10\n" +
-	"\x03url\x18\v \x01(\tR\x03url\x12\x12\n" +
+	" \x01(\tR\x04type\x12\x18\n" +
+	"\x03url\x18\v \x01(\tB\x06\x8a\xb5\x18\x02\x18\x01R\x03url\x12\x12\n" +
 	"\x04name\x18\f \x01(\tR\x04name\x12\x14\n" +
 	"\x05title\x18\r \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x0e \x01(\tR\vdescription\x12 \n" +
-	"\vreliability\x18\x0f \x01(\x05R\vreliability\x12\x1d\n" +
+	"\vdescription\x18\x0e \x01(\tR\vdescription\x128\n" +
+	"\vreliability\x18\x0f \x01(\x05B\x16\x8a\xb5\x18\x12\t\x00\x00\x00\x00\x00\x00\x00\x00\x11\x00\x00\x00\x00\x00\x00Y@R\vreliability\x12.\n" +
 	"\n" +
-	"created_at\x18\x14 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"created_at\x18\x14 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\tcreatedAt\x12:\n" +
 	"\n" +
-	"updated_at\x18\x15 \x01(\x03R\tupdatedAt\x12\x12\n" +
+	"updated_at\x18\x15 \x01(\x03B\x1b\x8a\xb5\x18\x17\t\x00\x00\x00\x00\x00\x00\x00\x00*\n" +
+	"created_at0\x01R\tupdatedAt\x12\x12\n" +
 	"\x04tags\x18\x1e \x03(\tR\x04tags\x127\n" +
 	"\n" +
 	"attributes\x18\x1f \x01(\v2\x17.google.protobuf.StructR\n" +
-	"attributes\"\xeb\x02\n" +
+	"attributes\"\xc3\x03\n" +
 	"\x06Person\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
@@ -1171,16 +1193,17 @@ const file_model_v1_osint_proto_rawDesc = "" +
 	"\x04role\x18\n" +
 	" \x01(\tR\x04role\x12\x12\n" +
 	"\x04name\x18\v \x01(\tR\x04name\x12 \n" +
-	"\vnationality\x18\f \x01(\tR\vnationality\x12\x1d\n" +
+	"\vnationality\x18\f \x01(\tR\vnationality\x12%\n" +
 	"\n" +
-	"birth_date\x18\x14 \x01(\x03R\tbirthDate\x12\x1d\n" +
+	"birth_date\x18\x14 \x01(\x03B\x06\x8a\xb5\x18\x020\x01R\tbirthDate\x12.\n" +
 	"\n" +
-	"updated_at\x18\x15 \x01(\x03R\tupdatedAt\x12\x12\n" +
+	"updated_at\x18\x15 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\tupdatedAt\x12=\n" +
+	"\x10fuzzy_birth_date\x18\x16 \x01(\v2\x13.model.v1.FuzzyDateR\x0efuzzyBirthDate\x12\x12\n" +
 	"\x04tags\x18\x1e \x03(\tR\x04tags\x12\x18\n" +
 	"\aaliases\x18\x1f \x03(\tR\aaliases\x127\n" +
 	"\n" +
 	"attributes\x18  \x01(\v2\x17.google.protobuf.StructR\n" +
-	"attributes\"\xde\x02\n" +
+	"attributes\"\xc7\x03\n" +
 	"\fOrganization\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
@@ -1190,30 +1213,31 @@ const file_model_v1_osint_proto_rawDesc = "" +
 	"\x05write\x18\x06 \x03(\tR\x05write\x12\x12\n" +
 	"\x04type\x18\n" +
 	" \x01(\tR\x04type\x12\x12\n" +
-	"\x04name\x18\v \x01(\tR\x04name\x12\x1d\n" +
+	"\x04name\x18\v \x01(\tR\x04name\x12%\n" +
 	"\n" +
-	"founded_at\x18\x14 \x01(\x03R\tfoundedAt\x12#\n" +
-	"\rdiscovered_at\x18\x15 \x01(\x03R\fdiscoveredAt\x12!\n" +
-	"\flast_visited\x18\x16 \x01(\x03R\vlastVisited\x12\x12\n" +
+	"founded_at\x18\x14 \x01(\x03B\x06\x8a\xb5\x18\x020\x01R\tfoundedAt\x124\n" +
+	"\rdiscovered_at\x18\x15 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\fdiscoveredAt\x122\n" +
+	"\flast_visited\x18\x16 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\vlastVisited\x12=\n" +
+	"\x10fuzzy_founded_at\x18\x17 \x01(\v2\x13.model.v1.FuzzyDateR\x0efuzzyFoundedAt\x12\x12\n" +
 	"\x04tags\x18\x1e \x03(\tR\x04tags\x127\n" +
 	"\n" +
 	"attributes\x18\x1f \x01(\v2\x17.google.protobuf.StructR\n" +
-	"attributes\"\xfb\x02\n" +
+	"attributes\"\xad\x03\n" +
 	"\aWebsite\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
 	"\x03rev\x18\x03 \x01(\tR\x03rev\x12\x14\n" +
 	"\x05owner\x18\x04 \x01(\tR\x05owner\x12\x12\n" +
 	"\x04read\x18\x05 \x03(\tR\x04read\x12\x14\n" +
-	"\x05write\x18\x06 \x03(\tR\x05write\x12\x10\n" +
+	"\x05write\x18\x06 \x03(\tR\x05write\x12\x18\n" +
 	"\x03url\x18\n" +
-	" \x01(\tR\x03url\x12\x14\n" +
+	" \x01(\tB\x06\x8a\xb5\x18\x02\x18\x01R\x03url\x12\x14\n" +
 	"\x05title\x18\v \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\f \x01(\tR\vdescription\x12\x1d\n" +
+	"\vdescription\x18\f \x01(\tR\vdescription\x12%\n" +
 	"\n" +
-	"founded_at\x18\x14 \x01(\x03R\tfoundedAt\x12#\n" +
-	"\rdiscovered_at\x18\x15 \x01(\x03R\fdiscoveredAt\x12!\n" +
-	"\flast_visited\x18\x16 \x01(\x03R\vlastVisited\x12\x12\n" +
+	"founded_at\x18\x14 \x01(\x03B\x06\x8a\xb5\x18\x020\x01R\tfoundedAt\x124\n" +
+	"\rdiscovered_at\x18\x15 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\fdiscoveredAt\x122\n" +
+	"\flast_visited\x18\x16 \x01(\x03B\x0f\x8a\xb5\x18\v\t\x00\x00\x00\x00\x00\x00\x00\x000\x01R\vlastVisited\x12\x12\n" +
 	"\x04tags\x18\x1e \x03(\tR\x04tags\x127\n" +
 	"\n" +
 	"attributes\x18\x1f \x01(\v2\x17.google.protobuf.StructR\n" +
@@ -1249,25 +1273,28 @@ var file_model_v1_osint_proto_goTypes = []any{
 	(*Entity)(nil),          // 6: model.v1.Entity
 	(*structpb.Struct)(nil), // 7: google.protobuf.Struct
 	(*LocationData)(nil),    // 8: model.v1.LocationData
+	(*FuzzyDate)(nil),       // 9: model.v1.FuzzyDate
 }
 var file_model_v1_osint_proto_depIdxs = []int32{
 	7,  // 0: model.v1.Relation.attributes:type_name -> google.protobuf.Struct
 	8,  // 1: model.v1.Event.location:type_name -> model.v1.LocationData
 	7,  // 2: model.v1.Event.attributes:type_name -> google.protobuf.Struct
 	7,  // 3: model.v1.Source.attributes:type_name -> google.protobuf.Struct
-	7,  // 4: model.v1.Person.attributes:type_name -> google.protobuf.Struct
-	7,  // 5: model.v1.Organization.attributes:type_name -> google.protobuf.Struct
-	7,  // 6: model.v1.Website.attributes:type_name -> google.protobuf.Struct
-	2,  // 7: model.v1.Entity.source:type_name -> model.v1.Source
-	3,  // 8: model.v1.Entity.person:type_name -> model.v1.Person
-	4,  // 9: model.v1.Entity.organization:type_name -> model.v1.Organization
-	5,  // 10: model.v1.Entity.website:type_name -> model.v1.Website
-	1,  // 11: model.v1.Entity.event:type_name -> model.v1.Event
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	9,  // 4: model.v1.Person.fuzzy_birth_date:type_name -> model.v1.FuzzyDate
+	7,  // 5: model.v1.Person.attributes:type_name -> google.protobuf.Struct
+	9,  // 6: model.v1.Organization.fuzzy_founded_at:type_name -> model.v1.FuzzyDate
+	7,  // 7: model.v1.Organization.attributes:type_name -> google.protobuf.Struct
+	7,  // 8: model.v1.Website.attributes:type_name -> google.protobuf.Struct
+	2,  // 9: model.v1.Entity.source:type_name -> model.v1.Source
+	3,  // 10: model.v1.Entity.person:type_name -> model.v1.Person
+	4,  // 11: model.v1.Entity.organization:type_name -> model.v1.Organization
+	5,  // 12: model.v1.Entity.website:type_name -> model.v1.Website
+	1,  // 13: model.v1.Entity.event:type_name -> model.v1.Event
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_model_v1_osint_proto_init() }
@@ -1276,6 +1303,7 @@ func file_model_v1_osint_proto_init() {
 		return
 	}
 	file_model_v1_common_proto_init()
+	file_model_v1_rules_proto_init()
 	file_model_v1_osint_proto_msgTypes[6].OneofWrappers = []any{
 		(*Entity_Source)(nil),
 		(*Entity_Person)(nil),
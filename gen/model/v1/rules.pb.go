@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: model/v1/rules.proto
+
+package model
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FieldRules declares the constraints a field must satisfy. Unset rules are
+// not checked, and string rules only apply to non-empty values.
+type FieldRules struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Inclusive lower bound for numeric fields.
+	Min *float64 `protobuf:"fixed64,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	// Inclusive upper bound for numeric fields.
+	Max *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	// The value must parse as an absolute URL with a host.
+	AbsoluteUrl bool `protobuf:"varint,3,opt,name=absolute_url,json=absoluteUrl,proto3" json:"absolute_url,omitempty"`
+	// The value must be an ISO 3166-1 alpha-2 country code.
+	CountryCode bool `protobuf:"varint,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	// Name of a sibling numeric field this value must not be less than.
+	// Checked only when this field is non-zero.
+	NotBefore string `protobuf:"bytes,5,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	// The int64 value is a Unix timestamp in seconds; zero means unset.
+	Timestamp     bool `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	mi := &file_model_v1_rules_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_model_v1_rules_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_model_v1_rules_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *FieldRules) GetAbsoluteUrl() bool {
+	if x != nil {
+		return x.AbsoluteUrl
+	}
+	return false
+}
+
+func (x *FieldRules) GetCountryCode() bool {
+	if x != nil {
+		return x.CountryCode
+	}
+	return false
+}
+
+func (x *FieldRules) GetNotBefore() string {
+	if x != nil {
+		return x.NotBefore
+	}
+	return ""
+}
+
+func (x *FieldRules) GetTimestamp() bool {
+	if x != nil {
+		return x.Timestamp
+	}
+	return false
+}
+
+var file_model_v1_rules_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         50001,
+		Name:          "model.v1.rules",
+		Tag:           "bytes,50001,opt,name=rules",
+		Filename:      "model/v1/rules.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional model.v1.FieldRules rules = 50001;
+	E_Rules = &file_model_v1_rules_proto_extTypes[0]
+)
+
+var File_model_v1_rules_proto protoreflect.FileDescriptor
+
+const file_model_v1_rules_proto_rawDesc = "" +
+	"\n" +
+	"\x14model/v1/rules.proto\x12\bmodel.v1\x1a google/protobuf/descriptor.proto\"\xcd\x01\n" +
+	"\n" +
+	"FieldRules\x12\x15\n" +
+	"\x03min\x18\x01 \x01(\x01H\x00R\x03min\x88\x01\x01\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x01H\x01R\x03max\x88\x01\x01\x12!\n" +
+	"\fabsolute_url\x18\x03 \x01(\bR\vabsoluteUrl\x12!\n" +
+	"\fcountry_code\x18\x04 \x01(\bR\vcountryCode\x12\x1d\n" +
+	"\n" +
+	"not_before\x18\x05 \x01(\tR\tnotBefore\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\bR\ttimestampB\x06\n" +
+	"\x04_minB\x06\n" +
+	"\x04_max:K\n" +
+	"\x05rules\x12\x1d.google.protobuf.FieldOptions\x18ц\x03 \x01(\v2\x14.model.v1.FieldRulesR\x05rulesB:Z8github.com/omnsight/omniscent-library/gen/model/v1;modelb\x06proto3"
+
+var (
+	file_model_v1_rules_proto_rawDescOnce sync.Once
+	file_model_v1_rules_proto_rawDescData []byte
+)
+
+func file_model_v1_rules_proto_rawDescGZIP() []byte {
+	file_model_v1_rules_proto_rawDescOnce.Do(func() {
+		file_model_v1_rules_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_model_v1_rules_proto_rawDesc), len(file_model_v1_rules_proto_rawDesc)))
+	})
+	return file_model_v1_rules_proto_rawDescData
+}
+
+var file_model_v1_rules_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_model_v1_rules_proto_goTypes = []any{
+	(*FieldRules)(nil),                // 0: model.v1.FieldRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_model_v1_rules_proto_depIdxs = []int32{
+	1, // 0: model.v1.rules:extendee -> google.protobuf.FieldOptions
+	0, // 1: model.v1.rules:type_name -> model.v1.FieldRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_model_v1_rules_proto_init() }
+func file_model_v1_rules_proto_init() {
+	if File_model_v1_rules_proto != nil {
+		return
+	}
+	file_model_v1_rules_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_model_v1_rules_proto_rawDesc), len(file_model_v1_rules_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_model_v1_rules_proto_goTypes,
+		DependencyIndexes: file_model_v1_rules_proto_depIdxs,
+		MessageInfos:      file_model_v1_rules_proto_msgTypes,
+		ExtensionInfos:    file_model_v1_rules_proto_extTypes,
+	}.Build()
+	File_model_v1_rules_proto = out.File
+	file_model_v1_rules_proto_goTypes = nil
+	file_model_v1_rules_proto_depIdxs = nil
+}
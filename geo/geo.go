@@ -0,0 +1,139 @@
+// Package geo adds geospatial behavior to LocationData: great-circle and
+// ellipsoidal distances, bearings, regions (bounding boxes, circles and
+// polygons), geohash and hexagonal cell encodings, and in-memory spatial
+// indexes over Events or any other located values.
+//
+// Distances are in metres and angles in degrees. Coordinates are WGS-84.
+package geo
+
+import (
+	"errors"
+	"math"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/floatconv"
+)
+
+// EarthRadius is the mean Earth radius in metres used by the spherical
+// formulas.
+const EarthRadius = 6371008.8
+
+// WGS-84 ellipsoid used by Vincenty.
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// ErrNoConvergence is returned by Vincenty for nearly antipodal points.
+var ErrNoConvergence = errors.New("geo: vincenty formula failed to converge")
+
+// Point is a WGS-84 position.
+type Point struct {
+	Lat, Lon float64
+}
+
+// PointOf returns the coordinates of l. The boolean is false when l is nil
+// or has no coordinates (both zero).
+func PointOf(l *model.LocationData) (Point, bool) {
+	if l == nil || (l.GetLatitude() == 0 && l.GetLongitude() == 0) {
+		return Point{}, false
+	}
+	return Point{Lat: floatconv.Widen(l.GetLatitude()), Lon: floatconv.Widen(l.GetLongitude())}, true
+}
+
+// Location returns p as LocationData with only the coordinates set.
+func (p Point) Location() *model.LocationData {
+	return &model.LocationData{Latitude: float32(p.Lat), Longitude: float32(p.Lon)}
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+func degrees(r float64) float64 { return r * 180 / math.Pi }
+
+// normalizeLon wraps a longitude into [-180, 180).
+func normalizeLon(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+// Haversine returns the great-circle distance between a and b on a sphere
+// of radius EarthRadius. Its error against the ellipsoid is below 0.5%.
+func Haversine(a, b Point) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dPhi, dLambda := phi2-phi1, radians(b.Lon-a.Lon)
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Vincenty returns the geodesic distance between a and b on the WGS-84
+// ellipsoid, accurate to within a millimetre. It fails with
+// ErrNoConvergence for nearly antipodal points, where Haversine is the
+// fallback.
+func Vincenty(a, b Point) (float64, error) {
+	L := radians(b.Lon - a.Lon)
+	U1 := math.Atan((1 - wgs84F) * math.Tan(radians(a.Lat)))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(radians(b.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) + (cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+		if sinSigma == 0 {
+			return 0, nil
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		C := wgs84F / 16 * cos2Alpha * (4 + wgs84F*(4-3*cos2Alpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) < 1e-12 {
+			u2 := cos2Alpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+			A := 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+			B := u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+			dSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return wgs84B * A * (sigma - dSigma), nil
+		}
+	}
+	return 0, ErrNoConvergence
+}
+
+// Distance returns the Vincenty distance between a and b, falling back to
+// Haversine where Vincenty does not converge.
+func Distance(a, b Point) float64 {
+	if d, err := Vincenty(a, b); err == nil {
+		return d
+	}
+	return Haversine(a, b)
+}
+
+// Bearing returns the initial great-circle bearing from a to b in degrees
+// clockwise from north, in [0, 360).
+func Bearing(a, b Point) float64 {
+	phi1, phi2 := radians(a.Lat), radians(b.Lat)
+	dLambda := radians(b.Lon - a.Lon)
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached from p after travelling distance
+// metres along the great circle with the given initial bearing.
+func Destination(p Point, bearing, distance float64) Point {
+	delta := distance / EarthRadius
+	theta := radians(bearing)
+	phi1, lambda1 := radians(p.Lat), radians(p.Lon)
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+	return Point{Lat: degrees(phi2), Lon: normalizeLon(degrees(lambda2))}
+}
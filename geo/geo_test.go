@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func dms(d, m, s float64) float64 {
+	if d < 0 {
+		return d - m/60 - s/3600
+	}
+	return d + m/60 + s/3600
+}
+
+func TestVincenty(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		// Vincenty's own example, Flinders Peak to Buninyong.
+		{"flinders-buninyong", Point{dms(-37, 57, 3.72030), dms(144, 25, 29.52440)}, Point{dms(-37, 39, 10.15610), dms(143, 55, 35.38390)}, 54972.271},
+		{"equator degree", Point{0, 0}, Point{0, 1}, wgs84A * math.Pi / 180},
+		{"quarter meridian", Point{0, 0}, Point{90, 0}, 10001965.729},
+		{"antimeridian", Point{0, 179.5}, Point{0, -179.5}, wgs84A * math.Pi / 180},
+		{"same point", Point{51.5, -0.1}, Point{51.5, -0.1}, 0},
+	}
+	for _, tt := range tests {
+		got, err := Vincenty(tt.a, tt.b)
+		if err != nil || math.Abs(got-tt.want) > 1e-3 {
+			t.Errorf("Vincenty %s = %.4f, %v, want %.4f", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestVincentyAntipodal(t *testing.T) {
+	a, b := Point{0, 0}, Point{0.5, 179.7}
+	if _, err := Vincenty(a, b); !errors.Is(err, ErrNoConvergence) {
+		t.Fatalf("Vincenty(%v, %v) error = %v, want ErrNoConvergence", a, b, err)
+	}
+	if got, want := Distance(a, b), Haversine(a, b); got != want {
+		t.Errorf("Distance = %f, want the Haversine fallback %f", got, want)
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"equator degree", Point{0, 0}, Point{0, 1}, EarthRadius * math.Pi / 180},
+		{"pole to pole", Point{90, 0}, Point{-90, 0}, EarthRadius * math.Pi},
+		{"antimeridian", Point{0, 179.5}, Point{0, -179.5}, EarthRadius * math.Pi / 180},
+		{"meridians meet at the pole", Point{90, 0}, Point{90, 120}, 0},
+	}
+	for _, tt := range tests {
+		if got := Haversine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("Haversine %s = %.6f, want %.6f", tt.name, got, tt.want)
+		}
+	}
+	// The spherical distance stays within 0.5% of the geodesic.
+	london, paris := Point{51.5074, -0.1278}, Point{48.8566, 2.3522}
+	v, _ := Vincenty(london, paris)
+	if h := Haversine(london, paris); math.Abs(h-v)/v > 0.005 {
+		t.Errorf("Haversine London-Paris = %.0f, Vincenty %.0f", h, v)
+	}
+}
+
+func TestBearingDestination(t *testing.T) {
+	tests := []struct {
+		a, b Point
+		want float64
+	}{
+		{Point{0, 0}, Point{10, 0}, 0},
+		{Point{0, 0}, Point{0, 10}, 90},
+		{Point{0, 0}, Point{-10, 0}, 180},
+		{Point{0, 179}, Point{0, -179}, 90},
+		{Point{0, -179}, Point{0, 179}, 270},
+	}
+	for _, tt := range tests {
+		if got := Bearing(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Bearing(%v, %v) = %f, want %f", tt.a, tt.b, got, tt.want)
+		}
+	}
+	got := Destination(Point{0, 179.5}, 90, EarthRadius*math.Pi/180)
+	if math.Abs(got.Lat) > 1e-9 || math.Abs(got.Lon+179.5) > 1e-9 {
+		t.Errorf("Destination across the antimeridian = %v, want {0 -179.5}", got)
+	}
+}
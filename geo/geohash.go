@@ -0,0 +1,79 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidGeohash is returned for strings that are not geohashes.
+var ErrInvalidGeohash = errors.New("geo: invalid geohash")
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash encodes p as a geohash of precision characters (1-12).
+func Geohash(p Point, precision int) string {
+	precision = min(max(precision, 1), 12)
+	lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+	var b strings.Builder
+	even, bit, ch := true, 0, 0
+	for b.Len() < precision {
+		rng, v := &lat, p.Lat
+		if even {
+			rng, v = &lon, p.Lon
+		}
+		mid := (rng[0] + rng[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			rng[0] = mid
+		} else {
+			rng[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			b.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// DecodeGeohash returns the cell described by hash.
+func DecodeGeohash(hash string) (BBox, error) {
+	if hash == "" {
+		return BBox{}, ErrInvalidGeohash
+	}
+	lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+	even := true
+	for _, c := range strings.ToLower(hash) {
+		v := strings.IndexRune(geohashAlphabet, c)
+		if v < 0 {
+			return BBox{}, fmt.Errorf("%w: %q", ErrInvalidGeohash, hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			rng := &lat
+			if even {
+				rng = &lon
+			}
+			mid := (rng[0] + rng[1]) / 2
+			if v>>bit&1 == 1 {
+				rng[0] = mid
+			} else {
+				rng[1] = mid
+			}
+			even = !even
+		}
+	}
+	return BBox{MinLat: lat[0], MinLon: lon[0], MaxLat: lat[1], MaxLon: lon[1]}, nil
+}
+
+// Center returns the midpoint of b. For boxes crossing the antimeridian
+// the longitude is wrapped.
+func (b BBox) Center() Point {
+	maxLon := b.MaxLon
+	if b.MinLon > maxLon {
+		maxLon += 360
+	}
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lon: normalizeLon((b.MinLon + maxLon) / 2)}
+}
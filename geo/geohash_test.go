@@ -0,0 +1,60 @@
+package geo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		p         Point
+		precision int
+		want      string
+	}{
+		{Point{57.64911, 10.40744}, 11, "u4pruydqqvj"},
+		{Point{48.8584, 2.2945}, 7, "u09tunq"},
+		{Point{-33.8568, 151.2153}, 6, "r3gx2u"},
+		{Point{0, 0}, 1, "s"},
+		{Point{-90, -180}, 5, "00000"},
+		{Point{51.5, -0.12}, 0, "g"},
+		{Point{51.5, -0.12}, 20, "gcpuvr295zcd"},
+	}
+	for _, tt := range tests {
+		if got := Geohash(tt.p, tt.precision); got != tt.want {
+			t.Errorf("Geohash(%v, %d) = %q, want %q", tt.p, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestGeohashRoundTrip(t *testing.T) {
+	points := []Point{
+		{57.64911, 10.40744},
+		{-33.8568, 151.2153},
+		{40.7128, -74.006},
+		{-54.8019, -68.303},
+		{89.9, 179.9},
+	}
+	for _, p := range points {
+		for precision := 1; precision <= 12; precision++ {
+			hash := Geohash(p, precision)
+			box, err := DecodeGeohash(hash)
+			if err != nil {
+				t.Fatalf("DecodeGeohash(%q): %v", hash, err)
+			}
+			if !box.Contains(p) {
+				t.Errorf("cell %q = %v does not contain %v", hash, box, p)
+			}
+			if again := Geohash(box.Center(), precision); again != hash {
+				t.Errorf("Geohash(center of %q) = %q", hash, again)
+			}
+		}
+	}
+}
+
+func TestDecodeGeohashInvalid(t *testing.T) {
+	for _, hash := range []string{"", "a", "u4pi", "u4p ru"} {
+		if _, err := DecodeGeohash(hash); !errors.Is(err, ErrInvalidGeohash) {
+			t.Errorf("DecodeGeohash(%q) error = %v, want ErrInvalidGeohash", hash, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrInvalidCell is returned for values that are not valid cells.
+var ErrInvalidCell = errors.New("geo: invalid cell")
+
+// MaxResolution is the finest cell resolution.
+const MaxResolution = 15
+
+// Cell is a hexagonal grid cell in the style of H3: a 64-bit index over a
+// hierarchy of resolutions 0-15 whose edge lengths at the equator follow
+// H3's average edge lengths, from about 1100 km down to 0.5 m. Cells are
+// regular hexagons on the Web Mercator plane, so they shrink towards the
+// poles and latitudes are clamped to ±85.05°. The grid does not wrap at
+// the antimeridian: cells straddling it have centers and boundaries up to
+// one cell beyond ±180° longitude, and CellOf maps such points back to
+// them. The indexes are not compatible with Uber's H3 library.
+//
+// Layout: 4 bits resolution, then 30 bits each for the axial q and r
+// coordinates offset by 2^29.
+type Cell uint64
+
+const (
+	mercatorRadius = 6378137.0
+	maxMercatorLat = 85.05112878
+	baseEdge       = 1107712.591 // H3 resolution 0 average edge, metres
+	axialBits      = 30
+	axialOffset    = 1 << (axialBits - 1)
+	axialMask      = 1<<axialBits - 1
+)
+
+var sqrt3 = math.Sqrt(3)
+
+// edge returns the hexagon edge length of a resolution on the Mercator
+// plane.
+func edge(res int) float64 {
+	return baseEdge / math.Pow(math.Sqrt(7), float64(res))
+}
+
+func mercator(p Point) (x, y float64) {
+	lat := math.Max(-maxMercatorLat, math.Min(maxMercatorLat, p.Lat))
+	return mercatorRadius * radians(p.Lon), mercatorRadius * math.Log(math.Tan(math.Pi/4+radians(lat)/2))
+}
+
+func inverseMercator(x, y float64) Point {
+	return Point{
+		Lat: degrees(2*math.Atan(math.Exp(y/mercatorRadius)) - math.Pi/2),
+		Lon: degrees(x / mercatorRadius),
+	}
+}
+
+// CellOf returns the cell of resolution res holding p.
+func CellOf(p Point, res int) Cell {
+	res = min(max(res, 0), MaxResolution)
+	x, y := mercator(p)
+	s := edge(res)
+	q := (sqrt3/3*x - y/3) / s
+	r := (2. / 3 * y) / s
+	qi, ri := roundAxial(q, r)
+	return newCell(res, qi, ri)
+}
+
+// roundAxial rounds fractional axial coordinates to the nearest hexagon.
+func roundAxial(q, r float64) (int, int) {
+	s := -q - r
+	rq, rr, rs := math.Round(q), math.Round(r), math.Round(s)
+	dq, dr, ds := math.Abs(rq-q), math.Abs(rr-r), math.Abs(rs-s)
+	switch {
+	case dq > dr && dq > ds:
+		rq = -rr - rs
+	case dr > ds:
+		rr = -rq - rs
+	}
+	return int(rq), int(rr)
+}
+
+func newCell(res, q, r int) Cell {
+	return Cell(uint64(res)<<(2*axialBits) |
+		uint64(q+axialOffset)&axialMask<<axialBits |
+		uint64(r+axialOffset)&axialMask)
+}
+
+// Resolution returns the resolution of c.
+func (c Cell) Resolution() int {
+	return int(c >> (2 * axialBits))
+}
+
+func (c Cell) axial() (q, r int) {
+	return int(c>>axialBits&axialMask) - axialOffset, int(c&axialMask) - axialOffset
+}
+
+// Center returns the center of c.
+func (c Cell) Center() Point {
+	q, r := c.axial()
+	s := edge(c.Resolution())
+	return inverseMercator(s*(sqrt3*float64(q)+sqrt3/2*float64(r)), s*1.5*float64(r))
+}
+
+// Boundary returns the six vertices of c, counter-clockwise.
+func (c Cell) Boundary() []Point {
+	q, r := c.axial()
+	s := edge(c.Resolution())
+	cx, cy := s*(sqrt3*float64(q)+sqrt3/2*float64(r)), s*1.5*float64(r)
+	out := make([]Point, 6)
+	for i := range out {
+		a := radians(float64(60*i - 30))
+		out[i] = inverseMercator(cx+s*math.Cos(a), cy+s*math.Sin(a))
+	}
+	return out
+}
+
+// Neighbors returns the six cells sharing an edge with c.
+func (c Cell) Neighbors() []Cell {
+	q, r := c.axial()
+	res := c.Resolution()
+	dirs := [6][2]int{{1, 0}, {1, -1}, {0, -1}, {-1, 0}, {-1, 1}, {0, 1}}
+	out := make([]Cell, len(dirs))
+	for i, d := range dirs {
+		out[i] = newCell(res, q+d[0], r+d[1])
+	}
+	return out
+}
+
+// Parent returns the cell of resolution res containing the center of c.
+// Hexagons do not nest exactly, so a child may straddle its parent's edge.
+func (c Cell) Parent(res int) Cell {
+	if res >= c.Resolution() {
+		return c
+	}
+	return CellOf(c.Center(), res)
+}
+
+// String returns c as 16 hexadecimal digits.
+func (c Cell) String() string {
+	return fmt.Sprintf("%016x", uint64(c))
+}
+
+// ParseCell parses a cell written by String.
+func ParseCell(s string) (Cell, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || Cell(v).Resolution() > MaxResolution {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCell, s)
+	}
+	return Cell(v), nil
+}
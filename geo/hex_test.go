@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+var cellPoints = []Point{
+	{0, 0},
+	{51.5074, -0.1278},
+	{-33.8688, 151.2093},
+	{35.6762, 139.6503},
+	{64.1466, -21.9426},
+	{-0.0001, 179.9999},
+}
+
+func TestCellRoundTrip(t *testing.T) {
+	for _, p := range cellPoints {
+		for res := 0; res <= MaxResolution; res++ {
+			c := CellOf(p, res)
+			if c.Resolution() != res {
+				t.Errorf("CellOf(%v, %d).Resolution() = %d", p, res, c.Resolution())
+			}
+			if again := CellOf(c.Center(), res); again != c {
+				t.Errorf("CellOf(center of %s) = %s", c, again)
+			}
+			parsed, err := ParseCell(c.String())
+			if err != nil || parsed != c {
+				t.Errorf("ParseCell(%q) = %s, %v", c.String(), parsed, err)
+			}
+			// The center lies within one edge length, on the Mercator
+			// plane, of every point of the cell.
+			x, y := mercator(p)
+			cx, cy := mercator(c.Center())
+			if d := math.Hypot(x-cx, y-cy); d > edge(res)*(1+1e-9) {
+				t.Errorf("%v is %.3f m from the center of %s, edge %.3f m", p, d, c, edge(res))
+			}
+		}
+	}
+}
+
+func TestCellNeighbors(t *testing.T) {
+	for _, p := range cellPoints {
+		c := CellOf(p, 9)
+		for _, n := range c.Neighbors() {
+			if n == c || CellOf(n.Center(), 9) != n {
+				t.Errorf("neighbor %s of %s does not round trip", n, c)
+			}
+			if !slices.Contains(n.Neighbors(), c) {
+				t.Errorf("%s is not a neighbor of its neighbor %s", c, n)
+			}
+		}
+	}
+}
+
+func TestCellParent(t *testing.T) {
+	c := CellOf(Point{51.5074, -0.1278}, 10)
+	if got := c.Parent(12); got != c {
+		t.Errorf("Parent(12) = %s, want %s", got, c)
+	}
+	if got := c.Parent(5); got.Resolution() != 5 || got != CellOf(c.Center(), 5) {
+		t.Errorf("Parent(5) = %s", got)
+	}
+}
+
+func TestParseCellInvalid(t *testing.T) {
+	for _, s := range []string{"", "zz", "g000000000000000", "-1", "1234567890abcdef0"} {
+		if _, err := ParseCell(s); !errors.Is(err, ErrInvalidCell) {
+			t.Errorf("ParseCell(%q) error = %v, want ErrInvalidCell", s, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package geo
+
+import (
+	"math"
+	"sort"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// DefaultCellSize is the grid spacing of an Index in degrees, about 55 km
+// at the equator.
+const DefaultCellSize = 0.5
+
+// Grid is an in-memory grid index of values at points, answering radius,
+// region and nearest-neighbour queries without a database. It is not safe
+// for concurrent modification.
+type Grid[T any] struct {
+	size  float64
+	cols  int
+	cells map[[2]int][]Near[T]
+	n     int
+}
+
+// Near is a value found by a Grid query, with its distance from the query
+// point in metres. Region queries leave Distance zero.
+type Near[T any] struct {
+	Value    T
+	Point    Point
+	Distance float64
+}
+
+// NewGrid returns an empty grid with cells of size degrees, or
+// DefaultCellSize if size is not positive. Smaller cells suit dense data
+// queried with small radii.
+func NewGrid[T any](size float64) *Grid[T] {
+	if size <= 0 {
+		size = DefaultCellSize
+	}
+	return &Grid[T]{size: size, cols: int(math.Ceil(360 / size)), cells: map[[2]int][]Near[T]{}}
+}
+
+// Insert adds v at p.
+func (g *Grid[T]) Insert(p Point, v T) {
+	k := [2]int{g.row(p.Lat), g.col(p.Lon)}
+	g.cells[k] = append(g.cells[k], Near[T]{Value: v, Point: p})
+	g.n++
+}
+
+// Len returns the number of values.
+func (g *Grid[T]) Len() int {
+	return g.n
+}
+
+func (g *Grid[T]) row(lat float64) int {
+	return int(math.Floor((lat + 90) / g.size))
+}
+
+func (g *Grid[T]) col(lon float64) int {
+	return min(int(math.Floor((normalizeLon(lon)+180)/g.size)), g.cols-1)
+}
+
+// scan calls fn for every value in the cells overlapping b.
+func (g *Grid[T]) scan(b BBox, fn func(Near[T])) {
+	cols := [][2]int{{g.col(b.MinLon), g.col(b.MaxLon)}}
+	if b.MinLon > b.MaxLon {
+		cols = [][2]int{{g.col(b.MinLon), g.cols - 1}, {0, g.col(b.MaxLon)}}
+	}
+	if b.MinLon <= -180 && b.MaxLon >= 180 {
+		cols = [][2]int{{0, g.cols - 1}}
+	}
+	for r := g.row(b.MinLat); r <= g.row(b.MaxLat); r++ {
+		for _, span := range cols {
+			for c := span[0]; c <= span[1]; c++ {
+				for _, it := range g.cells[[2]int{r, c}] {
+					fn(it)
+				}
+			}
+		}
+	}
+}
+
+// Within returns the values within radius metres of center, nearest
+// first.
+func (g *Grid[T]) Within(center Point, radius float64) []Near[T] {
+	var out []Near[T]
+	g.scan(Around(center, radius), func(it Near[T]) {
+		if d := Haversine(center, it.Point); d <= radius {
+			it.Distance = d
+			out = append(out, it)
+		}
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Distance < out[j].Distance })
+	return out
+}
+
+// In returns the values inside r. Regions with a Bounds method, such as
+// all the region types of this package, only scan the cells they overlap.
+func (g *Grid[T]) In(r Region) []Near[T] {
+	b := BBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+	if bounded, ok := r.(interface{ Bounds() BBox }); ok {
+		b = bounded.Bounds()
+	}
+	var out []Near[T]
+	g.scan(b, func(it Near[T]) {
+		if r.Contains(it.Point) {
+			out = append(out, it)
+		}
+	})
+	return out
+}
+
+// Nearest returns the k values closest to p, nearest first.
+func (g *Grid[T]) Nearest(p Point, k int) []Near[T] {
+	return g.NearestWithin(p, k, math.Pi*EarthRadius)
+}
+
+// NearestWithin returns the k values closest to p that are at most
+// radius metres away, nearest first.
+func (g *Grid[T]) NearestWithin(p Point, k int, radius float64) []Near[T] {
+	if k <= 0 || g.n == 0 {
+		return nil
+	}
+	r := math.Min(radius, g.size*math.Pi/180*EarthRadius)
+	for {
+		hits := g.Within(p, r)
+		if len(hits) >= k || r >= radius {
+			return hits[:min(k, len(hits))]
+		}
+		r = math.Min(r*2, radius)
+	}
+}
+
+// Index is a Grid of located events.
+type Index struct {
+	grid *Grid[*model.Event]
+}
+
+// Hit is an event found by an Index query, with its distance from the
+// query point in metres.
+type Hit struct {
+	Event    *model.Event
+	Distance float64
+}
+
+// NewIndex returns an empty index with cells of size degrees, as for
+// NewGrid.
+func NewIndex(size float64) *Index {
+	return &Index{grid: NewGrid[*model.Event](size)}
+}
+
+// IndexEvents returns an index with DefaultCellSize holding events.
+func IndexEvents(events []*model.Event) *Index {
+	ix := NewIndex(0)
+	for _, e := range events {
+		ix.Add(e)
+	}
+	return ix
+}
+
+// Add indexes e. It returns false, leaving the index unchanged, when e has
+// no coordinates.
+func (ix *Index) Add(e *model.Event) bool {
+	p, ok := PointOf(e.GetLocation())
+	if ok {
+		ix.grid.Insert(p, e)
+	}
+	return ok
+}
+
+// Len returns the number of indexed events.
+func (ix *Index) Len() int {
+	return ix.grid.Len()
+}
+
+// Within returns the events within radius metres of center, nearest
+// first.
+func (ix *Index) Within(center Point, radius float64) []Hit {
+	return hits(ix.grid.Within(center, radius))
+}
+
+// InBox returns the events inside b.
+func (ix *Index) InBox(b BBox) []*model.Event {
+	return ix.In(b)
+}
+
+// In returns the events inside r.
+func (ix *Index) In(r Region) []*model.Event {
+	var out []*model.Event
+	for _, n := range ix.grid.In(r) {
+		out = append(out, n.Value)
+	}
+	return out
+}
+
+// Nearest returns the k events closest to p, nearest first.
+func (ix *Index) Nearest(p Point, k int) []Hit {
+	return hits(ix.grid.Nearest(p, k))
+}
+
+func hits(ns []Near[*model.Event]) []Hit {
+	out := make([]Hit, len(ns))
+	for i, n := range ns {
+		out[i] = Hit{Event: n.Value, Distance: n.Distance}
+	}
+	return out
+}
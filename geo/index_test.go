@@ -0,0 +1,172 @@
+package geo
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func values(ns []Near[string]) []string {
+	out := make([]string, len(ns))
+	for i, n := range ns {
+		out[i] = n.Value
+	}
+	return out
+}
+
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testGrid() *Grid[string] {
+	g := NewGrid[string](0)
+	for _, it := range []struct {
+		p Point
+		v string
+	}{
+		{Point{51.5074, -0.1278}, "london"},
+		{Point{48.8566, 2.3522}, "paris"},
+		{Point{50.8503, 4.3517}, "brussels"},
+		{Point{0, 179.9}, "east"},
+		{Point{0, -179.95}, "west"},
+		{Point{0.2, 180}, "meridian"},
+		{Point{89.95, 10}, "north1"},
+		{Point{89.95, -170}, "north2"},
+		{Point{90, 0}, "pole"},
+	} {
+		g.Insert(it.p, it.v)
+	}
+	return g
+}
+
+func TestGridWithin(t *testing.T) {
+	g := testGrid()
+	if g.Len() != 9 {
+		t.Fatalf("Len = %d, want 9", g.Len())
+	}
+	tests := []struct {
+		name   string
+		center Point
+		radius float64
+		want   []string
+	}{
+		{"paris", Point{48.8566, 2.3522}, 300000, []string{"paris", "brussels"}},
+		{"paris wide", Point{48.8566, 2.3522}, 400000, []string{"paris", "brussels", "london"}},
+		{"antimeridian", Point{0, -179.99}, 30000, []string{"west", "east", "meridian"}},
+		{"pole", Point{89.99, 0}, 20000, []string{"pole", "north1", "north2"}},
+		{"nothing", Point{0, 0}, 1000, []string{}},
+	}
+	for _, tt := range tests {
+		got := g.Within(tt.center, tt.radius)
+		if !sameValues(values(got), tt.want) {
+			t.Errorf("Within %s = %v, want %v", tt.name, values(got), tt.want)
+		}
+		for i, n := range got {
+			if n.Distance != Haversine(tt.center, n.Point) || n.Distance > tt.radius {
+				t.Errorf("Within %s [%d] distance = %f", tt.name, i, n.Distance)
+			}
+		}
+	}
+}
+
+func TestGridNearest(t *testing.T) {
+	g := testGrid()
+	tests := []struct {
+		p    Point
+		k    int
+		want []string
+	}{
+		{Point{49, 2}, 1, []string{"paris"}},
+		{Point{49, 2}, 3, []string{"paris", "brussels", "london"}},
+		{Point{0.1, 179.99}, 2, []string{"meridian", "west"}},
+		{Point{-60, 0}, 1, []string{"paris"}},
+		{Point{49, 2}, 0, nil},
+	}
+	for _, tt := range tests {
+		if got := values(g.Nearest(tt.p, tt.k)); !sameValues(got, tt.want) {
+			t.Errorf("Nearest(%v, %d) = %v, want %v", tt.p, tt.k, got, tt.want)
+		}
+	}
+	if got := g.Nearest(Point{}, 20); len(got) != 9 {
+		t.Errorf("Nearest(k > Len) returned %d values, want 9", len(got))
+	}
+	if got := g.NearestWithin(Point{49, 2}, 3, 300000); !sameValues(values(got), []string{"paris", "brussels"}) {
+		t.Errorf("NearestWithin = %v", values(got))
+	}
+	if got := NewGrid[string](1).Nearest(Point{}, 1); got != nil {
+		t.Errorf("Nearest on an empty grid = %v", got)
+	}
+}
+
+func TestGridIn(t *testing.T) {
+	g := testGrid()
+	tests := []struct {
+		name string
+		r    Region
+		want []string
+	}{
+		{"box across the antimeridian", BBox{MinLat: -1, MinLon: 179.8, MaxLat: 1, MaxLon: -179.8}, []string{"east", "west", "meridian"}},
+		{"circle at the pole", Circle{Point{90, 0}, 20000}, []string{"north1", "north2", "pole"}},
+		{"polygon with a hole", Polygon{square(45, -5, 55, 10), square(50, 0, 52, 5)}, []string{"paris", "london"}},
+		{"unbounded region", regionFunc(func(p Point) bool { return p.Lat < 1 && p.Lat > -1 }), []string{"east", "west", "meridian"}},
+	}
+	for _, tt := range tests {
+		got := values(g.In(tt.r))
+		if !sameSet(got, tt.want) {
+			t.Errorf("In %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type regionFunc func(Point) bool
+
+func (f regionFunc) Contains(p Point) bool { return f(p) }
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]int{}
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		seen[s]--
+	}
+	for _, n := range seen {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndex(t *testing.T) {
+	paris := &model.Event{Key: "paris", Location: &model.LocationData{Latitude: 48.8566, Longitude: 2.3522}}
+	ix := IndexEvents([]*model.Event{
+		paris,
+		{Key: "nowhere"},
+		{Key: "london", Location: &model.LocationData{Latitude: 51.5074, Longitude: -0.1278}},
+	})
+	if ix.Len() != 2 {
+		t.Fatalf("Len = %d, want 2 as events without coordinates are skipped", ix.Len())
+	}
+	hits := ix.Nearest(Point{49, 2}, 1)
+	if len(hits) != 1 || hits[0].Event != paris || hits[0].Distance == 0 {
+		t.Errorf("Nearest = %+v", hits)
+	}
+	if got := ix.InBox(BBox{MinLat: 50, MinLon: -1, MaxLat: 52, MaxLon: 1}); len(got) != 1 || got[0].GetKey() != "london" {
+		t.Errorf("InBox = %v", got)
+	}
+	if got := ix.Within(Point{48.8566, 2.3522}, 1000); len(got) != 1 || got[0].Event != paris {
+		t.Errorf("Within = %+v", got)
+	}
+}
@@ -0,0 +1,18 @@
+package geo
+
+import (
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/postcode"
+)
+
+// PostalCode is postcode.Of: it returns the postcode of l, falling back to
+// the deprecated numeric postal_code field.
+func PostalCode(l *model.LocationData) string {
+	return postcode.Of(l)
+}
+
+// SetPostalCode is postcode.Set: it sets the postcode of l and keeps the
+// deprecated numeric field in step.
+func SetPostalCode(l *model.LocationData, code string) {
+	postcode.Set(l, code)
+}
@@ -0,0 +1,208 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geojson"
+)
+
+// ErrUnsupportedGeometry is returned by ParseRegion for geometries that do
+// not describe an area.
+var ErrUnsupportedGeometry = errors.New("geo: unsupported geometry")
+
+// Region is an area that points can be tested against.
+type Region interface {
+	Contains(p Point) bool
+}
+
+// Filter returns a predicate selecting the events located in r, for use
+// with timeline.Timeline.Filter and similar helpers.
+func Filter(r Region) func(*model.Event) bool {
+	return func(e *model.Event) bool {
+		p, ok := PointOf(e.GetLocation())
+		return ok && r.Contains(p)
+	}
+}
+
+// BBox is a latitude/longitude rectangle. A box whose MinLon is greater
+// than its MaxLon crosses the antimeridian.
+type BBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Contains reports whether p lies inside b, edges included.
+func (b BBox) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+	}
+	return p.Lon >= b.MinLon || p.Lon <= b.MaxLon
+}
+
+// Around returns the smallest box holding the circle of radius metres
+// around center. Near the poles the box spans all longitudes.
+func Around(center Point, radius float64) BBox {
+	dLat := degrees(radius / EarthRadius)
+	b := BBox{MinLat: center.Lat - dLat, MaxLat: center.Lat + dLat}
+	if b.MinLat <= -90 || b.MaxLat >= 90 {
+		b.MinLat, b.MaxLat = math.Max(b.MinLat, -90), math.Min(b.MaxLat, 90)
+		b.MinLon, b.MaxLon = -180, 180
+		return b
+	}
+	dLon := degrees(math.Asin(math.Sin(radius/EarthRadius) / math.Cos(radians(center.Lat))))
+	if math.IsNaN(dLon) || dLon >= 180 {
+		b.MinLon, b.MaxLon = -180, 180
+		return b
+	}
+	b.MinLon, b.MaxLon = normalizeLon(center.Lon-dLon), normalizeLon(center.Lon+dLon)
+	return b
+}
+
+// Circle is the set of points within Radius metres of Center.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Contains reports whether p is within the circle by great-circle
+// distance.
+func (c Circle) Contains(p Point) bool {
+	return Haversine(c.Center, p) <= c.Radius
+}
+
+// Ring is a closed sequence of vertices; the last vertex connects back to
+// the first.
+type Ring []Point
+
+// Polygon is an outer ring followed by optional holes. Edges are straight
+// lines in longitude/latitude, as in GeoJSON; polygons crossing the
+// antimeridian must be split.
+type Polygon []Ring
+
+// Contains reports whether p is inside the outer ring and outside every
+// hole.
+func (pg Polygon) Contains(p Point) bool {
+	if len(pg) == 0 || !pg[0].contains(p) {
+		return false
+	}
+	for _, hole := range pg[1:] {
+		if hole.contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains applies the even-odd ray casting rule.
+func (r Ring) contains(p Point) bool {
+	in := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			in = !in
+		}
+	}
+	return in
+}
+
+// MultiPolygon is a union of polygons.
+type MultiPolygon []Polygon
+
+// Contains reports whether p is inside any of the polygons.
+func (mp MultiPolygon) Contains(p Point) bool {
+	for _, pg := range mp {
+		if pg.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// GeoJSON geometry types read by ParseRegion.
+const (
+	typePolygon      = "Polygon"
+	typeMultiPolygon = "MultiPolygon"
+)
+
+// ParseRegion converts a GeoJSON Polygon or MultiPolygon geometry to a
+// Region.
+func ParseRegion(g *geojson.Geometry) (Region, error) {
+	if g == nil {
+		return nil, ErrUnsupportedGeometry
+	}
+	switch g.Type {
+	case typePolygon:
+		var c [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &c); err != nil {
+			return nil, fmt.Errorf("geo: polygon coordinates: %w", err)
+		}
+		return polygon(c)
+	case typeMultiPolygon:
+		var c [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &c); err != nil {
+			return nil, fmt.Errorf("geo: multipolygon coordinates: %w", err)
+		}
+		mp := make(MultiPolygon, 0, len(c))
+		for _, pc := range c {
+			pg, err := polygon(pc)
+			if err != nil {
+				return nil, err
+			}
+			mp = append(mp, pg)
+		}
+		return mp, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedGeometry, g.Type)
+}
+
+func polygon(c [][][]float64) (Polygon, error) {
+	pg := make(Polygon, 0, len(c))
+	for _, rc := range c {
+		ring := make(Ring, 0, len(rc))
+		for _, pos := range rc {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("geo: position has %d coordinates", len(pos))
+			}
+			ring = append(ring, Point{Lat: pos[1], Lon: pos[0]})
+		}
+		pg = append(pg, ring)
+	}
+	return pg, nil
+}
+
+// Bounds returns b itself.
+func (b BBox) Bounds() BBox { return b }
+
+// Bounds returns the box around the circle.
+func (c Circle) Bounds() BBox { return Around(c.Center, c.Radius) }
+
+// Bounds returns the box around the outer ring.
+func (pg Polygon) Bounds() BBox {
+	b := BBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	if len(pg) == 0 {
+		return b
+	}
+	for _, p := range pg[0] {
+		b.MinLat, b.MaxLat = math.Min(b.MinLat, p.Lat), math.Max(b.MaxLat, p.Lat)
+		b.MinLon, b.MaxLon = math.Min(b.MinLon, p.Lon), math.Max(b.MaxLon, p.Lon)
+	}
+	return b
+}
+
+// Bounds returns the box around all polygons.
+func (mp MultiPolygon) Bounds() BBox {
+	b := BBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, pg := range mp {
+		pb := pg.Bounds()
+		b.MinLat, b.MaxLat = math.Min(b.MinLat, pb.MinLat), math.Max(b.MaxLat, pb.MaxLat)
+		b.MinLon, b.MaxLon = math.Min(b.MinLon, pb.MinLon), math.Max(b.MaxLon, pb.MaxLon)
+	}
+	return b
+}
@@ -0,0 +1,158 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/geojson"
+)
+
+func TestAround(t *testing.T) {
+	tests := []struct {
+		name   string
+		center Point
+		radius float64
+		in     []Point
+		out    []Point
+		full   bool
+	}{
+		{
+			"antimeridian", Point{0, 179.9}, 50000,
+			[]Point{{0, 179.9}, {0, -179.8}, {0.3, 180}},
+			[]Point{{0, 179}, {0, -179}, {1, 179.9}},
+			false,
+		},
+		{
+			"antimeridian west", Point{-10, -179.95}, 20000,
+			[]Point{{-10, 179.95}, {-10, -179.9}},
+			[]Point{{-10, 179.5}, {-10, -179.5}},
+			false,
+		},
+		{
+			"north pole", Point{89.9, 0}, 50000,
+			[]Point{{90, 0}, {89.8, 180}, {89.9, -90}},
+			[]Point{{89, 0}},
+			true,
+		},
+		{
+			"south pole", Point{-89.95, 120}, 10000,
+			[]Point{{-90, 0}, {-89.96, -60}},
+			[]Point{{-89.8, 120}},
+			true,
+		},
+		{
+			"circle wider than its latitude", Point{80, 10}, 1500000,
+			[]Point{{80, -170}},
+			[]Point{{60, 10}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		b := Around(tt.center, tt.radius)
+		if full := b.MinLon == -180 && b.MaxLon == 180; full != tt.full {
+			t.Errorf("Around %s = %+v, spans all longitudes %v, want %v", tt.name, b, full, tt.full)
+		}
+		if b.MinLat < -90 || b.MaxLat > 90 {
+			t.Errorf("Around %s = %+v, latitude out of range", tt.name, b)
+		}
+		for _, p := range tt.in {
+			if !b.Contains(p) {
+				t.Errorf("Around %s = %+v does not contain %v", tt.name, b, p)
+			}
+		}
+		for _, p := range tt.out {
+			if b.Contains(p) {
+				t.Errorf("Around %s = %+v contains %v", tt.name, b, p)
+			}
+		}
+	}
+}
+
+// square returns the ring of the square with corners (lat0, lon0) and
+// (lat1, lon1).
+func square(lat0, lon0, lat1, lon1 float64) Ring {
+	return Ring{{lat0, lon0}, {lat0, lon1}, {lat1, lon1}, {lat1, lon0}, {lat0, lon0}}
+}
+
+func TestPolygonHoles(t *testing.T) {
+	pg := Polygon{square(0, 0, 10, 10), square(2, 2, 4, 4), square(6, 6, 8, 8)}
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{1, 1}, true},
+		{Point{5, 5}, true},
+		{Point{3, 3}, false},
+		{Point{7, 7}, false},
+		{Point{3, 5}, true},
+		{Point{11, 5}, false},
+		{Point{-1, 5}, false},
+	}
+	for _, tt := range tests {
+		if got := pg.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got, want := pg.Bounds(), (BBox{0, 0, 10, 10}); got != want {
+		t.Errorf("Bounds = %+v, want %+v", got, want)
+	}
+	if (Polygon{}).Contains(Point{}) {
+		t.Error("empty polygon contains a point")
+	}
+}
+
+func TestParseRegion(t *testing.T) {
+	tests := []struct {
+		geometry string
+		in, out  []Point
+	}{
+		{
+			`{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[2,2],[4,2],[4,4],[2,4],[2,2]]]}`,
+			[]Point{{1, 1}, {5, 9}},
+			[]Point{{3, 3}, {1, 11}},
+		},
+		{
+			`{"type":"MultiPolygon","coordinates":[[[[170,-10],[180,-10],[180,10],[170,10],[170,-10]]],[[[-180,-10],[-170,-10],[-170,10],[-180,10],[-180,-10]]]]}`,
+			[]Point{{0, 175}, {0, -175}},
+			[]Point{{0, 0}, {20, 175}},
+		},
+	}
+	for _, tt := range tests {
+		var g geojson.Geometry
+		if err := json.Unmarshal([]byte(tt.geometry), &g); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseRegion(&g)
+		if err != nil {
+			t.Fatalf("ParseRegion(%s): %v", tt.geometry, err)
+		}
+		for _, p := range tt.in {
+			if !r.Contains(p) {
+				t.Errorf("%s does not contain %v", g.Type, p)
+			}
+		}
+		for _, p := range tt.out {
+			if r.Contains(p) {
+				t.Errorf("%s contains %v", g.Type, p)
+			}
+		}
+	}
+
+	for _, s := range []string{
+		`{"type":"Point","coordinates":[1,2]}`,
+		`{"type":"Polygon","coordinates":[[[1]]]}`,
+		`{"type":"Polygon","coordinates":"x"}`,
+	} {
+		var g geojson.Geometry
+		if err := json.Unmarshal([]byte(s), &g); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseRegion(&g); err == nil {
+			t.Errorf("ParseRegion(%s) succeeded", s)
+		}
+	}
+	if _, err := ParseRegion(nil); !errors.Is(err, ErrUnsupportedGeometry) {
+		t.Errorf("ParseRegion(nil) error = %v, want ErrUnsupportedGeometry", err)
+	}
+}
@@ -0,0 +1,286 @@
+// Package geocode fills the address fields of LocationData from coordinates
+// and resolves place names to coordinates, offline, using a GeoNames-style
+// gazetteer.
+//
+// A Geocoder is loaded from the GeoNames "cities" dump format
+// (cities500.txt, cities15000.txt and so on) and optionally from
+// admin1CodesASCII.txt for first-level division names. NewBundled uses a
+// small embedded dataset of capitals and major cities, enough to resolve
+// country codes near populated areas; load a full dump for anything finer.
+package geocode
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/textfold"
+)
+
+// DefaultMaxDistance is how far, in metres, Reverse looks for a place.
+const DefaultMaxDistance = 100000
+
+// ErrInvalidRecord is returned for gazetteer lines that cannot be parsed.
+var ErrInvalidRecord = errors.New("geocode: invalid record")
+
+// The bundled dataset uses the GeoNames column layouts but is hand-curated,
+// so its geonameid column is zero and only some admin1 codes are present.
+//
+//go:embed data/cities.tsv data/admin1.tsv
+var bundled embed.FS
+
+// Place is a populated place of the gazetteer.
+type Place struct {
+	// ID is the GeoNames geonameid, or zero when unknown.
+	ID         int64
+	Name       string
+	ASCIIName  string
+	Alternates []string
+	Point      geo.Point
+	// FeatureCode is the GeoNames feature code, such as PPLC for a capital.
+	FeatureCode string
+	CountryCode string
+	// Admin1Code is the GeoNames first-level division code within the
+	// country, such as "NY" in the US or "11" (Île-de-France) in France.
+	Admin1Code string
+	Population int64
+}
+
+// Option configures a Geocoder.
+type Option func(*Geocoder)
+
+// WithMaxDistance sets how far, in metres, Reverse looks for a place.
+func WithMaxDistance(metres float64) Option {
+	return func(g *Geocoder) {
+		g.maxDistance = metres
+	}
+}
+
+// Geocoder answers reverse and forward lookups over loaded places. Loading
+// is not safe for concurrent use; lookups are once loading is done.
+type Geocoder struct {
+	grid        *geo.Grid[*Place]
+	names       map[string][]*Place
+	admin1      map[string]string
+	maxDistance float64
+}
+
+// New returns an empty Geocoder. Load places with LoadGeoNames.
+func New(opts ...Option) *Geocoder {
+	g := &Geocoder{
+		grid:        geo.NewGrid[*Place](0),
+		names:       map[string][]*Place{},
+		admin1:      map[string]string{},
+		maxDistance: DefaultMaxDistance,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// NewBundled returns a Geocoder loaded with the embedded dataset.
+func NewBundled(opts ...Option) (*Geocoder, error) {
+	g := New(opts...)
+	if err := loadFile("data/cities.tsv", g.LoadGeoNames); err != nil {
+		return nil, err
+	}
+	if err := loadFile("data/admin1.tsv", g.LoadAdmin1); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func loadFile(name string, load func(io.Reader) error) error {
+	f, err := bundled.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return load(f)
+}
+
+// Len returns the number of loaded places.
+func (g *Geocoder) Len() int {
+	return g.grid.Len()
+}
+
+// LoadGeoNames adds the places of a GeoNames dump read from r: tab-separated
+// lines of geonameid, name, asciiname, alternatenames, latitude, longitude,
+// feature class, feature code, country code, cc2, admin1 code, admin2 code,
+// admin3 code, admin4 code, population and so on. Only populated places
+// (feature class P) are kept. Empty lines and lines starting with '#' are
+// skipped.
+func (g *Geocoder) LoadGeoNames(r io.Reader) error {
+	return scan(r, 15, func(f []string) error {
+		if f[6] != "P" {
+			return nil
+		}
+		lat, err := strconv.ParseFloat(f[4], 64)
+		if err != nil {
+			return err
+		}
+		lon, err := strconv.ParseFloat(f[5], 64)
+		if err != nil {
+			return err
+		}
+		p := &Place{
+			Name:        f[1],
+			ASCIIName:   f[2],
+			Point:       geo.Point{Lat: lat, Lon: lon},
+			FeatureCode: f[7],
+			CountryCode: f[8],
+			Admin1Code:  f[10],
+		}
+		if f[0] != "" {
+			if p.ID, err = strconv.ParseInt(f[0], 10, 64); err != nil {
+				return err
+			}
+		}
+		if f[3] != "" {
+			p.Alternates = strings.Split(f[3], ",")
+		}
+		if f[14] != "" {
+			if p.Population, err = strconv.ParseInt(f[14], 10, 64); err != nil {
+				return err
+			}
+		}
+		g.add(p)
+		return nil
+	})
+}
+
+// LoadAdmin1 adds first-level division names from an admin1CodesASCII dump
+// read from r: tab-separated lines of "<country>.<admin1 code>", name,
+// ASCII name and geonameid.
+func (g *Geocoder) LoadAdmin1(r io.Reader) error {
+	return scan(r, 2, func(f []string) error {
+		if !strings.Contains(f[0], ".") {
+			return fmt.Errorf("code %q has no country prefix", f[0])
+		}
+		g.admin1[f[0]] = f[1]
+		return nil
+	})
+}
+
+// scan calls fn with the fields of every record of r, which must have at
+// least n fields.
+func scan(r io.Reader, n int, fn func([]string) error) error {
+	sc := bufio.NewScanner(r)
+	// Alternate name lists of large cities run to tens of kilobytes.
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		f := strings.Split(text, "\t")
+		if len(f) < n {
+			return fmt.Errorf("%w: line %d has %d fields, want %d", ErrInvalidRecord, line, len(f), n)
+		}
+		if err := fn(f); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidRecord, line, err)
+		}
+	}
+	return sc.Err()
+}
+
+func (g *Geocoder) add(p *Place) {
+	g.grid.Insert(p.Point, p)
+	seen := map[string]bool{}
+	for _, name := range append([]string{p.Name, p.ASCIIName}, p.Alternates...) {
+		k := textfold.Fold(name)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		g.names[k] = append(g.names[k], p)
+	}
+}
+
+// AdministrativeArea returns the name of the first-level division p lies
+// in, or "" when it was not loaded.
+func (g *Geocoder) AdministrativeArea(p *Place) string {
+	if p.Admin1Code == "" {
+		return ""
+	}
+	return g.admin1[p.CountryCode+"."+p.Admin1Code]
+}
+
+// Reverse returns the place nearest to p within the maximum distance, and
+// its distance in metres.
+func (g *Geocoder) Reverse(p geo.Point) (*Place, float64, bool) {
+	hits := g.grid.NearestWithin(p, 1, g.maxDistance)
+	if len(hits) == 0 {
+		return nil, 0, false
+	}
+	return hits[0].Value, hits[0].Distance, true
+}
+
+// Fill reverse-geocodes the coordinates of l and sets its CountryCode,
+// AdministrativeArea and Locality where they are empty, and its source to
+// geocoded when unspecified. Fields that are already set are kept, even if
+// they disagree with the gazetteer. It returns false when l has no
+// coordinates or no place is near enough.
+func (g *Geocoder) Fill(l *model.LocationData) bool {
+	pt, ok := geo.PointOf(l)
+	if !ok {
+		return false
+	}
+	p, _, ok := g.Reverse(pt)
+	if !ok {
+		return false
+	}
+	if l.CountryCode == "" {
+		l.CountryCode = p.CountryCode
+	}
+	if l.AdministrativeArea == "" {
+		l.AdministrativeArea = g.AdministrativeArea(p)
+	}
+	if l.Locality == "" {
+		l.Locality = p.Name
+	}
+	if l.Source == model.LocationSource_LOCATION_SOURCE_UNSPECIFIED {
+		l.Source = model.LocationSource_LOCATION_SOURCE_GEOCODED
+	}
+	return true
+}
+
+// Forward returns the places named name, most populous first. Names match
+// ignoring case, common diacritics and punctuation, against the name, the
+// ASCII name and the alternate names. A non-empty country restricts the
+// result to that ISO 3166-1 alpha-2 code.
+func (g *Geocoder) Forward(name, country string) []*Place {
+	var out []*Place
+	for _, p := range g.names[textfold.Fold(name)] {
+		if country == "" || strings.EqualFold(p.CountryCode, country) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Population > out[j].Population })
+	return out
+}
+
+// Locate returns the location of the most populous place named name, as
+// for Forward, with its coordinates and address fields set and its source
+// marked as geocoded.
+func (g *Geocoder) Locate(name, country string) (*model.LocationData, bool) {
+	places := g.Forward(name, country)
+	if len(places) == 0 {
+		return nil, false
+	}
+	p := places[0]
+	l := p.Point.Location()
+	l.CountryCode = p.CountryCode
+	l.AdministrativeArea = g.AdministrativeArea(p)
+	l.Locality = p.Name
+	l.Source = model.LocationSource_LOCATION_SOURCE_GEOCODED
+	return l, true
+}
@@ -0,0 +1,200 @@
+package geocode
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"google.golang.org/protobuf/proto"
+)
+
+func bundledGeocoder(t *testing.T, opts ...Option) *Geocoder {
+	t.Helper()
+	g, err := NewBundled(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestReverse(t *testing.T) {
+	g := bundledGeocoder(t)
+	if g.Len() == 0 {
+		t.Fatal("bundled dataset is empty")
+	}
+	tests := []struct {
+		p       geo.Point
+		name    string
+		country string
+		admin   string
+	}{
+		{geo.Point{Lat: 48.8584, Lon: 2.2945}, "Paris", "FR", "Île-de-France"},
+		{geo.Point{Lat: 45.52, Lon: -73.6}, "Montréal", "CA", "Quebec"},
+		{geo.Point{Lat: -33.86, Lon: 151.21}, "Sydney", "AU", "New South Wales"},
+		{geo.Point{Lat: 47.37, Lon: 8.54}, "Zürich", "CH", ""},
+	}
+	for _, tt := range tests {
+		p, d, ok := g.Reverse(tt.p)
+		if !ok {
+			t.Errorf("Reverse(%v) found nothing", tt.p)
+			continue
+		}
+		if p.Name != tt.name || p.CountryCode != tt.country || g.AdministrativeArea(p) != tt.admin {
+			t.Errorf("Reverse(%v) = %s, %s, %q, want %s, %s, %q", tt.p, p.Name, p.CountryCode, g.AdministrativeArea(p), tt.name, tt.country, tt.admin)
+		}
+		if d != geo.Haversine(tt.p, p.Point) {
+			t.Errorf("Reverse(%v) distance = %f, want %f", tt.p, d, geo.Haversine(tt.p, p.Point))
+		}
+	}
+	if p, _, ok := g.Reverse(geo.Point{Lat: -48.87, Lon: -123.39}); ok {
+		t.Errorf("Reverse(Point Nemo) = %s", p.Name)
+	}
+	near := bundledGeocoder(t, WithMaxDistance(1000))
+	if p, _, ok := near.Reverse(geo.Point{Lat: 48.9, Lon: 2.35}); ok {
+		t.Errorf("Reverse beyond WithMaxDistance = %s", p.Name)
+	}
+}
+
+func TestForward(t *testing.T) {
+	g := bundledGeocoder(t)
+	tests := []struct {
+		name, country string
+		want          string
+	}{
+		{"Paris", "", "Paris"},
+		{"paris", "fr", "Paris"},
+		{"Sao Paulo", "", "São Paulo"},
+		{"MONTREAL", "CA", "Montréal"},
+		{"Marseilles", "", "Marseille"},
+		{"Tiranë", "", "Tirana"},
+		{"Paris", "US", ""},
+		{"Atlantis", "", ""},
+	}
+	for _, tt := range tests {
+		places := g.Forward(tt.name, tt.country)
+		got := ""
+		if len(places) > 0 {
+			got = places[0].Name
+		}
+		if got != tt.want {
+			t.Errorf("Forward(%q, %q) = %q, want %q", tt.name, tt.country, got, tt.want)
+		}
+	}
+
+	l, ok := g.Locate("Sao Paulo", "BR")
+	want := &model.LocationData{
+		Latitude: -23.5475, Longitude: -46.6361, CountryCode: "BR",
+		AdministrativeArea: "São Paulo", Locality: "São Paulo",
+		Source: model.LocationSource_LOCATION_SOURCE_GEOCODED,
+	}
+	if !ok || !proto.Equal(l, want) {
+		t.Errorf("Locate = %v, want %v", l, want)
+	}
+	if _, ok := g.Locate("Atlantis", ""); ok {
+		t.Error("Locate(Atlantis) succeeded")
+	}
+}
+
+func TestForwardPopulation(t *testing.T) {
+	g := New()
+	data := "1\tSpringfield\tSpringfield\t\t39.8\t-89.6\tP\tPPLA\tUS\t\tIL\t\t\t\t114394\n" +
+		"2\tSpringfield\tSpringfield\t\t37.2\t-93.3\tP\tPPL\tUS\t\tMO\t\t\t\t169176\n" +
+		"3\tSpringfield Lake\tSpringfield Lake\tSpringfield\t41.1\t-81.5\tL\tLK\tUS\t\tOH\t\t\t\t0\n"
+	if err := g.LoadGeoNames(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 2 {
+		t.Errorf("Len = %d, want 2 as only populated places are kept", g.Len())
+	}
+	places := g.Forward("springfield", "us")
+	if len(places) != 2 || places[0].ID != 2 || places[1].ID != 1 {
+		t.Errorf("Forward = %v, want the Missouri then the Illinois Springfield", places)
+	}
+}
+
+func TestFill(t *testing.T) {
+	g := bundledGeocoder(t)
+	tests := []struct {
+		name string
+		in   *model.LocationData
+		want *model.LocationData
+		ok   bool
+	}{
+		{
+			"empty fields",
+			&model.LocationData{Latitude: 48.8584, Longitude: 2.2945},
+			&model.LocationData{Latitude: 48.8584, Longitude: 2.2945, CountryCode: "FR", AdministrativeArea: "Île-de-France", Locality: "Paris", Source: model.LocationSource_LOCATION_SOURCE_GEOCODED},
+			true,
+		},
+		{
+			"set fields kept",
+			&model.LocationData{Latitude: 48.8584, Longitude: 2.2945, Locality: "Eiffel Tower", Source: model.LocationSource_LOCATION_SOURCE_GPS},
+			&model.LocationData{Latitude: 48.8584, Longitude: 2.2945, CountryCode: "FR", AdministrativeArea: "Île-de-France", Locality: "Eiffel Tower", Source: model.LocationSource_LOCATION_SOURCE_GPS},
+			true,
+		},
+		{
+			"no coordinates",
+			&model.LocationData{Address: "somewhere"},
+			&model.LocationData{Address: "somewhere"},
+			false,
+		},
+		{
+			"open ocean",
+			&model.LocationData{Latitude: -48.87, Longitude: -123.39},
+			&model.LocationData{Latitude: -48.87, Longitude: -123.39},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		if ok := g.Fill(tt.in); ok != tt.ok || !proto.Equal(tt.in, tt.want) {
+			t.Errorf("Fill %s = %v, %v, want %v, %v", tt.name, tt.in, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	row := func(fields ...string) string {
+		f := make([]string, 15)
+		copy(f, []string{"1", "Name", "Name", "", "1", "2", "P", "PPL", "XX"})
+		for i := 0; i+1 < len(fields); i += 2 {
+			switch fields[i] {
+			case "id":
+				f[0] = fields[i+1]
+			case "lat":
+				f[4] = fields[i+1]
+			case "lon":
+				f[5] = fields[i+1]
+			case "population":
+				f[14] = fields[i+1]
+			}
+		}
+		return strings.Join(f, "\t")
+	}
+	tests := []struct {
+		name string
+		data string
+		line string
+	}{
+		{"short line", "# header\n\n" + row() + "\na\tb\n", "line 4 has 2 fields"},
+		{"latitude", row("lat", "north"), "line 1:"},
+		{"longitude", row("lon", "east"), "line 1:"},
+		{"geonameid", row() + "\n" + row("id", "x"), "line 2:"},
+		{"population", row("population", "many"), "line 1:"},
+	}
+	for _, tt := range tests {
+		err := New().LoadGeoNames(strings.NewReader(tt.data))
+		if !errors.Is(err, ErrInvalidRecord) || !strings.Contains(err.Error(), tt.line) {
+			t.Errorf("LoadGeoNames %s error = %v, want ErrInvalidRecord at %q", tt.name, err, tt.line)
+		}
+	}
+
+	g := New()
+	if err := g.LoadGeoNames(strings.NewReader("# only a comment\n\n" + row() + "\n")); err != nil || g.Len() != 1 {
+		t.Errorf("LoadGeoNames = %v with %d places, want 1", err, g.Len())
+	}
+	if err := g.LoadAdmin1(strings.NewReader("11\tÎle-de-France\n")); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("LoadAdmin1 without a country prefix error = %v, want ErrInvalidRecord", err)
+	}
+}
@@ -0,0 +1,290 @@
+// Package geojson renders located Events as GeoJSON (RFC 7946) and reads
+// GeoJSON points back into LocationData.
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/omnsight/omniscent-library/acl"
+	"github.com/omnsight/omniscent-library/arango"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/floatconv"
+	"github.com/omnsight/omniscent-library/internal/locsource"
+	"github.com/omnsight/omniscent-library/internal/postcode"
+)
+
+// GeoJSON object types.
+const (
+	TypeFeatureCollection = "FeatureCollection"
+	TypeFeature           = "Feature"
+	TypePoint             = "Point"
+)
+
+// Feature property names set from an Event and its location.
+const (
+	PropTitle       = "title"
+	PropType        = "type"
+	PropDescription = "description"
+	PropTags        = "tags"
+	PropHappenedAt  = "happened_at"
+	PropAttributes  = "attributes"
+
+	PropCountryCode           = "country_code"
+	PropAdministrativeArea    = "administrative_area"
+	PropSubAdministrativeArea = "sub_administrative_area"
+	PropLocality              = "locality"
+	PropSubLocality           = "sub_locality"
+	PropAddress               = "address"
+	PropPostcode              = "postcode"
+	PropAccuracy              = "accuracy"
+	PropLocationSource        = "location_source"
+)
+
+var ErrNotPoint = errors.New("geojson: geometry is not a Point")
+
+// FeatureCollection is a GeoJSON FeatureCollection.
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature.
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Coordinates are kept raw so any geometry
+// type can be decoded; only Point is interpreted.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Option configures FromEvents.
+type Option func(*exporter)
+
+// WithProperties restricts feature properties to names. By default every
+// property is written.
+func WithProperties(names ...string) Option {
+	return func(x *exporter) {
+		x.properties = append(x.properties, names...)
+	}
+}
+
+// WithACL drops events p may not read according to e. A nil evaluator
+// means acl.Default.
+func WithACL(e *acl.Evaluator, p acl.Principal) Option {
+	return func(x *exporter) {
+		if e == nil {
+			e = acl.Default
+		}
+		x.evaluator, x.principal = e, &p
+	}
+}
+
+type exporter struct {
+	properties []string
+	evaluator  *acl.Evaluator
+	principal  *acl.Principal
+}
+
+// FromEvents returns a collection with one Point feature per event that has
+// a location. Feature IDs are the event document handles when known.
+func FromEvents(events []*model.Event, opts ...Option) *FeatureCollection {
+	x := &exporter{}
+	for _, opt := range opts {
+		opt(x)
+	}
+	fc := &FeatureCollection{Type: TypeFeatureCollection, Features: []*Feature{}}
+	for _, e := range events {
+		if e.GetLocation() == nil {
+			continue
+		}
+		if x.principal != nil && !x.evaluator.CanRead(*x.principal, e) {
+			continue
+		}
+		fc.Features = append(fc.Features, x.feature(e))
+	}
+	return fc
+}
+
+func (x *exporter) feature(e *model.Event) *Feature {
+	loc := e.GetLocation()
+	f := &Feature{
+		Type:       TypeFeature,
+		Geometry:   Point(loc),
+		Properties: map[string]any{},
+	}
+	if h, err := arango.HandleOf(e); err == nil {
+		f.ID = h.String()
+	}
+	x.set(f, PropTitle, e.GetTitle())
+	x.set(f, PropType, e.GetType())
+	x.set(f, PropDescription, e.GetDescription())
+	x.set(f, PropHappenedAt, e.GetHappenedAt())
+	x.set(f, PropCountryCode, loc.GetCountryCode())
+	x.set(f, PropAdministrativeArea, loc.GetAdministrativeArea())
+	x.set(f, PropSubAdministrativeArea, loc.GetSubAdministrativeArea())
+	x.set(f, PropLocality, loc.GetLocality())
+	x.set(f, PropSubLocality, loc.GetSubLocality())
+	x.set(f, PropAddress, loc.GetAddress())
+	x.set(f, PropPostcode, postcode.Of(loc))
+	x.set(f, PropAccuracy, floatconv.Widen(loc.GetAccuracy()))
+	x.set(f, PropLocationSource, locsource.Name(loc.GetSource()))
+	if tags := e.GetTags(); len(tags) > 0 {
+		x.set(f, PropTags, tags)
+	}
+	if attrs := e.GetAttributes(); len(attrs.GetFields()) > 0 {
+		x.set(f, PropAttributes, attrs.AsMap())
+	}
+	return f
+}
+
+// set writes a non-zero property allowed by the whitelist.
+func (x *exporter) set(f *Feature, name string, v any) {
+	if len(x.properties) > 0 && !slices.Contains(x.properties, name) {
+		return
+	}
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case int64:
+		if v == 0 {
+			return
+		}
+	case float64:
+		if v == 0 {
+			return
+		}
+	}
+	f.Properties[name] = v
+}
+
+// Point returns the Point geometry of loc, with its altitude as the third
+// coordinate when set.
+func Point(loc *model.LocationData) *Geometry {
+	pos := []float64{
+		floatconv.Widen(loc.GetLongitude()),
+		floatconv.Widen(loc.GetLatitude()),
+	}
+	if alt := loc.GetAltitude(); alt != 0 {
+		pos = append(pos, floatconv.Widen(alt))
+	}
+	c, _ := json.Marshal(pos)
+	return &Geometry{Type: TypePoint, Coordinates: c}
+}
+
+// Location converts a Point geometry to LocationData, reading a third
+// coordinate as the altitude.
+func Location(g *Geometry) (*model.LocationData, error) {
+	if g == nil || g.Type != TypePoint {
+		return nil, ErrNotPoint
+	}
+	var c []float64
+	if err := json.Unmarshal(g.Coordinates, &c); err != nil {
+		return nil, fmt.Errorf("geojson: point coordinates: %w", err)
+	}
+	if len(c) < 2 {
+		return nil, fmt.Errorf("geojson: point has %d coordinates", len(c))
+	}
+	loc := &model.LocationData{
+		Longitude: float32(c[0]),
+		Latitude:  float32(c[1]),
+	}
+	if len(c) > 2 {
+		loc.Altitude = float32(c[2])
+	}
+	return loc, nil
+}
+
+// FeatureLocation converts a Point feature to LocationData, filling the
+// address, accuracy and source fields from the properties written by
+// FromEvents. A numeric postcode property is accepted too.
+func FeatureLocation(f *Feature) (*model.LocationData, error) {
+	loc, err := Location(f.Geometry)
+	if err != nil {
+		return nil, err
+	}
+	str := func(name string) string {
+		s, _ := f.Properties[name].(string)
+		return s
+	}
+	loc.CountryCode = str(PropCountryCode)
+	loc.AdministrativeArea = str(PropAdministrativeArea)
+	loc.SubAdministrativeArea = str(PropSubAdministrativeArea)
+	loc.Locality = str(PropLocality)
+	loc.SubLocality = str(PropSubLocality)
+	loc.Address = str(PropAddress)
+	switch v := f.Properties[PropPostcode].(type) {
+	case string:
+		postcode.Set(loc, v)
+	case float64:
+		postcode.Set(loc, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	if v, ok := f.Properties[PropAccuracy].(float64); ok {
+		loc.Accuracy = float32(v)
+	}
+	loc.Source = locsource.Parse(str(PropLocationSource))
+	return loc, nil
+}
+
+// ParseLocations decodes a GeoJSON Point, Feature or FeatureCollection and
+// returns the locations of all Point geometries in it. Features with other
+// geometries are skipped.
+func ParseLocations(data []byte) ([]*model.LocationData, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	switch head.Type {
+	case TypePoint:
+		var g Geometry
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, err
+		}
+		loc, err := Location(&g)
+		if err != nil {
+			return nil, err
+		}
+		return []*model.LocationData{loc}, nil
+	case TypeFeature:
+		var f Feature
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		return featureLocations([]*Feature{&f})
+	case TypeFeatureCollection:
+		var fc FeatureCollection
+		if err := json.Unmarshal(data, &fc); err != nil {
+			return nil, err
+		}
+		return featureLocations(fc.Features)
+	}
+	return nil, fmt.Errorf("geojson: unsupported type %q", head.Type)
+}
+
+func featureLocations(features []*Feature) ([]*model.LocationData, error) {
+	var out []*model.LocationData
+	for _, f := range features {
+		if f.Geometry == nil || f.Geometry.Type != TypePoint {
+			continue
+		}
+		loc, err := FeatureLocation(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, loc)
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package geojson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromEventsProperties(t *testing.T) {
+	loc := &model.LocationData{Latitude: 48.8566, Longitude: 2.3522, Locality: "Paris"}
+	tests := []struct {
+		event *model.Event
+		want  map[string]any
+	}{
+		{
+			&model.Event{Title: "undated", Location: loc},
+			map[string]any{PropTitle: "undated", PropLocality: "Paris"},
+		},
+		{
+			&model.Event{Title: "dated", HappenedAt: 1700000000, Location: loc},
+			map[string]any{PropTitle: "dated", PropHappenedAt: int64(1700000000), PropLocality: "Paris"},
+		},
+	}
+	for _, tt := range tests {
+		fc := FromEvents([]*model.Event{tt.event})
+		if len(fc.Features) != 1 {
+			t.Fatalf("%d features, want 1", len(fc.Features))
+		}
+		if got := fc.Features[0].Properties; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: properties = %v, want %v", tt.event.GetTitle(), got, tt.want)
+		}
+	}
+}
+
+func TestPointRoundTrip(t *testing.T) {
+	loc := &model.LocationData{Latitude: 2.35, Longitude: -71.0589}
+	if got := string(Point(loc).Coordinates); got != "[-71.0589,2.35]" {
+		t.Errorf("coordinates = %s", got)
+	}
+	back, err := Location(Point(loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.GetLatitude() != loc.GetLatitude() || back.GetLongitude() != loc.GetLongitude() {
+		t.Errorf("Location(Point(%v)) = %v", loc, back)
+	}
+}
+
+func TestParseLocationsRoundTrip(t *testing.T) {
+	tests := []*model.LocationData{
+		{Latitude: 48.8566, Longitude: 2.3522, Locality: "Paris"},
+		{
+			Latitude: 51.501, Longitude: -0.1416, Altitude: 12.5, Accuracy: 30,
+			Source: model.LocationSource_LOCATION_SOURCE_GPS, Locality: "London", Postcode: "SW1A 1AA",
+		},
+		{
+			Latitude: 48.8606, Longitude: 2.3376, Postcode: "75001", PostalCode: 75001,
+			Source: model.LocationSource_LOCATION_SOURCE_GEOCODED,
+		},
+		{Latitude: 42.3554, Longitude: -71.0605, Postcode: "02134"},
+	}
+	for _, loc := range tests {
+		data, err := json.Marshal(FromEvents([]*model.Event{{Title: "e", Location: loc}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseLocations(data)
+		if err != nil {
+			t.Fatalf("ParseLocations(%s): %v", data, err)
+		}
+		if len(got) != 1 || !proto.Equal(got[0], loc) {
+			t.Errorf("ParseLocations(%s) = %v, want %v", data, got, loc)
+		}
+	}
+}
+
+func TestFeatureLocationNumericPostcode(t *testing.T) {
+	var f Feature
+	data := `{"type":"Feature","geometry":{"type":"Point","coordinates":[2.35,48.86,35]},` +
+		`"properties":{"postcode":75001,"location_source":"manual"}}`
+	if err := json.Unmarshal([]byte(data), &f); err != nil {
+		t.Fatal(err)
+	}
+	loc, err := FeatureLocation(&f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.GetPostcode() != "75001" || loc.GetPostalCode() != 75001 {
+		t.Errorf("postcode = %q/%d, want 75001", loc.GetPostcode(), loc.GetPostalCode())
+	}
+	if loc.GetAltitude() != 35 {
+		t.Errorf("altitude = %v, want 35", loc.GetAltitude())
+	}
+	if loc.GetSource() != model.LocationSource_LOCATION_SOURCE_MANUAL {
+		t.Errorf("source = %v, want LOCATION_SOURCE_MANUAL", loc.GetSource())
+	}
+}
@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/omnsight/omniscent-library/epoch"
+)
+
+// GEXFNamespace is the GEXF 1.3 namespace.
+const GEXFNamespace = "http://gexf.net/1.3"
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	Mode            string           `xml:"mode,attr"`
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	TimeFormat      string           `xml:"timeformat,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	Start     string         `xml:"start,attr,omitempty"`
+	End       string         `xml:"end,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	Start     string         `xml:"start,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF encodes g as a dynamic GEXF 1.3 graph. Each node and edge
+// starts at its created_at, happened_at, discovered_at or updated_at,
+// whichever is set first. Organizations and websites end at last_visited
+// when it is set; every other element remains to the end of the timeline,
+// as the model records no end for events, people, sources or relations.
+// Elements without any timestamp are always present.
+func WriteGEXF(w io.Writer, g Graph) error {
+	p := prepare(g)
+	doc := gexf{
+		Xmlns:   GEXFNamespace,
+		Version: "1.3",
+		Graph: gexfGraph{
+			Mode:            "dynamic",
+			DefaultEdgeType: "directed",
+			TimeFormat:      "dateTime",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: gexfDeclare(p.nodeAttrs)},
+				{Class: "edge", Attributes: gexfDeclare(p.edgeAttrs)},
+			},
+		},
+	}
+	for _, n := range p.nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:        n.id,
+			Label:     n.label,
+			Start:     gexfTime(n.start),
+			End:       gexfTime(n.end),
+			AttValues: gexfValues(p.nodeAttrs, n.values),
+		})
+	}
+	for _, e := range p.edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        e.id,
+			Source:    e.source,
+			Target:    e.target,
+			Label:     e.label,
+			Start:     gexfTime(e.start),
+			AttValues: gexfValues(p.edgeAttrs, e.values),
+		})
+	}
+	return encodeXML(w, doc)
+}
+
+func gexfDeclare(attrs []attribute) []gexfAttribute {
+	out := make([]gexfAttribute, len(attrs))
+	for i, a := range attrs {
+		out[i] = gexfAttribute{ID: strconv.Itoa(a.index), Title: a.name, Type: gexfType(a.typ)}
+	}
+	return out
+}
+
+// gexfType maps GraphML attribute types to GEXF ones.
+func gexfType(typ string) string {
+	if typ == typeInt {
+		return "integer"
+	}
+	return typ
+}
+
+func gexfValues(attrs []attribute, values map[string]string) []gexfAttValue {
+	var out []gexfAttValue
+	for _, a := range attrs {
+		if v, ok := values[a.name]; ok {
+			out = append(out, gexfAttValue{For: strconv.Itoa(a.index), Value: v})
+		}
+	}
+	return out
+}
+
+// gexfTime formats a timestamp field read with epoch.Default, or returns
+// "" if it is unset.
+func gexfTime(v int64) string {
+	t := epoch.Default.Time(v)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,276 @@
+// Package graph exports entities and relations as GraphML and GEXF for
+// tools such as yEd and Gephi.
+//
+// Nodes are keyed by document handle and carry one attribute per populated
+// message field, named by its proto path ("location.latitude"); repeated
+// fields are joined with ';' and attributes are written as a JSON object.
+// Edges carry the relation name, label and confidence. Relations whose
+// endpoints are not among the exported entities are skipped.
+package graph
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Graph is the subgraph to export.
+type Graph struct {
+	Entities  []*model.Entity
+	Relations []*model.Relation
+}
+
+// Attribute value types shared by both formats.
+const (
+	typeString  = "string"
+	typeInt     = "int"
+	typeLong    = "long"
+	typeFloat   = "float"
+	typeDouble  = "double"
+	typeBoolean = "boolean"
+)
+
+// Node attribute holding the entity kind.
+const kindAttribute = "kind"
+
+// Edge attributes.
+const (
+	edgeName       = "name"
+	edgeLabel      = "label"
+	edgeConfidence = "confidence"
+)
+
+// attribute declares one column of node or edge data.
+type attribute struct {
+	id    string
+	name  string
+	typ   string
+	index int
+}
+
+type node struct {
+	id     string
+	label  string
+	start  int64
+	end    int64
+	values map[string]string
+}
+
+type edge struct {
+	id     string
+	source string
+	target string
+	label  string
+	start  int64
+	values map[string]string
+}
+
+// prepared is the format-independent form of a Graph.
+type prepared struct {
+	nodeAttrs []attribute
+	edgeAttrs []attribute
+	nodes     []node
+	edges     []edge
+}
+
+func prepare(g Graph) prepared {
+	var p prepared
+	types := map[string]string{kindAttribute: typeString}
+	handles := map[string]bool{}
+	for _, e := range g.Entities {
+		doc := entity.Unwrap(e)
+		if doc == nil {
+			continue
+		}
+		h, err := arango.HandleOf(doc)
+		if err != nil || handles[h.String()] {
+			continue
+		}
+		handles[h.String()] = true
+		n := node{
+			id:     h.String(),
+			label:  nodeLabel(doc),
+			start:  startOf(doc.ProtoReflect()),
+			end:    endOf(doc.ProtoReflect()),
+			values: map[string]string{kindAttribute: entity.KindOf(doc).String()},
+		}
+		flatten(doc.ProtoReflect(), "", n.values, types)
+		p.nodes = append(p.nodes, n)
+	}
+	p.nodeAttrs = declare("n", types)
+
+	for i, r := range g.Relations {
+		if !handles[r.GetFrom()] || !handles[r.GetTo()] {
+			continue
+		}
+		id := r.GetId()
+		if id == "" {
+			id = "e" + strconv.Itoa(i)
+		}
+		e := edge{
+			id:     id,
+			source: r.GetFrom(),
+			target: r.GetTo(),
+			label:  r.GetLabel(),
+			start:  startOf(r.ProtoReflect()),
+			values: map[string]string{
+				edgeLabel:      r.GetLabel(),
+				edgeConfidence: strconv.Itoa(int(r.GetConfidence())),
+			},
+		}
+		if r.GetName() != "" {
+			e.values[edgeName] = r.GetName()
+		}
+		p.edges = append(p.edges, e)
+	}
+	p.edgeAttrs = declare("e", map[string]string{
+		edgeName:       typeString,
+		edgeLabel:      typeString,
+		edgeConfidence: typeInt,
+	})
+	return p
+}
+
+// declare assigns ids to attributes in name order.
+func declare(prefix string, types map[string]string) []attribute {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	attrs := make([]attribute, len(names))
+	for i, name := range names {
+		attrs[i] = attribute{id: prefix + strconv.Itoa(i), name: name, typ: types[name], index: i}
+	}
+	return attrs
+}
+
+// skipped fields are represented by the node id instead.
+var skipped = map[protoreflect.Name]bool{"id": true, "key": true, "rev": true}
+
+// flatten writes the populated fields of m into values, recording the
+// attribute type of each path in types.
+func flatten(m protoreflect.Message, prefix string, values, types map[string]string) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if prefix == "" && skipped[fd.Name()] {
+			return true
+		}
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsList():
+			parts := make([]string, v.List().Len())
+			for i := range parts {
+				parts[i] = scalar(fd, v.List().Get(i))
+			}
+			record(types, path, typeString)
+			values[path] = strings.Join(parts, ";")
+		case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Struct":
+			b, _ := json.Marshal(v.Message().Interface().(*structpb.Struct).AsMap())
+			record(types, path, typeString)
+			values[path] = string(b)
+		case fd.Message() != nil:
+			flatten(v.Message(), path+".", values, types)
+		default:
+			record(types, path, attributeType(fd))
+			values[path] = scalar(fd, v)
+		}
+		return true
+	})
+}
+
+// record sets the type of path, widening to string on conflict.
+func record(types map[string]string, path, typ string) {
+	if old, ok := types[path]; ok && old != typ {
+		typ = typeString
+	}
+	types[path] = typ
+}
+
+func attributeType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return typeBoolean
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return typeInt
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return typeLong
+	case protoreflect.FloatKind:
+		return typeFloat
+	case protoreflect.DoubleKind:
+		return typeDouble
+	}
+	return typeString
+}
+
+func scalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+	}
+	return v.String()
+}
+
+// nodeLabel picks the most readable field of a document.
+func nodeLabel(d entity.Tagged) string {
+	switch v := d.(type) {
+	case *model.Person:
+		return v.GetName()
+	case *model.Organization:
+		return v.GetName()
+	case *model.Source:
+		if v.GetName() != "" {
+			return v.GetName()
+		}
+		return v.GetTitle()
+	case *model.Website:
+		if v.GetTitle() != "" {
+			return v.GetTitle()
+		}
+		return v.GetUrl()
+	case *model.Event:
+		return v.GetTitle()
+	}
+	return d.GetKey()
+}
+
+// startOf returns the first non-zero of created_at, happened_at,
+// discovered_at and updated_at, the moment a document enters a dynamic
+// graph.
+func startOf(m protoreflect.Message) int64 {
+	fields := m.Descriptor().Fields()
+	for _, name := range []protoreflect.Name{"created_at", "happened_at", "discovered_at", "updated_at"} {
+		if fd := fields.ByName(name); fd != nil {
+			if t := m.Get(fd).Int(); t != 0 {
+				return t
+			}
+		}
+	}
+	return 0
+}
+
+// endOf returns last_visited, the last moment a document was seen, or 0 if
+// it is unset or precedes startOf.
+func endOf(m protoreflect.Message) int64 {
+	fd := m.Descriptor().Fields().ByName("last_visited")
+	if fd == nil {
+		return 0
+	}
+	t := m.Get(fd).Int()
+	if epoch.Default.Time(t).Before(epoch.Default.Time(startOf(m))) {
+		return 0
+	}
+	return t
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func mustStruct(m map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var goldenGraph = Graph{
+	Entities: []*model.Entity{
+		entity.MustNew(&model.Person{Key: "p1", Name: "Ada Lovelace", Role: "analyst", Tags: []string{"vip", "uk"}}),
+		entity.MustNew(&model.Organization{Key: "o1", Name: "Acme", Type: "company", DiscoveredAt: 1600000000, LastVisited: 1700000000}),
+		entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com/a&b", DiscoveredAt: 1650000000, LastVisited: 1600000000}),
+		entity.MustNew(&model.Event{
+			Key:        "e1",
+			Title:      "Meeting <private>",
+			HappenedAt: 1700000000,
+			Location:   &model.LocationData{Latitude: 51.5074, Longitude: -0.1278, Locality: "London"},
+			Attributes: mustStruct(map[string]any{"attendees": 3}),
+		}),
+		entity.MustNew(&model.Person{Key: "p1", Name: "duplicate"}),
+	},
+	Relations: []*model.Relation{
+		{Id: "relations/r1", From: "persons/p1", To: "organizations/o1", Label: "member_of", Confidence: 80, CreatedAt: 1690000000},
+		{From: "persons/p1", To: "events/e1", Label: "participated_in", Name: "chair"},
+		{From: "persons/p1", To: "websites/missing", Label: "related_to"},
+	},
+}
+
+func TestGolden(t *testing.T) {
+	writers := map[string]func(io.Writer, Graph) error{
+		"basic.graphml": WriteGraphML,
+		"basic.gexf":    WriteGEXF,
+	}
+	for name, write := range writers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := write(&buf, goldenGraph); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join("testdata", name)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("output differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestTimesMilliseconds(t *testing.T) {
+	if s, ms := gexfTime(1700000000), gexfTime(1700000000000); s != ms || s != "2023-11-14T22:13:20Z" {
+		t.Errorf("gexfTime = %q for seconds and %q for milliseconds", s, ms)
+	}
+	tests := []struct {
+		w    *model.Website
+		want int64
+	}{
+		{&model.Website{DiscoveredAt: 1600000000, LastVisited: 1700000000000}, 1700000000000},
+		{&model.Website{DiscoveredAt: 1600000000000, LastVisited: 1700000000}, 1700000000},
+		{&model.Website{DiscoveredAt: 1700000000000, LastVisited: 1600000000}, 0},
+	}
+	for _, tt := range tests {
+		if got := endOf(tt.w.ProtoReflect()); got != tt.want {
+			t.Errorf("endOf(%v) = %d, want %d", tt.w, got, tt.want)
+		}
+	}
+}
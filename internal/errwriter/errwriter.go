@@ -0,0 +1,33 @@
+// Package errwriter formats text to an io.Writer, keeping the first write
+// error so callers check it once at the end.
+package errwriter
+
+import (
+	"fmt"
+	"io"
+)
+
+// Writer formats to an underlying writer until a write fails. Later
+// writes are skipped.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+// New returns a Writer writing to w.
+func New(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Printf formats according to format and writes the result, unless an
+// earlier write failed.
+func (w *Writer) Printf(format string, args ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+// Err returns the first write error, if any.
+func (w *Writer) Err() error {
+	return w.err
+}
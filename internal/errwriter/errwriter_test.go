@@ -0,0 +1,53 @@
+package errwriter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errFull = errors.New("full")
+
+// limited accepts n writes and fails the rest.
+type limited struct {
+	n     int
+	calls int
+	b     strings.Builder
+}
+
+func (l *limited) Write(p []byte) (int, error) {
+	l.calls++
+	if l.calls > l.n {
+		return 0, errFull
+	}
+	return l.b.Write(p)
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		n       int
+		want    string
+		calls   int
+		wantErr error
+	}{
+		{3, "a1b2c3", 3, nil},
+		{1, "a1", 2, errFull},
+		{0, "", 1, errFull},
+	}
+	for _, tt := range tests {
+		l := &limited{n: tt.n}
+		w := New(l)
+		w.Printf("a%d", 1)
+		w.Printf("b%d", 2)
+		w.Printf("c%d", 3)
+		if got := l.b.String(); got != tt.want {
+			t.Errorf("n=%d: wrote %q, want %q", tt.n, got, tt.want)
+		}
+		if l.calls != tt.calls {
+			t.Errorf("n=%d: %d writes, want %d", tt.n, l.calls, tt.calls)
+		}
+		if err := w.Err(); err != tt.wantErr {
+			t.Errorf("n=%d: Err() = %v, want %v", tt.n, err, tt.wantErr)
+		}
+	}
+}
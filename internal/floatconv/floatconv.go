@@ -0,0 +1,12 @@
+// Package floatconv converts the float32 coordinates of the model for
+// output.
+package floatconv
+
+import "strconv"
+
+// Widen converts f to the float64 with the same shortest decimal form, so
+// 2.35 is written as 2.35 rather than 2.3499999046325684.
+func Widen(f float32) float64 {
+	d, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return d
+}
@@ -0,0 +1,21 @@
+package floatconv
+
+import "testing"
+
+func TestWiden(t *testing.T) {
+	tests := []struct {
+		in   float32
+		want float64
+	}{
+		{0, 0},
+		{2.35, 2.35},
+		{-33.8688, -33.8688},
+		{151.2093, 151.2093},
+		{1e-7, 1e-7},
+	}
+	for _, tt := range tests {
+		if got := Widen(tt.in); got != tt.want {
+			t.Errorf("Widen(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+// Package locsource names the LocationSource values in export formats.
+package locsource
+
+import (
+	"strings"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+const prefix = "LOCATION_SOURCE_"
+
+// Name returns the lower-case name of s without its prefix, such as "gps",
+// or "" for LOCATION_SOURCE_UNSPECIFIED.
+func Name(s model.LocationSource) string {
+	if s == model.LocationSource_LOCATION_SOURCE_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(s.String(), prefix))
+}
+
+// Parse returns the source named name, in any case, or
+// LOCATION_SOURCE_UNSPECIFIED if there is none.
+func Parse(name string) model.LocationSource {
+	return model.LocationSource(model.LocationSource_value[prefix+strings.ToUpper(name)])
+}
@@ -0,0 +1,34 @@
+package locsource
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		source model.LocationSource
+		name   string
+	}{
+		{model.LocationSource_LOCATION_SOURCE_UNSPECIFIED, ""},
+		{model.LocationSource_LOCATION_SOURCE_GPS, "gps"},
+		{model.LocationSource_LOCATION_SOURCE_GEOCODED, "geocoded"},
+		{model.LocationSource_LOCATION_SOURCE_INFERRED, "inferred"},
+		{model.LocationSource_LOCATION_SOURCE_MANUAL, "manual"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.source); got != tt.name {
+			t.Errorf("Name(%v) = %q, want %q", tt.source, got, tt.name)
+		}
+		if got := Parse(tt.name); got != tt.source {
+			t.Errorf("Parse(%q) = %v, want %v", tt.name, got, tt.source)
+		}
+	}
+	if got := Parse("GPS"); got != model.LocationSource_LOCATION_SOURCE_GPS {
+		t.Errorf("Parse(%q) = %v, want LOCATION_SOURCE_GPS", "GPS", got)
+	}
+	if got := Parse("radar"); got != model.LocationSource_LOCATION_SOURCE_UNSPECIFIED {
+		t.Errorf("Parse(%q) = %v, want LOCATION_SOURCE_UNSPECIFIED", "radar", got)
+	}
+}
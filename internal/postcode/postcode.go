@@ -0,0 +1,34 @@
+// Package postcode reads and writes the postcode of LocationData, keeping
+// the deprecated numeric postal_code field in step. Package geo exports it
+// as PostalCode and SetPostalCode; packages geo depends on use it directly.
+package postcode
+
+import (
+	"strconv"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// Of returns the postcode of l, falling back to the deprecated numeric
+// postal_code field for documents written before postcode existed.
+func Of(l *model.LocationData) string {
+	if s := l.GetPostcode(); s != "" {
+		return s
+	}
+	if n := l.GetPostalCode(); n != 0 {
+		return strconv.Itoa(int(n))
+	}
+	return ""
+}
+
+// Set sets the postcode of l. The deprecated numeric field is kept in step
+// for older readers: it is set when code survives a round trip through an
+// int32, such as "75001", and cleared otherwise, such as for "02134" or
+// "SW1A 1AA".
+func Set(l *model.LocationData, code string) {
+	l.Postcode = code
+	l.PostalCode = 0
+	if n, err := strconv.ParseInt(code, 10, 32); err == nil && n > 0 && strconv.Itoa(int(n)) == code {
+		l.PostalCode = int32(n)
+	}
+}
@@ -0,0 +1,52 @@
+package postcode
+
+import (
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		code    string
+		numeric int32
+	}{
+		{"75001", 75001},
+		{"02134", 0},
+		{"SW1A 1AA", 0},
+		{"", 0},
+		{"0", 0},
+		{"-5", 0},
+		{"+5", 0},
+		{"2147483647", 2147483647},
+		{"2147483648", 0},
+	}
+	for _, tt := range tests {
+		l := &model.LocationData{PostalCode: 12345}
+		Set(l, tt.code)
+		if l.GetPostcode() != tt.code || l.GetPostalCode() != tt.numeric {
+			t.Errorf("Set(%q) = %q, %d, want %q, %d", tt.code, l.GetPostcode(), l.GetPostalCode(), tt.code, tt.numeric)
+		}
+		if got := Of(l); got != tt.code {
+			t.Errorf("Of after Set(%q) = %q", tt.code, got)
+		}
+	}
+}
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		l    *model.LocationData
+		want string
+	}{
+		{nil, ""},
+		{&model.LocationData{}, ""},
+		{&model.LocationData{PostalCode: 75001}, "75001"},
+		{&model.LocationData{Postcode: "02134", PostalCode: 2134}, "02134"},
+		{&model.LocationData{Postcode: "SW1A 1AA"}, "SW1A 1AA"},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.l); got != tt.want {
+			t.Errorf("Of(%v) = %q, want %q", tt.l, got, tt.want)
+		}
+	}
+}
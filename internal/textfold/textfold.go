@@ -0,0 +1,68 @@
+// Package textfold folds place and region names to a form that compares
+// equal across casing, diacritics and punctuation.
+package textfold
+
+import (
+	"strings"
+	"unicode"
+)
+
+// folds maps letters with diacritics to their base letters.
+var folds = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+func init() {
+	for base, letters := range map[string]string{
+		"a": "àáâãäåāăąạảấầẩẫậắằẳẵặ",
+		"c": "çćĉċč",
+		"d": "ď",
+		"e": "èéêëēĕėęěẹẻẽếềểễệ",
+		"g": "ĝğġģ",
+		"h": "ĥħ",
+		"i": "ìíîïĩīĭįỉị",
+		"j": "ĵ",
+		"k": "ķ",
+		"l": "ĺļľŀ",
+		"n": "ñńņňŉ",
+		"o": "òóôõöōŏőơọỏốồổỗộớờởỡợ",
+		"r": "ŕŗř",
+		"s": "śŝşšș",
+		"t": "ţťŧț",
+		"u": "ùúûüũūŭůűųưụủứừửữự",
+		"w": "ŵ",
+		"y": "ýÿŷỳỵỷỹ",
+		"z": "źżž",
+	} {
+		for _, r := range letters {
+			folds[r] = base
+		}
+	}
+}
+
+// Fold lower-cases s, strips common diacritics and collapses everything
+// other than letters and digits into single spaces, so "São Paulo",
+// "SAO PAULO" and "sao-paulo" all fold to "sao paulo".
+func Fold(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case folds[r] != "":
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(folds[r])
+			space = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package textfold
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"São Paulo", "sao paulo"},
+		{"SAO PAULO", "sao paulo"},
+		{"sao-paulo", "sao paulo"},
+		{"  Zürich ", "zurich"},
+		{"Straße", "strasse"},
+		{"Łódź", "lodz"},
+		{"Hồ Chí Minh", "ho chi minh"},
+		{"St. John's", "st john s"},
+		{"東京", "東京"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Fold(tt.in); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package uuid derives name-based (version 5) UUIDs, used wherever an
+// exchange format needs a stable UUID for a document.
+package uuid
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+)
+
+// V5 returns the version 5 UUID of name in namespace ns, in canonical
+// form.
+func V5(ns [16]byte, name string) string {
+	h := sha1.New()
+	h.Write(ns[:])
+	h.Write([]byte(name))
+	var u [16]byte
+	copy(u[:], h.Sum(nil))
+	u[6] = u[6]&0x0f | 0x50
+	u[8] = u[8]&0x3f | 0x80
+	return format(u)
+}
+
+func format(u [16]byte) string {
+	s := hex.EncodeToString(u[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// MustParse returns the bytes of a UUID written in canonical form. It
+// panics if s is not a UUID.
+func MustParse(s string) [16]byte {
+	var u [16]byte
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		panic("uuid: bad uuid " + s)
+	}
+	copy(u[:], b)
+	return u
+}
@@ -0,0 +1,38 @@
+package uuid
+
+import "testing"
+
+// dnsNamespace is the RFC 4122 namespace for domain names.
+var dnsNamespace = MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+func TestV5(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"python.org", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+		{"www.example.com", "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+	}
+	for _, tt := range tests {
+		if got := V5(dnsNamespace, tt.name); got != tt.want {
+			t.Errorf("V5(dns, %q) = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	const s = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	if got := format(MustParse(s)); got != s {
+		t.Errorf("format(MustParse(%q)) = %s", s, got)
+	}
+	for _, bad := range []string{"", "6ba7b810", "6ba7b810-9dad-11d1-80b4-00c04fd430cz"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("MustParse(%q) did not panic", bad)
+				}
+			}()
+			MustParse(bad)
+		}()
+	}
+}
@@ -0,0 +1,270 @@
+// Package jsonld publishes the OSINT model as schema.org linked data in
+// JSON-LD.
+//
+// The mapping is:
+//
+//	Person        schema:Person        name, alternateName (aliases),
+//	                                   jobTitle (role), nationality,
+//	                                   birthDate
+//	Organization  schema:Organization  name, additionalType, foundingDate
+//	Website       schema:WebSite       url, name (title), description,
+//	                                   dateCreated (founded_at)
+//	Event         schema:Event         name (title), description,
+//	                                   additionalType, startDate, location
+//	                                   (Place with GeoCoordinates and a
+//	                                   PostalAddress)
+//	Source        schema:CreativeWork  url, name, headline (title),
+//	                                   description, dateCreated
+//
+// Tags become keywords and attributes become additionalProperty
+// PropertyValue nodes. The @id of a node is its document handle appended
+// to a base IRI, so the same document always gets the same identifier.
+// ACL fields, revisions and crawl bookkeeping (discovered_at,
+// last_visited) are never published. birthDate and foundingDate are
+// schema.org Dates at the precision of the fuzzy date, so "1970" stays a
+// year; their time of day, circa flag and range end are dropped. Other
+// timestamps are read with epoch.Default, like the dates, and imported as
+// Unix seconds.
+//
+// Unmarshal reads the compacted form written by this package: single
+// nodes, arrays and @graph documents using the schema.org context. It is
+// not a general JSON-LD processor.
+package jsonld
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/epoch"
+	"github.com/omnsight/omniscent-library/fuzzydate"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Context is the @context of every document written.
+const Context = "https://schema.org"
+
+// DefaultBase is the base IRI of node identifiers unless WithBase is used.
+const DefaultBase = "urn:omnsight:"
+
+// schema.org types used by the mapping.
+const (
+	TypePerson         = "Person"
+	TypeOrganization   = "Organization"
+	TypeWebSite        = "WebSite"
+	TypeEvent          = "Event"
+	TypeCreativeWork   = "CreativeWork"
+	TypeCountry        = "Country"
+	TypePlace          = "Place"
+	TypeGeoCoordinates = "GeoCoordinates"
+	TypePostalAddress  = "PostalAddress"
+	TypePropertyValue  = "PropertyValue"
+)
+
+// ErrUnknownType is returned for nodes whose @type has no model
+// equivalent.
+var ErrUnknownType = errors.New("jsonld: unknown node type")
+
+// Node is a schema.org node. Only the properties relevant to Type are set.
+type Node struct {
+	Context any    `json:"@context,omitempty"`
+	ID      string `json:"@id,omitempty"`
+	Type    string `json:"@type"`
+
+	Name           string   `json:"name,omitempty"`
+	AlternateName  []string `json:"alternateName,omitempty"`
+	Headline       string   `json:"headline,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	AdditionalType string   `json:"additionalType,omitempty"`
+	JobTitle       string   `json:"jobTitle,omitempty"`
+	Nationality    *Node    `json:"nationality,omitempty"`
+
+	BirthDate    string `json:"birthDate,omitempty"`
+	FoundingDate string `json:"foundingDate,omitempty"`
+	StartDate    string `json:"startDate,omitempty"`
+	DateCreated  string `json:"dateCreated,omitempty"`
+	DateModified string `json:"dateModified,omitempty"`
+
+	Location  *Node    `json:"location,omitempty"`
+	Geo       *Node    `json:"geo,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	Address            *Node    `json:"address,omitempty"`
+	StreetAddress      string   `json:"streetAddress,omitempty"`
+	AddressLocality    string   `json:"addressLocality,omitempty"`
+	AddressRegion      string   `json:"addressRegion,omitempty"`
+	AddressCountry     string   `json:"addressCountry,omitempty"`
+	PostalCode         string   `json:"postalCode,omitempty"`
+	Keywords           []string `json:"keywords,omitempty"`
+	AdditionalProperty []*Node  `json:"additionalProperty,omitempty"`
+	Value              any      `json:"value,omitempty"`
+}
+
+// Option configures the conversion.
+type Option func(*config)
+
+type config struct {
+	base string
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{base: DefaultBase}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithBase sets the IRI that document handles are appended to when
+// forming @id, for example "https://osint.example.org/". Identifiers
+// outside base are ignored on import.
+func WithBase(base string) Option {
+	return func(c *config) { c.base = base }
+}
+
+// id returns the @id of a document.
+func (c *config) id(m proto.Message) (string, error) {
+	h, err := arango.HandleOf(m)
+	if err != nil {
+		return "", err
+	}
+	return c.base + h.String(), nil
+}
+
+// handle returns the document handle encoded in id, or a zero handle when
+// id is not under the configured base.
+func (c *config) handle(id string) (arango.Handle, error) {
+	rest, ok := strings.CutPrefix(id, c.base)
+	if !ok || id == "" {
+		return arango.Handle{}, nil
+	}
+	return arango.ParseHandle(rest)
+}
+
+// Marshal encodes a document or Entity as a standalone JSON-LD node.
+func Marshal(m proto.Message, opts ...Option) ([]byte, error) {
+	n, err := FromEntity(m, opts...)
+	if err != nil {
+		return nil, err
+	}
+	n.Context = Context
+	return json.Marshal(n)
+}
+
+// graphDocument is a JSON-LD document holding several nodes.
+type graphDocument struct {
+	Context any     `json:"@context,omitempty"`
+	Graph   []*Node `json:"@graph"`
+}
+
+// MarshalGraph encodes entities as one JSON-LD document with a @graph.
+func MarshalGraph(entities []*model.Entity, opts ...Option) ([]byte, error) {
+	doc := graphDocument{Context: Context, Graph: make([]*Node, 0, len(entities))}
+	for _, e := range entities {
+		n, err := FromEntity(e, opts...)
+		if err != nil {
+			return nil, err
+		}
+		doc.Graph = append(doc.Graph, n)
+	}
+	return json.Marshal(doc)
+}
+
+// Unmarshal decodes a single node, an array of nodes or a @graph document.
+func Unmarshal(data []byte, opts ...Option) ([]*model.Entity, error) {
+	var nodes []*Node
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &nodes); err != nil {
+			return nil, err
+		}
+	} else {
+		var doc graphDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		nodes = doc.Graph
+		if doc.Graph == nil {
+			var n Node
+			if err := json.Unmarshal(data, &n); err != nil {
+				return nil, err
+			}
+			nodes = []*Node{&n}
+		}
+	}
+	out := make([]*model.Entity, 0, len(nodes))
+	for i, n := range nodes {
+		e, err := ToEntity(n, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("node %d: %w", i, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Date layouts.
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = time.RFC3339
+)
+
+// formatDate returns the start of d as an ISO 8601 date at d's precision,
+// such as "1970" or "1970-05". Circa and range ends are not published.
+func formatDate(d *model.FuzzyDate) string {
+	if d == nil {
+		return ""
+	}
+	return fuzzydate.Format(&model.FuzzyDate{
+		Year: d.GetYear(), Month: d.GetMonth(), Day: d.GetDay(), Precision: d.GetPrecision(),
+	})
+}
+
+// parseFuzzyDate reads an ISO 8601 date of any precision, or the day of a
+// date-time. It returns nil if s is empty or malformed.
+func parseFuzzyDate(s string) *model.FuzzyDate {
+	if d, err := fuzzydate.Parse(s); err == nil {
+		return d
+	}
+	return fuzzydate.FromTime(parseTime(s))
+}
+
+// isExactDay reports whether d is a plain day, which the int64 date fields
+// hold without a fuzzy date unless it is 1970-01-01.
+func isExactDay(d *model.FuzzyDate) bool {
+	return d.GetPrecision() == fuzzydate.PrecisionDay && !d.GetCirca() && !fuzzydate.IsRange(d)
+}
+
+// formatDateTime formats a timestamp field read with epoch.Default, or
+// returns "" if it is unset.
+func formatDateTime(v int64) string {
+	t := epoch.Default.Time(v)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateTimeLayout)
+}
+
+// parseDate returns an ISO 8601 date or date-time as a timestamp written
+// with epoch.Default, or 0 if s is empty or malformed.
+func parseDate(s string) int64 {
+	return epoch.Default.Unix(parseTime(s))
+}
+
+// parseTime reads an ISO 8601 date or date-time, taking dates without a
+// zone as UTC. It returns the zero time if s is empty or malformed.
+func parseTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", dateLayout, "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
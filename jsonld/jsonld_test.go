@@ -0,0 +1,208 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/epoch"
+	"github.com/omnsight/omniscent-library/fuzzydate"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func mustStruct(m map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var entities = []*model.Entity{
+	entity.MustNew(&model.Person{
+		Key: "p1", Name: "Ada Lovelace", Aliases: []string{"Countess"}, Role: "analyst",
+		Nationality: "GB", BirthDate: -4861728000, UpdatedAt: 1700000000,
+		Tags: []string{"vip"}, Attributes: mustStruct(map[string]any{"score": 3, "active": true}),
+	}),
+	entity.MustNew(&model.Organization{Key: "o1", Name: "Acme", Type: "company", FoundedAt: 946684800}),
+	entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com", Title: "Example", Description: "home", FoundedAt: 820454400}),
+	entity.MustNew(&model.Event{
+		Key: "e1", Title: "Meeting", Description: "quarterly", Type: "meeting",
+		HappenedAt: 1700000000, UpdatedAt: 1700003600,
+		Location: &model.LocationData{Latitude: 51.5074, Longitude: -0.1278, Locality: "London", CountryCode: "GB"},
+	}),
+	entity.MustNew(&model.Source{
+		Key: "s1", Url: "https://example.com/report", Name: "report", Title: "The report",
+		Type: "article", CreatedAt: 1690000000, UpdatedAt: 1690000060,
+	}),
+}
+
+func TestContext(t *testing.T) {
+	b, err := Marshal(entities[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var node map[string]any
+	if err := json.Unmarshal(b, &node); err != nil {
+		t.Fatal(err)
+	}
+	if node["@context"] != Context || node["@type"] != TypePerson || node["@id"] != DefaultBase+"persons/p1" {
+		t.Errorf("Marshal = %s", b)
+	}
+
+	b, err = MarshalGraph(entities, WithBase("https://osint.example.org/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Context string           `json:"@context"`
+		Graph   []map[string]any `json:"@graph"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Context != Context || len(doc.Graph) != len(entities) {
+		t.Fatalf("MarshalGraph = %s", b)
+	}
+	for _, n := range doc.Graph {
+		if _, ok := n["@context"]; ok {
+			t.Errorf("graph node %v repeats @context", n["@id"])
+		}
+	}
+	if id := doc.Graph[1]["@id"]; id != "https://osint.example.org/organizations/o1" {
+		t.Errorf("@id = %v, want it under the base", id)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b, err := MarshalGraph(entities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(entities) {
+		t.Fatalf("read %d entities, want %d", len(got), len(entities))
+	}
+	for i := range entities {
+		if !proto.Equal(got[i], entities[i]) {
+			t.Errorf("entity %d = %v, want %v", i, got[i], entities[i])
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		keys    []string
+		wantErr error
+	}{
+		{"node", `{"@context":"https://schema.org","@type":"Person","@id":"urn:omnsight:persons/p1","name":"Ada"}`, []string{"p1"}, nil},
+		{"array", `[{"@type":"schema:Organization","name":"Acme"},{"@type":"https://schema.org/WebSite","url":"https://example.com"}]`, []string{"", ""}, nil},
+		{"graph", `{"@context":"https://schema.org","@graph":[{"@type":"Event","@id":"urn:omnsight:events/e1"}]}`, []string{"e1"}, nil},
+		{"outside base", `{"@type":"Person","@id":"https://elsewhere.org/persons/p1"}`, []string{""}, nil},
+		{"unknown type", `{"@type":"Recipe"}`, nil, ErrUnknownType},
+		{"wrong collection", `{"@type":"Person","@id":"urn:omnsight:events/e1"}`, nil, arango.ErrInvalidHandle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unmarshal([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unmarshal error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.keys) {
+				t.Fatalf("read %d entities, want %d", len(got), len(tt.keys))
+			}
+			for i, e := range got {
+				if key := entity.Unwrap(e).GetKey(); key != tt.keys[i] {
+					t.Errorf("entity %d key = %q, want %q", i, key, tt.keys[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyDates(t *testing.T) {
+	tests := []struct {
+		name      string
+		birth     *model.FuzzyDate
+		birthDate string
+		// back is the date read back, or nil if it equals birth.
+		back *model.FuzzyDate
+	}{
+		{"year", fuzzydate.Year(1970), "1970", nil},
+		{"month", fuzzydate.Month(1815, 12), "1815-12", nil},
+		{"day", fuzzydate.Day(1815, 12, 10), "1815-12-10", nil},
+		{"epoch day", fuzzydate.Day(1970, 1, 1), "1970-01-01", nil},
+		{"circa", fuzzydate.Circa(fuzzydate.Year(1970)), "1970", fuzzydate.Year(1970)},
+		{"range", &model.FuzzyDate{Year: 1990, EndYear: 1995, Precision: fuzzydate.PrecisionYear}, "1990", fuzzydate.Year(1990)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &model.Person{Key: "p1"}
+			o := &model.Organization{Key: "o1"}
+			if err := fuzzydate.SetBirthDate(p, tt.birth); err != nil {
+				t.Fatal(err)
+			}
+			if err := fuzzydate.SetFoundedAt(o, tt.birth); err != nil {
+				t.Fatal(err)
+			}
+			pn, err := FromEntity(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			on, err := FromEntity(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pn.BirthDate != tt.birthDate || on.FoundingDate != tt.birthDate {
+				t.Errorf("birthDate = %q, foundingDate = %q, want %q", pn.BirthDate, on.FoundingDate, tt.birthDate)
+			}
+			want := tt.back
+			if want == nil {
+				want = tt.birth
+			}
+			pe, err := ToEntity(pn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fuzzydate.BirthDate(pe.GetPerson()); !proto.Equal(got, want) {
+				t.Errorf("birth date read back = %q, want %q", fuzzydate.Format(got), fuzzydate.Format(want))
+			}
+			oe, err := ToEntity(on)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fuzzydate.FoundedAt(oe.GetOrganization()); !proto.Equal(got, want) {
+				t.Errorf("founding date read back = %q, want %q", fuzzydate.Format(got), fuzzydate.Format(want))
+			}
+		})
+	}
+}
+
+func TestMilliseconds(t *testing.T) {
+	for _, e := range entities {
+		seconds, err := FromEntity(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := proto.Clone(e)
+		epoch.Default.Normalize(m, epoch.Milliseconds)
+		millis, err := FromEntity(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := json.Marshal(seconds)
+		b, _ := json.Marshal(millis)
+		if string(a) != string(b) {
+			t.Errorf("node from milliseconds = %s, want %s", b, a)
+		}
+	}
+}
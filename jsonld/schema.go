@@ -0,0 +1,267 @@
+package jsonld
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/fuzzydate"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/floatconv"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FromEntity converts a document or Entity to a schema.org node. The
+// document must have a handle.
+func FromEntity(m proto.Message, opts ...Option) (*Node, error) {
+	c := newConfig(opts)
+	doc := m
+	if e, ok := m.(*model.Entity); ok {
+		doc = entity.Unwrap(e)
+	}
+	id, err := c.id(doc)
+	if err != nil {
+		return nil, err
+	}
+	var n *Node
+	switch d := doc.(type) {
+	case *model.Person:
+		n = &Node{
+			Type:          TypePerson,
+			Name:          d.GetName(),
+			AlternateName: d.GetAliases(),
+			JobTitle:      d.GetRole(),
+			BirthDate:     formatDate(fuzzydate.BirthDate(d)),
+			DateModified:  formatDateTime(d.GetUpdatedAt()),
+			Keywords:      d.GetTags(),
+		}
+		if d.GetNationality() != "" {
+			n.Nationality = &Node{Type: TypeCountry, Name: d.GetNationality()}
+		}
+	case *model.Organization:
+		n = &Node{
+			Type:           TypeOrganization,
+			Name:           d.GetName(),
+			AdditionalType: d.GetType(),
+			FoundingDate:   formatDate(fuzzydate.FoundedAt(d)),
+			Keywords:       d.GetTags(),
+		}
+	case *model.Website:
+		n = &Node{
+			Type:        TypeWebSite,
+			URL:         d.GetUrl(),
+			Name:        d.GetTitle(),
+			Description: d.GetDescription(),
+			DateCreated: formatDateTime(d.GetFoundedAt()),
+			Keywords:    d.GetTags(),
+		}
+	case *model.Event:
+		n = &Node{
+			Type:           TypeEvent,
+			Name:           d.GetTitle(),
+			Description:    d.GetDescription(),
+			AdditionalType: d.GetType(),
+			StartDate:      formatDateTime(d.GetHappenedAt()),
+			DateModified:   formatDateTime(d.GetUpdatedAt()),
+			Location:       place(d.GetLocation()),
+			Keywords:       d.GetTags(),
+		}
+	case *model.Source:
+		n = &Node{
+			Type:           TypeCreativeWork,
+			URL:            d.GetUrl(),
+			Name:           d.GetName(),
+			Headline:       d.GetTitle(),
+			Description:    d.GetDescription(),
+			AdditionalType: d.GetType(),
+			DateCreated:    formatDateTime(d.GetCreatedAt()),
+			DateModified:   formatDateTime(d.GetUpdatedAt()),
+			Keywords:       d.GetTags(),
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T", arango.ErrUnknownType, doc)
+	}
+	n.ID = id
+	n.AdditionalProperty = properties(doc.(entity.Document).GetAttributes())
+	return n, nil
+}
+
+// ToEntity converts a schema.org node back to an Entity. The key is taken
+// from @id when it lies under the configured base.
+func ToEntity(n *Node, opts ...Option) (*model.Entity, error) {
+	c := newConfig(opts)
+	h, err := c.handle(n.ID)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := attributes(n.AdditionalProperty)
+	if err != nil {
+		return nil, err
+	}
+	var doc proto.Message
+	switch typeName(n.Type) {
+	case TypePerson:
+		p := &model.Person{
+			Key:        h.Key,
+			Name:       n.Name,
+			Aliases:    n.AlternateName,
+			Role:       n.JobTitle,
+			UpdatedAt:  parseDate(n.DateModified),
+			Tags:       n.Keywords,
+			Attributes: attrs,
+		}
+		if n.Nationality != nil {
+			p.Nationality = n.Nationality.Name
+		}
+		if d := parseFuzzyDate(n.BirthDate); d != nil {
+			fuzzydate.SetBirthDate(p, d)
+			if isExactDay(d) && p.BirthDate != 0 {
+				p.FuzzyBirthDate = nil
+			}
+		}
+		doc = p
+	case TypeOrganization:
+		o := &model.Organization{
+			Key:        h.Key,
+			Name:       n.Name,
+			Type:       n.AdditionalType,
+			Tags:       n.Keywords,
+			Attributes: attrs,
+		}
+		if d := parseFuzzyDate(n.FoundingDate); d != nil {
+			fuzzydate.SetFoundedAt(o, d)
+			if isExactDay(d) && o.FoundedAt != 0 {
+				o.FuzzyFoundedAt = nil
+			}
+		}
+		doc = o
+	case TypeWebSite:
+		doc = &model.Website{
+			Key:         h.Key,
+			Url:         n.URL,
+			Title:       n.Name,
+			Description: n.Description,
+			FoundedAt:   parseDate(n.DateCreated),
+			Tags:        n.Keywords,
+			Attributes:  attrs,
+		}
+	case TypeEvent:
+		doc = &model.Event{
+			Key:         h.Key,
+			Title:       n.Name,
+			Description: n.Description,
+			Type:        n.AdditionalType,
+			HappenedAt:  parseDate(n.StartDate),
+			UpdatedAt:   parseDate(n.DateModified),
+			Location:    locationData(n.Location),
+			Tags:        n.Keywords,
+			Attributes:  attrs,
+		}
+	case TypeCreativeWork:
+		doc = &model.Source{
+			Key:         h.Key,
+			Url:         n.URL,
+			Name:        n.Name,
+			Title:       n.Headline,
+			Description: n.Description,
+			Type:        n.AdditionalType,
+			CreatedAt:   parseDate(n.DateCreated),
+			UpdatedAt:   parseDate(n.DateModified),
+			Tags:        n.Keywords,
+			Attributes:  attrs,
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, n.Type)
+	}
+	if !h.IsZero() {
+		if col, _ := arango.CollectionOf(doc); col != h.Collection {
+			return nil, fmt.Errorf("%w: %s is not a %s", arango.ErrInvalidHandle, n.ID, n.Type)
+		}
+	}
+	return entity.New(doc)
+}
+
+// typeName strips the schema.org prefixes from a compacted or expanded
+// type.
+func typeName(t string) string {
+	for _, prefix := range []string{"schema:", "https://schema.org/", "http://schema.org/"} {
+		if s, ok := strings.CutPrefix(t, prefix); ok {
+			return s
+		}
+	}
+	return t
+}
+
+func place(l *model.LocationData) *Node {
+	if l == nil {
+		return nil
+	}
+	lat, lon := floatconv.Widen(l.GetLatitude()), floatconv.Widen(l.GetLongitude())
+	p := &Node{
+		Type: TypePlace,
+		Geo:  &Node{Type: TypeGeoCoordinates, Latitude: &lat, Longitude: &lon},
+	}
+	a := &Node{
+		Type:            TypePostalAddress,
+		StreetAddress:   l.GetAddress(),
+		AddressLocality: l.GetLocality(),
+		AddressRegion:   l.GetAdministrativeArea(),
+		AddressCountry:  l.GetCountryCode(),
+		PostalCode:      geo.PostalCode(l),
+	}
+	if a.StreetAddress != "" || a.AddressLocality != "" || a.AddressRegion != "" || a.AddressCountry != "" || a.PostalCode != "" {
+		p.Address = a
+	}
+	return p
+}
+
+func locationData(p *Node) *model.LocationData {
+	if p == nil {
+		return nil
+	}
+	l := &model.LocationData{}
+	if g := p.Geo; g != nil {
+		if g.Latitude != nil {
+			l.Latitude = float32(*g.Latitude)
+		}
+		if g.Longitude != nil {
+			l.Longitude = float32(*g.Longitude)
+		}
+	}
+	if a := p.Address; a != nil {
+		l.Address = a.StreetAddress
+		l.Locality = a.AddressLocality
+		l.AdministrativeArea = a.AddressRegion
+		l.CountryCode = a.AddressCountry
+		geo.SetPostalCode(l, a.PostalCode)
+	}
+	return l
+}
+
+// properties converts attributes to PropertyValue nodes sorted by name.
+func properties(s *structpb.Struct) []*Node {
+	if len(s.GetFields()) == 0 {
+		return nil
+	}
+	out := make([]*Node, 0, len(s.GetFields()))
+	for k, v := range s.GetFields() {
+		out = append(out, &Node{Type: TypePropertyValue, Name: k, Value: v.AsInterface()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func attributes(props []*Node) (*structpb.Struct, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]any, len(props))
+	for _, p := range props {
+		m[p.Name] = p.Value
+	}
+	return structpb.NewStruct(m)
+}
@@ -0,0 +1,356 @@
+// Package kml writes located Events as KML 2.2 documents and KMZ archives
+// for Google Earth.
+//
+// Events are placed in one folder per Event.Type, carry a TimeStamp from
+// HappenedAt (read with epoch.Default) so the time slider works, and are
+// styled by their first tag. The address of the location is written as the
+// placemark address, its postcode, accuracy and source as ExtendedData,
+// and a non-zero altitude as an absolute third coordinate. Relations
+// between two exported events can be drawn as LineStrings.
+package kml
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/locsource"
+)
+
+// Namespace is the KML 2.2 namespace.
+const Namespace = "http://www.opengis.net/kml/2.2"
+
+// UntypedFolder names the folder of events with an empty type.
+const UntypedFolder = "Other"
+
+// Style is the look of placemarks with a given tag. Color uses the KML
+// aabbggrr hex form; empty fields fall back to Google Earth defaults.
+type Style struct {
+	Color string
+	Icon  string
+	Scale float64
+}
+
+// Option configures Write and WriteKMZ.
+type Option func(*writer)
+
+// WithName sets the document name.
+func WithName(name string) Option {
+	return func(w *writer) {
+		w.name = name
+	}
+}
+
+// WithTagStyle sets the style of placemarks whose first styled tag is tag.
+// Tags without an explicit style get a color derived from the tag name.
+func WithTagStyle(tag string, s Style) Option {
+	return func(w *writer) {
+		w.styles[tag] = s
+	}
+}
+
+// WithRelations draws each relation whose endpoints are both exported
+// events as a LineString in a "Relations" folder.
+func WithRelations(relations []*model.Relation) Option {
+	return func(w *writer) {
+		w.relations = append(w.relations, relations...)
+	}
+}
+
+type writer struct {
+	name      string
+	styles    map[string]Style
+	relations []*model.Relation
+}
+
+// Write encodes the events that have a location as a KML document.
+func Write(out io.Writer, events []*model.Event, opts ...Option) error {
+	w := &writer{styles: map[string]Style{}}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(w.build(events)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+// WriteKMZ writes a KMZ archive holding the document produced by Write as
+// doc.kml.
+func WriteKMZ(out io.Writer, events []*model.Event, opts ...Option) error {
+	zw := zip.NewWriter(out)
+	f, err := zw.Create("doc.kml")
+	if err != nil {
+		return err
+	}
+	if err := Write(f, events, opts...); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+type kml struct {
+	XMLName  xml.Name `xml:"kml"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Document document `xml:"Document"`
+}
+
+type document struct {
+	Name    string      `xml:"name,omitempty"`
+	Styles  []xmlStyle  `xml:"Style"`
+	Folders []xmlFolder `xml:"Folder"`
+}
+
+type xmlStyle struct {
+	ID        string     `xml:"id,attr"`
+	IconStyle *iconStyle `xml:"IconStyle,omitempty"`
+	LineStyle *lineStyle `xml:"LineStyle,omitempty"`
+}
+
+type iconStyle struct {
+	Color string  `xml:"color,omitempty"`
+	Scale float64 `xml:"scale,omitempty"`
+	Icon  *icon   `xml:"Icon,omitempty"`
+}
+
+type icon struct {
+	Href string `xml:"href"`
+}
+
+type lineStyle struct {
+	Color string  `xml:"color,omitempty"`
+	Width float64 `xml:"width,omitempty"`
+}
+
+type xmlFolder struct {
+	Name       string      `xml:"name"`
+	Placemarks []placemark `xml:"Placemark"`
+}
+
+// placemark lists its elements in the order the KML schema requires.
+type placemark struct {
+	ID           string        `xml:"id,attr,omitempty"`
+	Name         string        `xml:"name,omitempty"`
+	Address      string        `xml:"address,omitempty"`
+	Description  string        `xml:"description,omitempty"`
+	TimeStamp    *timeStamp    `xml:"TimeStamp,omitempty"`
+	StyleURL     string        `xml:"styleUrl,omitempty"`
+	ExtendedData *extendedData `xml:"ExtendedData,omitempty"`
+	Point        *point        `xml:"Point,omitempty"`
+	LineString   *lineString   `xml:"LineString,omitempty"`
+}
+
+type timeStamp struct {
+	When string `xml:"when"`
+}
+
+type extendedData struct {
+	Data []data `xml:"Data"`
+}
+
+type data struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type point struct {
+	AltitudeMode string `xml:"altitudeMode,omitempty"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+type lineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+const relationStyle = "relation"
+
+func (w *writer) build(events []*model.Event) kml {
+	doc := document{Name: w.name}
+	folders := map[string]*xmlFolder{}
+	coords := map[string]string{}
+	used := map[string]bool{}
+	for _, e := range events {
+		loc := e.GetLocation()
+		if loc == nil {
+			continue
+		}
+		c := coordinate(loc)
+		pm := placemark{
+			Name:         e.GetTitle(),
+			Address:      loc.GetAddress(),
+			Description:  e.GetDescription(),
+			ExtendedData: locationData(loc),
+			Point:        &point{Coordinates: c},
+		}
+		if alt := loc.GetAltitude(); alt != 0 {
+			pm.Point.AltitudeMode = "absolute"
+			pm.Point.Coordinates += "," + strconv.FormatFloat(float64(alt), 'f', -1, 32)
+		}
+		if h, err := arango.HandleOf(e); err == nil {
+			pm.ID = placemarkID(h.String())
+			coords[h.String()] = c
+		}
+		if e.GetHappenedAt() != 0 {
+			pm.TimeStamp = &timeStamp{When: epoch.HappenedAt(e).Format(time.RFC3339)}
+		}
+		if tag := w.styleTag(e.GetTags()); tag != "" {
+			pm.StyleURL = "#" + styleID(tag)
+			used[tag] = true
+		}
+		name := e.GetType()
+		if name == "" {
+			name = UntypedFolder
+		}
+		f, ok := folders[name]
+		if !ok {
+			f = &xmlFolder{Name: name}
+			folders[name] = f
+		}
+		f.Placemarks = append(f.Placemarks, pm)
+	}
+
+	tags := make([]string, 0, len(used))
+	for tag := range used {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		doc.Styles = append(doc.Styles, w.style(tag))
+	}
+	names := make([]string, 0, len(folders))
+	for name := range folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc.Folders = append(doc.Folders, *folders[name])
+	}
+
+	var lines []placemark
+	for _, r := range w.relations {
+		from, ok1 := coords[r.GetFrom()]
+		to, ok2 := coords[r.GetTo()]
+		if !ok1 || !ok2 {
+			continue
+		}
+		name := r.GetName()
+		if name == "" {
+			name = r.GetLabel()
+		}
+		lines = append(lines, placemark{
+			Name:       name,
+			StyleURL:   "#" + relationStyle,
+			LineString: &lineString{Tessellate: 1, Coordinates: from + " " + to},
+		})
+	}
+	if len(lines) > 0 {
+		doc.Styles = append(doc.Styles, xmlStyle{
+			ID:        relationStyle,
+			LineStyle: &lineStyle{Color: "ff00ffff", Width: 2},
+		})
+		doc.Folders = append(doc.Folders, xmlFolder{Name: "Relations", Placemarks: lines})
+	}
+	return kml{Xmlns: Namespace, Document: doc}
+}
+
+// locationData returns the ExtendedData of a placemark at loc, or nil if
+// loc has no postcode, accuracy or source.
+func locationData(loc *model.LocationData) *extendedData {
+	var d []data
+	if code := geo.PostalCode(loc); code != "" {
+		d = append(d, data{Name: "postcode", Value: code})
+	}
+	if acc := loc.GetAccuracy(); acc != 0 {
+		d = append(d, data{Name: "accuracy", Value: strconv.FormatFloat(float64(acc), 'f', -1, 32)})
+	}
+	if src := locsource.Name(loc.GetSource()); src != "" {
+		d = append(d, data{Name: "location_source", Value: src})
+	}
+	if d == nil {
+		return nil
+	}
+	return &extendedData{Data: d}
+}
+
+// styleTag returns the tag whose style applies to a placemark: the first
+// tag with an explicit style, or else the first tag.
+func (w *writer) styleTag(tags []string) string {
+	for _, t := range tags {
+		if _, ok := w.styles[t]; ok {
+			return t
+		}
+	}
+	if len(tags) > 0 {
+		return tags[0]
+	}
+	return ""
+}
+
+func (w *writer) style(tag string) xmlStyle {
+	s, ok := w.styles[tag]
+	if !ok {
+		s = Style{Color: tagColor(tag)}
+	}
+	st := xmlStyle{ID: styleID(tag), IconStyle: &iconStyle{Color: s.Color, Scale: s.Scale}}
+	if s.Icon != "" {
+		st.IconStyle.Icon = &icon{Href: s.Icon}
+	}
+	return st
+}
+
+// tagColor derives an opaque color from the tag name.
+func tagColor(tag string) string {
+	return fmt.Sprintf("ff%06x", hash(tag)&0xffffff)
+}
+
+// styleID turns a tag into a valid XML id.
+func styleID(tag string) string {
+	return "tag-" + xmlID(tag)
+}
+
+func placemarkID(handle string) string {
+	return xmlID(handle)
+}
+
+// xmlID replaces the characters of s not allowed in an XML id and appends
+// a hash of s, so strings differing only in replaced characters, such as
+// "a b" and "a/b", get distinct ids.
+func xmlID(s string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+	return fmt.Sprintf("%s-%08x", safe, hash(s))
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// coordinate formats loc as a KML "lon,lat" tuple.
+func coordinate(loc *model.LocationData) string {
+	return strconv.FormatFloat(float64(loc.GetLongitude()), 'f', -1, 32) + "," +
+		strconv.FormatFloat(float64(loc.GetLatitude()), 'f', -1, 32)
+}
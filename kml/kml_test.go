@@ -0,0 +1,102 @@
+package kml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestStyleIDsUnique(t *testing.T) {
+	tags := []string{"a b", "a/b", "a_b", "a-b", "é", "è"}
+	seen := map[string]string{}
+	for _, tag := range tags {
+		id := styleID(tag)
+		if prev, ok := seen[id]; ok {
+			t.Errorf("styleID(%q) = styleID(%q) = %s", tag, prev, id)
+		}
+		seen[id] = tag
+		if strings.Trim(id, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.") != "" {
+			t.Errorf("styleID(%q) = %q is not a valid id", tag, id)
+		}
+	}
+}
+
+func TestWriteStyles(t *testing.T) {
+	loc := &model.LocationData{Latitude: 51.5, Longitude: -0.12}
+	events := []*model.Event{
+		{Key: "e1", Title: "one", Tags: []string{"a b"}, Location: loc},
+		{Key: "e2", Title: "two", Tags: []string{"a/b"}, Location: loc},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, tag := range []string{"a b", "a/b"} {
+		id := styleID(tag)
+		if !strings.Contains(out, `<Style id="`+id+`">`) || !strings.Contains(out, "<styleUrl>#"+id+"</styleUrl>") {
+			t.Errorf("style %s of tag %q not written and referenced", id, tag)
+		}
+	}
+}
+
+func TestTimeStampMilliseconds(t *testing.T) {
+	loc := &model.LocationData{Latitude: 51.5, Longitude: -0.12}
+	for _, at := range []int64{1700000000, 1700000000000} {
+		var buf bytes.Buffer
+		if err := Write(&buf, []*model.Event{{Title: "e", HappenedAt: at, Location: loc}}); err != nil {
+			t.Fatal(err)
+		}
+		if want := "<when>2023-11-14T22:13:20Z</when>"; !strings.Contains(buf.String(), want) {
+			t.Errorf("happened_at %d: output lacks %s:\n%s", at, want, buf.String())
+		}
+	}
+}
+
+func TestWriteLocation(t *testing.T) {
+	tests := []struct {
+		loc  *model.LocationData
+		want []string
+	}{
+		{
+			&model.LocationData{Latitude: 51.5, Longitude: -0.12},
+			[]string{"<coordinates>-0.12,51.5</coordinates>"},
+		},
+		{
+			&model.LocationData{
+				Latitude: 51.501, Longitude: -0.1416, Altitude: 12.5, Accuracy: 30,
+				Source: model.LocationSource_LOCATION_SOURCE_GPS, Address: "Buckingham Palace, London SW1A 1AA",
+				Postcode: "SW1A 1AA",
+			},
+			[]string{
+				"<address>Buckingham Palace, London SW1A 1AA</address>",
+				`<Data name="postcode">`, "<value>SW1A 1AA</value>",
+				`<Data name="accuracy">`, "<value>30</value>",
+				`<Data name="location_source">`, "<value>gps</value>",
+				"<altitudeMode>absolute</altitudeMode>",
+				"<coordinates>-0.1416,51.501,12.5</coordinates>",
+			},
+		},
+		{
+			&model.LocationData{Latitude: 48.86, Longitude: 2.34, PostalCode: 75001},
+			[]string{`<Data name="postcode">`, "<value>75001</value>"},
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Write(&buf, []*model.Event{{Title: "e", Location: tt.loc}}); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		for _, w := range tt.want {
+			if !strings.Contains(out, w) {
+				t.Errorf("Write(%v) lacks %s:\n%s", tt.loc, w, out)
+			}
+		}
+		if tt.loc.GetAltitude() == 0 && strings.Contains(out, "altitudeMode") {
+			t.Errorf("Write(%v) sets altitudeMode without an altitude", tt.loc)
+		}
+	}
+}
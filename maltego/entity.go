@@ -0,0 +1,225 @@
+// Package maltego converts the OSINT model to and from Maltego entities and
+// reads and writes Maltego .mtgx graph archives.
+//
+// The entity mapping is:
+//
+//	Person        maltego.Person        person.fullname
+//	Organization  maltego.Organization  title
+//	Website       maltego.Website       fqdn
+//	Source        maltego.URL           url
+//
+// Model fields without a Maltego equivalent and the document key are kept
+// in omnsight.* properties so exported graphs import back unchanged.
+package maltego
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// Maltego entity types.
+const (
+	TypePerson       = "maltego.Person"
+	TypeOrganization = "maltego.Organization"
+	TypeWebsite      = "maltego.Website"
+	TypeURL          = "maltego.URL"
+)
+
+// Property names.
+const (
+	PropFullName   = "person.fullname"
+	PropFirstNames = "person.firstnames"
+	PropLastName   = "person.lastname"
+	PropTitle      = "title"
+	PropFQDN       = "fqdn"
+	PropURL        = "url"
+	PropShortTitle = "short-title"
+
+	PropKey         = "omnsight.key"
+	PropType        = "omnsight.type"
+	PropRole        = "omnsight.role"
+	PropNationality = "omnsight.nationality"
+	PropAliases     = "omnsight.aliases"
+	PropDescription = "omnsight.description"
+	PropSourceName  = "omnsight.name"
+	PropWebsiteURL  = "omnsight.url"
+	PropTags        = "omnsight.tags"
+	PropReliability = "omnsight.reliability"
+)
+
+// listSeparator joins repeated fields in a single property.
+const listSeparator = ";"
+
+// Property is one Maltego entity or link property.
+type Property struct {
+	Name        string
+	DisplayName string
+	Value       string
+	Hidden      bool
+}
+
+// Entity is a Maltego entity. Value is its main property.
+type Entity struct {
+	Type       string
+	Properties []Property
+}
+
+// Get returns the value of the named property.
+func (e *Entity) Get(name string) string {
+	for _, p := range e.Properties {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func (e *Entity) set(name, display, value string) {
+	if value != "" {
+		e.Properties = append(e.Properties, Property{Name: name, DisplayName: display, Value: value})
+	}
+}
+
+func (e *Entity) hidden(name, value string) {
+	if value != "" {
+		e.Properties = append(e.Properties, Property{Name: name, DisplayName: name, Value: value, Hidden: true})
+	}
+}
+
+// FromEntity converts a Person, Organization, Website or Source.
+func FromEntity(e *model.Entity) (*Entity, error) {
+	m := &Entity{}
+	switch v := entity.Unwrap(e).(type) {
+	case *model.Person:
+		m.Type = TypePerson
+		m.set(PropFullName, "Full Name", v.GetName())
+		if first, last, ok := strings.Cut(v.GetName(), " "); ok {
+			m.set(PropFirstNames, "First Names", first)
+			m.set(PropLastName, "Surname", last)
+		}
+		m.hidden(PropRole, v.GetRole())
+		m.hidden(PropNationality, v.GetNationality())
+		m.hidden(PropAliases, strings.Join(v.GetAliases(), listSeparator))
+	case *model.Organization:
+		m.Type = TypeOrganization
+		m.set(PropTitle, "Name", v.GetName())
+		m.hidden(PropType, v.GetType())
+	case *model.Website:
+		m.Type = TypeWebsite
+		m.set(PropFQDN, "Website", host(v.GetUrl()))
+		m.hidden(PropWebsiteURL, v.GetUrl())
+		m.hidden(PropTitle, v.GetTitle())
+		m.hidden(PropDescription, v.GetDescription())
+	case *model.Source:
+		m.Type = TypeURL
+		m.set(PropURL, "URL", v.GetUrl())
+		m.set(PropShortTitle, "Short title", v.GetName())
+		m.set(PropTitle, "Title", v.GetTitle())
+		m.hidden(PropType, v.GetType())
+		m.hidden(PropDescription, v.GetDescription())
+		if v.GetReliability() != 0 {
+			m.hidden(PropReliability, fmt.Sprint(v.GetReliability()))
+		}
+	default:
+		return nil, fmt.Errorf("maltego: %s has no Maltego entity type", entity.KindOf(e))
+	}
+	d := entity.Unwrap(e)
+	m.hidden(PropKey, d.GetKey())
+	m.hidden(PropTags, strings.Join(d.GetTags(), listSeparator))
+	return m, nil
+}
+
+// ToEntity converts a Maltego entity of one of the mapped types. The key is
+// taken from omnsight.key or derived from the type and main value.
+func ToEntity(m *Entity) (*model.Entity, error) {
+	key := m.Get(PropKey)
+	tags := split(m.Get(PropTags))
+	switch m.Type {
+	case TypePerson:
+		name := m.Get(PropFullName)
+		if name == "" {
+			name = strings.TrimSpace(m.Get(PropFirstNames) + " " + m.Get(PropLastName))
+		}
+		return entity.New(&model.Person{
+			Key:         orDerived(key, m.Type, name),
+			Role:        m.Get(PropRole),
+			Name:        name,
+			Nationality: m.Get(PropNationality),
+			Tags:        tags,
+			Aliases:     split(m.Get(PropAliases)),
+		})
+	case TypeOrganization:
+		name := m.Get(PropTitle)
+		return entity.New(&model.Organization{
+			Key:  orDerived(key, m.Type, name),
+			Type: m.Get(PropType),
+			Name: name,
+			Tags: tags,
+		})
+	case TypeWebsite:
+		fqdn := m.Get(PropFQDN)
+		u := m.Get(PropWebsiteURL)
+		if u == "" {
+			u = "https://" + fqdn
+		}
+		return entity.New(&model.Website{
+			Key:         orDerived(key, m.Type, fqdn),
+			Url:         u,
+			Title:       m.Get(PropTitle),
+			Description: m.Get(PropDescription),
+			Tags:        tags,
+		})
+	case TypeURL:
+		u := m.Get(PropURL)
+		s := &model.Source{
+			Key:         orDerived(key, m.Type, u),
+			Type:        m.Get(PropType),
+			Url:         u,
+			Name:        m.Get(PropShortTitle),
+			Title:       m.Get(PropTitle),
+			Description: m.Get(PropDescription),
+			Tags:        tags,
+		}
+		if r := m.Get(PropReliability); r != "" {
+			n, err := strconv.Atoi(r)
+			if err != nil {
+				return nil, fmt.Errorf("maltego: %s reliability: %w", m.Type, err)
+			}
+			s.Reliability = int32(n)
+		}
+		return entity.New(s)
+	}
+	return nil, fmt.Errorf("maltego: unsupported entity type %q", m.Type)
+}
+
+// host returns the host of a URL, or raw itself when it has none.
+func host(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return raw
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, listSeparator)
+}
+
+// orDerived returns key, or a stable key derived from the Maltego type and
+// main value when key is empty.
+func orDerived(key, typ, value string) string {
+	if key != "" {
+		return key
+	}
+	sum := sha1.Sum([]byte(typ + "\x00" + value))
+	return "mtg-" + hex.EncodeToString(sum[:10])
+}
@@ -0,0 +1,50 @@
+package maltego
+
+import (
+	"testing"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func TestToEntityReliability(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int32
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"3", 3, false},
+		{"-1", -1, false},
+		{"high", 0, true},
+		{"3 stars", 0, true},
+	}
+	for _, tt := range tests {
+		m := &Entity{Type: TypeURL}
+		m.set(PropURL, "URL", "https://example.com/report")
+		m.hidden(PropReliability, tt.value)
+		e, err := ToEntity(m)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("reliability %q: error %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if err == nil && e.GetSource().GetReliability() != tt.want {
+			t.Errorf("reliability %q = %d, want %d", tt.value, e.GetSource().GetReliability(), tt.want)
+		}
+	}
+}
+
+func TestSourceRoundTrip(t *testing.T) {
+	s := &model.Source{Key: "s1", Url: "https://example.com/report", Name: "report", Reliability: 4}
+	m, err := FromEntity(entity.MustNew(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := ToEntity(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e.GetSource(); got.GetKey() != "s1" || got.GetReliability() != 4 || got.GetName() != "report" {
+		t.Errorf("round trip = %v", got)
+	}
+}
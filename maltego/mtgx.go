@@ -0,0 +1,307 @@
+package maltego
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/edge"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Namespaces of the GraphML document inside an .mtgx archive.
+const (
+	GraphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+	MTGXNamespace    = "http://maltego.paterva.com/xml/mtgx"
+)
+
+// Link types and properties.
+const (
+	LinkManual          = "maltego.link.manual-link"
+	PropLinkLabel       = "maltego.link.manual.type"
+	PropLinkDescription = "maltego.link.manual.description"
+	PropLinkConfidence  = "omnsight.confidence"
+)
+
+// AttrLinkLabel is the relation attribute keeping a link label that ReadMTGX
+// replaced with edge.RelatedTo.
+const AttrLinkLabel = "maltego_link_label"
+
+const (
+	graphPath   = "Graphs/Graph1.graphml"
+	versionPath = "Version.properties"
+	versionFile = "maltego.client.version=omniscent-library\nmaltego.graph.version=1.2\n"
+)
+
+// The writer uses literal mtg: prefixes, declared on the root element, as
+// Maltego expects; the reader matches local names only.
+
+type mtgxGraphML struct {
+	XMLName xml.Name  `xml:"graphml"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	XmlnsMT string    `xml:"xmlns:mtg,attr"`
+	Keys    []mtgxKey `xml:"key"`
+	Graph   struct {
+		ID          string     `xml:"id,attr"`
+		EdgeDefault string     `xml:"edgedefault,attr"`
+		Nodes       []mtgxNode `xml:"node"`
+		Edges       []mtgxEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type mtgxKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+}
+
+type mtgxNode struct {
+	ID   string `xml:"id,attr"`
+	Data struct {
+		Key    string     `xml:"key,attr"`
+		Entity mtgxObject `xml:"mtg:MaltegoEntity"`
+	} `xml:"data"`
+}
+
+type mtgxEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   struct {
+		Key  string     `xml:"key,attr"`
+		Link mtgxObject `xml:"mtg:MaltegoLink"`
+	} `xml:"data"`
+}
+
+type mtgxObject struct {
+	Type       string         `xml:"type,attr"`
+	Properties []mtgxProperty `xml:"mtg:Properties>mtg:Property"`
+}
+
+type mtgxProperty struct {
+	Name        string `xml:"name,attr"`
+	DisplayName string `xml:"displayName,attr"`
+	Type        string `xml:"type,attr"`
+	Hidden      bool   `xml:"hidden,attr"`
+	Nullable    bool   `xml:"nullable,attr"`
+	ReadOnly    bool   `xml:"readonly,attr"`
+	Value       string `xml:"mtg:Value"`
+}
+
+// WriteMTGX writes g as a Maltego graph archive. Entities without a Maltego
+// type and relations touching them are skipped. A related_to relation
+// carrying AttrLinkLabel is written with that label.
+func WriteMTGX(w io.Writer, g graph.Graph) error {
+	doc := mtgxGraphML{
+		Xmlns:   GraphMLNamespace,
+		XmlnsMT: MTGXNamespace,
+		Keys: []mtgxKey{
+			{ID: "d0", For: "node", AttrName: "MaltegoEntity"},
+			{ID: "d1", For: "edge", AttrName: "MaltegoLink"},
+		},
+	}
+	doc.Graph.ID = "G"
+	doc.Graph.EdgeDefault = "directed"
+	nodes := map[string]string{}
+	for _, e := range g.Entities {
+		m, err := FromEntity(e)
+		if err != nil {
+			continue
+		}
+		h, err := arango.HandleOf(e)
+		if err != nil {
+			return err
+		}
+		n := mtgxNode{ID: "n" + strconv.Itoa(len(doc.Graph.Nodes))}
+		n.Data.Key = "d0"
+		n.Data.Entity = toXML(m.Type, m.Properties)
+		nodes[h.String()] = n.ID
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for _, r := range g.Relations {
+		src, ok1 := nodes[r.GetFrom()]
+		dst, ok2 := nodes[r.GetTo()]
+		if !ok1 || !ok2 {
+			continue
+		}
+		label := r.GetLabel()
+		if orig := r.GetAttributes().GetFields()[AttrLinkLabel].GetStringValue(); orig != "" && label == edge.RelatedTo {
+			label = orig
+		}
+		props := []Property{{Name: PropLinkLabel, DisplayName: "Label", Value: label}}
+		if r.GetName() != "" {
+			props = append(props, Property{Name: PropLinkDescription, DisplayName: "Description", Value: r.GetName()})
+		}
+		if r.GetConfidence() != 0 {
+			props = append(props, Property{Name: PropLinkConfidence, DisplayName: "Confidence", Value: strconv.Itoa(int(r.GetConfidence())), Hidden: true})
+		}
+		e := mtgxEdge{ID: "e" + strconv.Itoa(len(doc.Graph.Edges)), Source: src, Target: dst}
+		e.Data.Key = "d1"
+		e.Data.Link = toXML(LinkManual, props)
+		doc.Graph.Edges = append(doc.Graph.Edges, e)
+	}
+
+	zw := zip.NewWriter(w)
+	f, err := zw.Create(versionPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, versionFile); err != nil {
+		return err
+	}
+	f, err = zw.Create(graphPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func toXML(typ string, props []Property) mtgxObject {
+	o := mtgxObject{Type: typ}
+	for _, p := range props {
+		o.Properties = append(o.Properties, mtgxProperty{
+			Name:        p.Name,
+			DisplayName: p.DisplayName,
+			Type:        "string",
+			Hidden:      p.Hidden,
+			Nullable:    true,
+			Value:       p.Value,
+		})
+	}
+	return o
+}
+
+type readGraphML struct {
+	Graph struct {
+		Nodes []struct {
+			ID   string `xml:"id,attr"`
+			Data []struct {
+				Entity *readObject `xml:"MaltegoEntity"`
+			} `xml:"data"`
+		} `xml:"node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+			Data   []struct {
+				Link *readObject `xml:"MaltegoLink"`
+			} `xml:"data"`
+		} `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type readObject struct {
+	Type       string `xml:"type,attr"`
+	Properties []struct {
+		Name        string `xml:"name,attr"`
+		DisplayName string `xml:"displayName,attr"`
+		Hidden      bool   `xml:"hidden,attr"`
+		Value       string `xml:"Value"`
+	} `xml:"Properties>Property"`
+}
+
+func (o *readObject) entity() *Entity {
+	e := &Entity{Type: o.Type}
+	for _, p := range o.Properties {
+		e.Properties = append(e.Properties, Property{Name: p.Name, DisplayName: p.DisplayName, Value: p.Value, Hidden: p.Hidden})
+	}
+	return e
+}
+
+// ReadMTGX reads the first graph of a Maltego archive. Entities of
+// unsupported types are skipped along with their links. Each link becomes
+// a Relation labelled with its manual link type when that label is a
+// registered edge kind fitting the endpoints, and edge.RelatedTo otherwise,
+// in which case the original label is kept in the AttrLinkLabel attribute.
+func ReadMTGX(r io.ReaderAt, size int64) (graph.Graph, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return graph.Graph{}, err
+	}
+	var file *zip.File
+	for _, f := range zr.File {
+		if ok, _ := path.Match("Graphs/*.graphml", f.Name); ok {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return graph.Graph{}, fmt.Errorf("maltego: archive has no Graphs/*.graphml")
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return graph.Graph{}, err
+	}
+	defer rc.Close()
+	var doc readGraphML
+	if err := xml.NewDecoder(rc).Decode(&doc); err != nil {
+		return graph.Graph{}, fmt.Errorf("maltego: %s: %w", file.Name, err)
+	}
+
+	var g graph.Graph
+	handles := map[string]arango.Handle{}
+	seen := map[string]bool{}
+	for _, n := range doc.Graph.Nodes {
+		for _, d := range n.Data {
+			if d.Entity == nil {
+				continue
+			}
+			e, err := ToEntity(d.Entity.entity())
+			if err != nil {
+				continue
+			}
+			h, err := arango.HandleOf(e)
+			if err != nil {
+				return graph.Graph{}, fmt.Errorf("maltego: node %s: %w", n.ID, err)
+			}
+			handles[n.ID] = h
+			if !seen[h.String()] {
+				seen[h.String()] = true
+				g.Entities = append(g.Entities, e)
+			}
+		}
+	}
+	for _, e := range doc.Graph.Edges {
+		from, ok1 := handles[e.Source]
+		to, ok2 := handles[e.Target]
+		if !ok1 || !ok2 {
+			continue
+		}
+		r := &model.Relation{From: from.String(), To: to.String()}
+		for _, d := range e.Data {
+			if d.Link == nil {
+				continue
+			}
+			link := d.Link.entity()
+			r.Label = link.Get(PropLinkLabel)
+			r.Name = link.Get(PropLinkDescription)
+			if c, err := strconv.Atoi(link.Get(PropLinkConfidence)); err == nil {
+				r.Confidence = int32(c)
+			}
+		}
+		if k, ok := edge.Lookup(r.Label); !ok || !k.Allows(from.Collection, to.Collection) {
+			if label := strings.TrimSpace(r.Label); label != "" {
+				r.Attributes = &structpb.Struct{Fields: map[string]*structpb.Value{
+					AttrLinkLabel: structpb.NewStringValue(label),
+				}}
+			}
+			r.Label = edge.RelatedTo
+		}
+		g.Relations = append(g.Relations, r)
+	}
+	return g, nil
+}
@@ -0,0 +1,73 @@
+package maltego
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/edge"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func roundTrip(t *testing.T, g graph.Graph) graph.Graph {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteMTGX(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReadMTGX(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestMTGXRoundTrip(t *testing.T) {
+	g := graph.Graph{
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Person{Key: "p1", Name: "Ada Lovelace", Aliases: []string{"Countess"}}),
+			entity.MustNew(&model.Organization{Key: "o1", Name: "Acme", Type: "company"}),
+			entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com/about", Title: "About"}),
+			entity.MustNew(&model.Event{Key: "e1", Title: "no Maltego type"}),
+		},
+		Relations: []*model.Relation{
+			{From: "persons/p1", To: "organizations/o1", Label: edge.MemberOf, Confidence: 80},
+			{From: "persons/p1", To: "websites/w1", Label: "operates", Name: "since 2019"},
+			{From: "persons/p1", To: "events/e1", Label: edge.ParticipatedIn},
+		},
+	}
+	want := []*model.Relation{
+		{From: "persons/p1", To: "organizations/o1", Label: edge.MemberOf, Confidence: 80},
+		{From: "persons/p1", To: "websites/w1", Label: edge.RelatedTo, Name: "since 2019", Attributes: &structpb.Struct{
+			Fields: map[string]*structpb.Value{AttrLinkLabel: structpb.NewStringValue("operates")},
+		}},
+	}
+
+	got := roundTrip(t, g)
+	if len(got.Entities) != 3 {
+		t.Fatalf("read %d entities, want 3", len(got.Entities))
+	}
+	for i, e := range got.Entities {
+		if !proto.Equal(e, g.Entities[i]) {
+			t.Errorf("entity %d = %v, want %v", i, e, g.Entities[i])
+		}
+	}
+	if len(got.Relations) != len(want) {
+		t.Fatalf("read %d relations, want %d", len(got.Relations), len(want))
+	}
+	for i, r := range got.Relations {
+		if !proto.Equal(r, want[i]) {
+			t.Errorf("relation %d = %v, want %v", i, r, want[i])
+		}
+	}
+
+	again := roundTrip(t, got)
+	for i, r := range again.Relations {
+		if !proto.Equal(r, want[i]) {
+			t.Errorf("second round trip: relation %d = %v, want %v", i, r, want[i])
+		}
+	}
+}
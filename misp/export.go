@@ -0,0 +1,304 @@
+package misp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Export builds a MISP event from ev and the entities related to it.
+// Entities not connected to ev by one of relations are ignored, as are
+// related events. MISP UUIDs are taken from the misp_uuid attribute and
+// default to a UUIDv5 of the document handle.
+func Export(ev *model.Event, entities []*model.Entity, relations []*model.Relation) (*Event, error) {
+	h, err := arango.HandleOf(ev)
+	if err != nil {
+		return nil, err
+	}
+	attrs := ev.GetAttributes()
+	out := &Event{
+		UUID:          uuidOf(ev),
+		Info:          ev.GetTitle(),
+		Timestamp:     timestamp(ev.GetUpdatedAt()),
+		ThreatLevelID: str(attrs, AttrThreatLevel),
+		Analysis:      str(attrs, AttrAnalysis),
+		Distribution:  str(attrs, AttrDistribution),
+		Published:     attrs.GetFields()[AttrPublished].GetBoolValue(),
+		Tag:           tags(ev.GetTags()),
+	}
+	if ev.GetHappenedAt() != 0 {
+		out.Date = epoch.HappenedAt(ev).Format(dateLayout)
+	}
+	byHandle := map[string]entity.Tagged{}
+	for _, e := range entities {
+		if eh, err := arango.HandleOf(e); err == nil {
+			byHandle[eh.String()] = entity.Unwrap(e)
+		}
+	}
+	galaxies := map[string]*Galaxy{}
+	for _, r := range relations {
+		other := r.GetTo()
+		if r.GetTo() == h.String() {
+			other = r.GetFrom()
+		} else if r.GetFrom() != h.String() {
+			continue
+		}
+		switch v := byHandle[other].(type) {
+		case *model.Website:
+			exportWebsite(out, v)
+		case *model.Source:
+			exportSource(out, v)
+		case *model.Person:
+			exportPerson(out, v)
+		case *model.Organization:
+			exportOrganization(out, galaxies, v)
+		}
+	}
+	names := make([]string, 0, len(galaxies))
+	for name := range galaxies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Galaxy = append(out.Galaxy, *galaxies[name])
+	}
+	if err := restore(attrs, AttrOtherAttribute, &out.Attribute); err != nil {
+		return nil, err
+	}
+	if err := restore(attrs, AttrOtherObject, &out.Object); err != nil {
+		return nil, err
+	}
+	if err := restore(attrs, AttrOtherGalaxy, &out.Galaxy); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func exportWebsite(out *Event, w *model.Website) {
+	attrs := w.GetAttributes()
+	if uuids := attrs.GetFields()[AttrObjectAttributes].GetStructValue(); uuids != nil {
+		relation := "url"
+		if str(attrs, AttrType) == "domain-ip" {
+			relation = "domain"
+		}
+		out.Object = append(out.Object, Object{
+			UUID:         uuidOf(w),
+			Name:         str(attrs, AttrType),
+			MetaCategory: str(attrs, AttrCategory),
+			Timestamp:    timestamp(w.GetDiscoveredAt()),
+			Attribute:    objectAttributes(w, map[string][]string{relation: {w.GetUrl()}}),
+		})
+		return
+	}
+	typ := str(attrs, AttrType)
+	if typ == "" {
+		typ = "domain"
+		if u, err := url.Parse(w.GetUrl()); err == nil && u.IsAbs() {
+			typ = "url"
+		}
+	}
+	category := str(attrs, AttrCategory)
+	if category == "" {
+		category = "Network activity"
+	}
+	out.Attribute = append(out.Attribute, Attribute{
+		UUID:      uuidOf(w),
+		Type:      typ,
+		Category:  category,
+		Value:     w.GetUrl(),
+		Comment:   str(attrs, AttrComment),
+		ToIDs:     attrs.GetFields()[AttrToIDs].GetBoolValue(),
+		Timestamp: timestamp(w.GetDiscoveredAt()),
+		Tag:       tags(w.GetTags()),
+	})
+}
+
+func exportSource(out *Event, s *model.Source) {
+	if s.GetType() == SourceTypeOrg {
+		out.Orgc = &Org{UUID: uuidOf(s), Name: s.GetName()}
+		return
+	}
+	attrs := s.GetAttributes()
+	category := str(attrs, AttrCategory)
+	if category == "" {
+		category = "External analysis"
+	}
+	comment := str(attrs, AttrComment)
+	if comment == "" {
+		comment = s.GetName()
+	}
+	out.Attribute = append(out.Attribute, Attribute{
+		UUID:      uuidOf(s),
+		Type:      "link",
+		Category:  category,
+		Value:     s.GetUrl(),
+		Comment:   comment,
+		ToIDs:     attrs.GetFields()[AttrToIDs].GetBoolValue(),
+		Timestamp: timestamp(s.GetCreatedAt()),
+		Tag:       tags(s.GetTags()),
+	})
+}
+
+func exportPerson(out *Event, p *model.Person) {
+	values := map[string][]string{
+		"full-name":   {p.GetName()},
+		"nationality": {p.GetNationality()},
+		"role":        {p.GetRole()},
+		"alias":       p.GetAliases(),
+	}
+	if p.GetBirthDate() != 0 {
+		values["date-of-birth"] = []string{epoch.BirthDate(p).Format(dateLayout)}
+	}
+	attrs := p.GetAttributes()
+	out.Object = append(out.Object, Object{
+		UUID:         uuidOf(p),
+		Name:         "person",
+		MetaCategory: orDefault(str(attrs, AttrCategory), "misc"),
+		Timestamp:    timestamp(p.GetUpdatedAt()),
+		Attribute:    objectAttributes(p, values),
+	})
+}
+
+func exportOrganization(out *Event, galaxies map[string]*Galaxy, o *model.Organization) {
+	attrs := o.GetAttributes()
+	if name := str(attrs, AttrGalaxy); name != "" {
+		g, ok := galaxies[name]
+		if !ok {
+			g = &Galaxy{UUID: str(attrs, AttrGalaxyUUID), Name: name, Type: str(attrs, AttrGalaxyType)}
+			galaxies[name] = g
+		}
+		g.GalaxyCluster = append(g.GalaxyCluster, GalaxyCluster{
+			UUID:        uuidOf(o),
+			Type:        g.Type,
+			Value:       o.GetName(),
+			Description: str(attrs, AttrComment),
+			TagName:     str(attrs, AttrTagName),
+		})
+		return
+	}
+	out.Object = append(out.Object, Object{
+		UUID:         uuidOf(o),
+		Name:         orDefault(str(attrs, AttrType), "organization"),
+		MetaCategory: orDefault(str(attrs, AttrCategory), "misc"),
+		Timestamp:    timestamp(o.GetDiscoveredAt()),
+		Attribute: objectAttributes(o, map[string][]string{
+			"name": {o.GetName()},
+			"role": {o.GetType()},
+		}),
+	})
+}
+
+// objectAttributes builds text attributes for the non-empty values of the
+// object exported from d, in relation order, reusing the UUIDs recorded at
+// import and deriving the others from the object's UUID. A relation with
+// several values yields one attribute per value; see uuidKey.
+func objectAttributes(d entity.Document, values map[string][]string) []Attribute {
+	parent := uuidOf(d)
+	uuids := d.GetAttributes().GetFields()[AttrObjectAttributes].GetStructValue()
+	relations := make([]string, 0, len(values))
+	for rel := range values {
+		relations = append(relations, rel)
+	}
+	sort.Strings(relations)
+	var out []Attribute
+	for _, rel := range relations {
+		i := 0
+		for _, v := range values[rel] {
+			if v == "" {
+				continue
+			}
+			key := uuidKey(rel, i)
+			out = append(out, Attribute{
+				UUID:           orDefault(str(uuids, key), uuid.V5(namespace, parent+"/"+key)),
+				Type:           "text",
+				Value:          v,
+				ObjectRelation: rel,
+			})
+			i++
+		}
+	}
+	return out
+}
+
+// uuidKey returns the key under which AttrObjectAttributes records the UUID
+// of the i-th attribute with relation rel: rel itself for the first, and
+// rel/i for the others.
+func uuidKey(rel string, i int) string {
+	if i == 0 {
+		return rel
+	}
+	return rel + "/" + strconv.Itoa(i)
+}
+
+// restore appends the values preserved under key at import to dst.
+func restore[T any](attrs *structpb.Struct, key string, dst *[]T) error {
+	list := attrs.GetFields()[key].GetListValue()
+	if list == nil {
+		return nil
+	}
+	b, err := json.Marshal(list.AsSlice())
+	if err != nil {
+		return err
+	}
+	var items []T
+	if err := json.Unmarshal(b, &items); err != nil {
+		return fmt.Errorf("misp: restoring %s: %w", key, err)
+	}
+	*dst = append(*dst, items...)
+	return nil
+}
+
+// timestamp returns the MISP timestamp, in Unix seconds, of a timestamp
+// field read with epoch.Default.
+func timestamp(v int64) Timestamp {
+	t := epoch.Default.Time(v)
+	if t.IsZero() {
+		return 0
+	}
+	return Timestamp(t.Unix())
+}
+
+// namespace is the namespace of the UUIDs derived for documents that were
+// not imported from MISP.
+var namespace = uuid.MustParse("722517e0-185f-4b39-8ddb-036a5239b3d9")
+
+// uuidOf returns the MISP UUID of d: the one recorded at import, or a
+// UUIDv5 of its handle.
+func uuidOf(d entity.Document) string {
+	if u := str(d.GetAttributes(), AttrUUID); u != "" {
+		return u
+	}
+	h, _ := arango.HandleOf(d)
+	return uuid.V5(namespace, h.String())
+}
+
+func str(s *structpb.Struct, key string) string {
+	return s.GetFields()[key].GetStringValue()
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func tags(names []string) []Tag {
+	if len(names) == 0 {
+		return nil
+	}
+	out := make([]Tag, len(names))
+	for i, n := range names {
+		out[i] = Tag{Name: n}
+	}
+	return out
+}
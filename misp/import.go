@@ -0,0 +1,291 @@
+package misp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/edge"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// AttrObjectAttributes maps object_relation to attribute UUID for entities
+// imported from a MISP object, so Export can restore the object. The second
+// and later attributes sharing a relation are keyed relation/1, relation/2
+// and so on.
+const AttrObjectAttributes = "misp_object_attributes"
+
+const dateLayout = "2006-01-02"
+
+// Import converts a MISP event. The first entity returned is the Event;
+// the relations link it to every other entity.
+func Import(e *Event) ([]*model.Entity, []*model.Relation, error) {
+	im := &importer{other: map[string][]any{}}
+	if err := im.run(e); err != nil {
+		return nil, nil, fmt.Errorf("misp: event %s: %w", e.UUID, err)
+	}
+	return im.entities, im.relations, nil
+}
+
+type importer struct {
+	handle    string
+	entities  []*model.Entity
+	relations []*model.Relation
+	other     map[string][]any
+}
+
+func (im *importer) run(e *Event) error {
+	if err := arango.ValidateKey(e.UUID); err != nil {
+		return err
+	}
+	ev := &model.Event{
+		Key:       e.UUID,
+		Type:      EventType,
+		Title:     e.Info,
+		UpdatedAt: int64(e.Timestamp),
+		Tags:      tagNames(e.Tag),
+	}
+	if e.Date != "" {
+		d, err := time.Parse(dateLayout, e.Date)
+		if err != nil {
+			return err
+		}
+		ev.HappenedAt = d.Unix()
+	}
+	im.handle = arango.Handle{Collection: arango.EventCollection, Key: e.UUID}.String()
+	im.entities = append(im.entities, entity.MustNew(ev))
+
+	if e.Orgc != nil && e.Orgc.UUID != "" {
+		err := im.add(&model.Source{
+			Key:        e.Orgc.UUID,
+			Type:       SourceTypeOrg,
+			Name:       e.Orgc.Name,
+			Attributes: mustStruct(map[string]any{AttrUUID: e.Orgc.UUID}),
+		}, edge.ReportedBy)
+		if err != nil {
+			return err
+		}
+	}
+	for _, a := range e.Attribute {
+		if err := im.attribute(a); err != nil {
+			return err
+		}
+	}
+	for _, o := range e.Object {
+		if err := im.object(o); err != nil {
+			return err
+		}
+	}
+	for _, g := range e.Galaxy {
+		if err := im.galaxy(g); err != nil {
+			return err
+		}
+	}
+
+	attrs := map[string]any{
+		AttrUUID:         e.UUID,
+		AttrThreatLevel:  e.ThreatLevelID,
+		AttrAnalysis:     e.Analysis,
+		AttrDistribution: e.Distribution,
+		AttrPublished:    e.Published,
+	}
+	for k, v := range im.other {
+		attrs[k] = v
+	}
+	s, err := structpb.NewStruct(attrs)
+	if err != nil {
+		return err
+	}
+	ev.Attributes = s
+	return nil
+}
+
+// add records m and relates it to the event. ReportedBy points from the
+// event to m; all other labels point from m to the event, except RelatedTo
+// which is undirected and stored event first.
+func (im *importer) add(m entity.Tagged, label string) error {
+	if err := arango.ValidateKey(m.GetKey()); err != nil {
+		return err
+	}
+	h, err := arango.HandleOf(m)
+	if err != nil {
+		return err
+	}
+	im.entities = append(im.entities, entity.MustNew(m))
+	r := &model.Relation{From: im.handle, To: h.String(), Label: label}
+	if label != edge.ReportedBy && label != edge.RelatedTo {
+		r.From, r.To = r.To, r.From
+	}
+	im.relations = append(im.relations, r)
+	return nil
+}
+
+func (im *importer) attribute(a Attribute) error {
+	attrs := map[string]any{
+		AttrUUID:     a.UUID,
+		AttrType:     a.Type,
+		AttrCategory: a.Category,
+		AttrToIDs:    a.ToIDs,
+	}
+	if a.Comment != "" {
+		attrs[AttrComment] = a.Comment
+	}
+	switch a.Type {
+	case "url", "uri", "domain", "hostname":
+		return im.add(&model.Website{
+			Key:          a.UUID,
+			Url:          a.Value,
+			DiscoveredAt: int64(a.Timestamp),
+			Tags:         tagNames(a.Tag),
+			Attributes:   mustStruct(attrs),
+		}, edge.RelatedTo)
+	case "link":
+		return im.add(&model.Source{
+			Key:        a.UUID,
+			Type:       a.Type,
+			Url:        a.Value,
+			Name:       a.Comment,
+			CreatedAt:  int64(a.Timestamp),
+			Tags:       tagNames(a.Tag),
+			Attributes: mustStruct(attrs),
+		}, edge.ReportedBy)
+	}
+	return im.keep(AttrOtherAttribute, a)
+}
+
+func (im *importer) object(o Object) error {
+	all := map[string][]string{}
+	values := map[string]string{}
+	uuids := map[string]any{}
+	for _, a := range o.Attribute {
+		rel := a.ObjectRelation
+		if _, dup := values[rel]; !dup {
+			values[rel] = a.Value
+		}
+		uuids[uuidKey(rel, len(all[rel]))] = a.UUID
+		all[rel] = append(all[rel], a.Value)
+	}
+	attrs := map[string]any{
+		AttrUUID:             o.UUID,
+		AttrType:             o.Name,
+		AttrCategory:         o.MetaCategory,
+		AttrObjectAttributes: uuids,
+	}
+	switch o.Name {
+	case "person":
+		name := values["full-name"]
+		if name == "" {
+			name = strings.TrimSpace(values["first-name"] + " " + values["last-name"])
+		}
+		p := &model.Person{
+			Key:         o.UUID,
+			Role:        values["role"],
+			Name:        name,
+			Nationality: values["nationality"],
+			UpdatedAt:   int64(o.Timestamp),
+			Attributes:  mustStruct(attrs),
+		}
+		if dob := values["date-of-birth"]; dob != "" {
+			if d, err := time.Parse(dateLayout, dob); err == nil {
+				p.BirthDate = d.Unix()
+			}
+		}
+		for _, alias := range all["alias"] {
+			if alias != "" {
+				p.Aliases = append(p.Aliases, alias)
+			}
+		}
+		return im.add(p, edge.ParticipatedIn)
+	case "organization", "organisation":
+		return im.add(&model.Organization{
+			Key:          o.UUID,
+			Type:         values["role"],
+			Name:         values["name"],
+			DiscoveredAt: int64(o.Timestamp),
+			Attributes:   mustStruct(attrs),
+		}, edge.ParticipatedIn)
+	case "url", "domain-ip":
+		u := values["url"]
+		if u == "" {
+			u = values["domain"]
+		}
+		if u != "" {
+			return im.add(&model.Website{
+				Key:          o.UUID,
+				Url:          u,
+				DiscoveredAt: int64(o.Timestamp),
+				Attributes:   mustStruct(attrs),
+			}, edge.RelatedTo)
+		}
+	}
+	return im.keep(AttrOtherObject, o)
+}
+
+func (im *importer) galaxy(g Galaxy) error {
+	if g.Type != "threat-actor" {
+		return im.keep(AttrOtherGalaxy, g)
+	}
+	for _, c := range g.GalaxyCluster {
+		attrs := map[string]any{
+			AttrUUID:       c.UUID,
+			AttrGalaxy:     g.Name,
+			AttrGalaxyType: g.Type,
+			AttrTagName:    c.TagName,
+		}
+		if g.UUID != "" {
+			attrs[AttrGalaxyUUID] = g.UUID
+		}
+		if c.Description != "" {
+			attrs[AttrComment] = c.Description
+		}
+		err := im.add(&model.Organization{
+			Key:        c.UUID,
+			Type:       "threat-actor",
+			Name:       c.Value,
+			Attributes: mustStruct(attrs),
+		}, edge.ParticipatedIn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keep stores v under key in the event attributes as plain JSON values.
+func (im *importer) keep(key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var plain any
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	im.other[key] = append(im.other[key], plain)
+	return nil
+}
+
+func tagNames(tags []Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// mustStruct converts attrs, which only ever hold strings, bools and
+// nested string maps, to a Struct.
+func mustStruct(attrs map[string]any) *structpb.Struct {
+	s, err := structpb.NewStruct(attrs)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
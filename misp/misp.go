@@ -0,0 +1,183 @@
+// Package misp converts MISP events to and from the OSINT model.
+//
+// Import maps a MISP event to an Event and the entities it references:
+//
+//	url and uri attributes, url objects         Website
+//	domain and hostname attributes, domain-ip   Website
+//	link attributes                             Source
+//	the creator organisation (Orgc)             Source
+//	person objects                              Person
+//	organization objects, threat-actor clusters Organization
+//
+// Every entity keeps its MISP UUID as _key and in the misp_uuid attribute,
+// and MISP tags become Tags. Attributes, objects and galaxy clusters with no
+// model equivalent are preserved in the event's attributes so Export can
+// reproduce them. Import stores timestamps in Unix seconds; Export reads
+// them with epoch.Default, so documents holding milliseconds export the
+// same.
+package misp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Attribute keys used to carry MISP data in model attributes.
+const (
+	AttrUUID           = "misp_uuid"
+	AttrType           = "misp_type"
+	AttrCategory       = "misp_category"
+	AttrComment        = "misp_comment"
+	AttrToIDs          = "misp_to_ids"
+	AttrThreatLevel    = "misp_threat_level_id"
+	AttrAnalysis       = "misp_analysis"
+	AttrDistribution   = "misp_distribution"
+	AttrPublished      = "misp_published"
+	AttrGalaxy         = "misp_galaxy"
+	AttrGalaxyType     = "misp_galaxy_type"
+	AttrGalaxyUUID     = "misp_galaxy_uuid"
+	AttrTagName        = "misp_tag_name"
+	AttrOtherAttribute = "misp_attributes"
+	AttrOtherObject    = "misp_objects"
+	AttrOtherGalaxy    = "misp_galaxies"
+)
+
+// SourceTypeOrg is the Source type given to the creator organisation.
+const SourceTypeOrg = "misp-org"
+
+// EventType is the Event type given to imported MISP events.
+const EventType = "misp-event"
+
+// Envelope is the {"Event": {...}} document served by the MISP API.
+type Envelope struct {
+	Event *Event `json:"Event"`
+}
+
+// Event is a MISP event.
+type Event struct {
+	UUID          string      `json:"uuid"`
+	Info          string      `json:"info"`
+	Date          string      `json:"date,omitempty"`
+	Timestamp     Timestamp   `json:"timestamp,omitempty"`
+	Published     bool        `json:"published"`
+	ThreatLevelID string      `json:"threat_level_id,omitempty"`
+	Analysis      string      `json:"analysis,omitempty"`
+	Distribution  string      `json:"distribution,omitempty"`
+	Orgc          *Org        `json:"Orgc,omitempty"`
+	Tag           []Tag       `json:"Tag,omitempty"`
+	Attribute     []Attribute `json:"Attribute,omitempty"`
+	Object        []Object    `json:"Object,omitempty"`
+	Galaxy        []Galaxy    `json:"Galaxy,omitempty"`
+}
+
+// Org is a MISP organisation.
+type Org struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// Tag is a MISP tag.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Attribute is a MISP attribute, standalone or inside an object.
+type Attribute struct {
+	UUID           string    `json:"uuid"`
+	Type           string    `json:"type"`
+	Category       string    `json:"category,omitempty"`
+	Value          string    `json:"value"`
+	Comment        string    `json:"comment,omitempty"`
+	ObjectRelation string    `json:"object_relation,omitempty"`
+	ToIDs          bool      `json:"to_ids"`
+	Timestamp      Timestamp `json:"timestamp,omitempty"`
+	Tag            []Tag     `json:"Tag,omitempty"`
+}
+
+// Object is a MISP object grouping related attributes.
+type Object struct {
+	UUID         string      `json:"uuid"`
+	Name         string      `json:"name"`
+	MetaCategory string      `json:"meta-category,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Timestamp    Timestamp   `json:"timestamp,omitempty"`
+	Attribute    []Attribute `json:"Attribute,omitempty"`
+}
+
+// Galaxy is a MISP galaxy with the clusters attached to an event.
+type Galaxy struct {
+	UUID          string          `json:"uuid,omitempty"`
+	Name          string          `json:"name"`
+	Type          string          `json:"type"`
+	GalaxyCluster []GalaxyCluster `json:"GalaxyCluster,omitempty"`
+}
+
+// GalaxyCluster is one cluster of a galaxy.
+type GalaxyCluster struct {
+	UUID        string         `json:"uuid"`
+	Type        string         `json:"type"`
+	Value       string         `json:"value"`
+	Description string         `json:"description,omitempty"`
+	TagName     string         `json:"tag_name,omitempty"`
+	Meta        map[string]any `json:"meta,omitempty"`
+}
+
+// Timestamp is a Unix time in seconds. MISP encodes it as a decimal string;
+// plain JSON numbers are accepted too.
+type Timestamp int64
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(t), 10))
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("misp: invalid timestamp %s", data)
+		}
+		*t = Timestamp(n)
+		return nil
+	}
+	if s == "" {
+		*t = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("misp: invalid timestamp %q", s)
+	}
+	*t = Timestamp(n)
+	return nil
+}
+
+// Decode reads a MISP event, with or without the {"Event": ...} envelope.
+func Decode(r io.Reader) (*Event, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	body, ok := raw["Event"]
+	if !ok {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Encode writes e wrapped in the {"Event": ...} envelope.
+func Encode(w io.Writer, e *Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Envelope{Event: e})
+}
@@ -0,0 +1,212 @@
+package misp
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func decodeFile(t *testing.T, name string) *Event {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		file      string
+		kinds     map[entity.Kind]int
+		relations int
+	}{
+		{"event.json", map[entity.Kind]int{entity.KindEvent: 1, entity.KindSource: 2, entity.KindWebsite: 3, entity.KindPerson: 1, entity.KindOrganization: 2}, 8},
+		{"bare.json", map[entity.Kind]int{entity.KindEvent: 1, entity.KindWebsite: 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			entities, relations, err := Import(decodeFile(t, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			kinds := map[entity.Kind]int{}
+			for _, e := range entities {
+				kinds[entity.KindOf(e)]++
+			}
+			if !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("kinds = %v, want %v", kinds, tt.kinds)
+			}
+			if len(relations) != tt.relations {
+				t.Errorf("%d relations, want %d", len(relations), tt.relations)
+			}
+			for _, r := range relations {
+				if err := arango.CheckRelation(r); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		file string
+		want error
+	}{
+		{"bad-date.json", nil},
+		{"bad-uuid.json", arango.ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		_, _, err := Import(decodeFile(t, tt.file))
+		if err == nil {
+			t.Errorf("%s: no error", tt.file)
+		} else if tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s: error %v, want %v", tt.file, err, tt.want)
+		}
+	}
+}
+
+// TestRoundTrip checks that exporting what a fixture imports to
+// reproduces the fixture, up to the order of its lists.
+func TestRoundTrip(t *testing.T) {
+	for _, file := range []string{"event.json", "bare.json"} {
+		t.Run(file, func(t *testing.T) {
+			want := decodeFile(t, file)
+			entities, relations, err := Import(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Export(entities[0].GetEvent(), entities[1:], relations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g, w := canonical(t, got), canonical(t, want); g != w {
+				t.Errorf("round trip:\n got %s\nwant %s", g, w)
+			}
+		})
+	}
+}
+
+// canonical returns e as JSON with its attributes, objects and galaxies
+// sorted.
+func canonical(t *testing.T, e *Event) string {
+	t.Helper()
+	c := *e
+	c.Attribute = append([]Attribute(nil), e.Attribute...)
+	c.Object = append([]Object(nil), e.Object...)
+	c.Galaxy = append([]Galaxy(nil), e.Galaxy...)
+	sort.Slice(c.Attribute, func(i, j int) bool { return c.Attribute[i].UUID < c.Attribute[j].UUID })
+	sort.Slice(c.Object, func(i, j int) bool { return c.Object[i].UUID < c.Object[j].UUID })
+	sort.Slice(c.Galaxy, func(i, j int) bool { return c.Galaxy[i].Name < c.Galaxy[j].Name })
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestExportDerivesUUIDs(t *testing.T) {
+	ev := &model.Event{Key: "e1", Title: "native"}
+	entities := []*model.Entity{
+		entity.MustNew(&model.Person{Key: "p1", Name: "Ada", Role: "analyst"}),
+		entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com"}),
+	}
+	relations := []*model.Relation{
+		{From: "persons/p1", To: "events/e1", Label: "participated_in"},
+		{From: "events/e1", To: "websites/w1", Label: "related_to"},
+	}
+	out, err := Export(ev, entities, relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Export(ev, entities, relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, again) {
+		t.Error("derived UUIDs are not stable")
+	}
+	uuids := []string{out.UUID}
+	for _, a := range out.Attribute {
+		uuids = append(uuids, a.UUID)
+	}
+	for _, o := range out.Object {
+		uuids = append(uuids, o.UUID)
+		for _, a := range o.Attribute {
+			uuids = append(uuids, a.UUID)
+		}
+	}
+	if len(uuids) != 5 {
+		t.Fatalf("exported %d UUIDs, want 5", len(uuids))
+	}
+	seen := map[string]bool{}
+	for _, u := range uuids {
+		if !uuidPattern.MatchString(u) {
+			t.Errorf("%q is not a UUIDv5", u)
+		}
+		if seen[u] {
+			t.Errorf("UUID %s exported twice", u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestPersonAliases(t *testing.T) {
+	ev := &model.Event{Key: "e1", Title: "native"}
+	p := &model.Person{Key: "p1", Name: "Ada", Aliases: []string{"Countess", "Enchantress"}}
+	out, err := Export(ev, []*model.Entity{entity.MustNew(p)}, []*model.Relation{
+		{From: "persons/p1", To: "events/e1", Label: "participated_in"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.UUID = "e1"
+	entities, _, err := Import(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != 2 {
+		t.Fatalf("imported %d entities, want 2", len(entities))
+	}
+	if got := entities[1].GetPerson().GetAliases(); !reflect.DeepEqual(got, p.Aliases) {
+		t.Errorf("aliases = %q, want %q", got, p.Aliases)
+	}
+}
+
+func TestExportMilliseconds(t *testing.T) {
+	export := func(scale int64) *Event {
+		ev := &model.Event{Key: "e1", Title: "native", HappenedAt: 1700000000 * scale, UpdatedAt: 1700003600 * scale}
+		p := &model.Person{Key: "p1", Name: "Ada", BirthDate: 490752000 * scale, UpdatedAt: 1700000000 * scale}
+		out, err := Export(ev, []*model.Entity{entity.MustNew(p)}, []*model.Relation{
+			{From: "persons/p1", To: "events/e1", Label: "participated_in"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	seconds, millis := export(1), export(1000)
+	if !reflect.DeepEqual(millis, seconds) {
+		t.Errorf("export from milliseconds = %+v, want %+v", millis, seconds)
+	}
+	if seconds.Date != "2023-11-14" || seconds.Timestamp != 1700003600 {
+		t.Errorf("date, timestamp = %s, %d", seconds.Date, seconds.Timestamp)
+	}
+}
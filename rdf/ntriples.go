@@ -0,0 +1,86 @@
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/graph"
+	"github.com/omnsight/omniscent-library/internal/errwriter"
+)
+
+// WriteNTriples writes g as N-Triples, one statement per line. Quoted
+// triples use the N-Triples-star << s p o >> syntax.
+func WriteNTriples(w io.Writer, g graph.Graph, opts ...Option) error {
+	triples, err := Triples(g, opts...)
+	if err != nil {
+		return err
+	}
+	ew := errwriter.New(w)
+	for _, t := range triples {
+		ew.Printf("%s .\n", ntriple(t))
+	}
+	return ew.Err()
+}
+
+func ntriple(t Triple) string {
+	return ntTerm(t.Subject) + " " + ntTerm(t.Predicate) + " " + ntTerm(t.Object)
+}
+
+func ntTerm(t Term) string {
+	switch t.Kind {
+	case IRI:
+		return "<" + escapeIRI(t.Value) + ">"
+	case Blank:
+		return "_:" + t.Value
+	case Quoted:
+		return "<< " + ntriple(*t.Triple) + " >>"
+	}
+	s := quote(t.Value)
+	if t.Datatype != "" && t.Datatype != xsdString {
+		s += "^^<" + escapeIRI(t.Datatype) + ">"
+	}
+	return s
+}
+
+// quote returns s as a double-quoted string literal.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// escapeIRI percent-encodes the characters not allowed in an IRIREF, such
+// as spaces. UCHAR escapes would only spell the same invalid IRI.
+func escapeIRI(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
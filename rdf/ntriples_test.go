@@ -0,0 +1,104 @@
+package rdf
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+)
+
+func TestEscapeIRI(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"http://example.org/persons/p1", "http://example.org/persons/p1"},
+		{"http://example.org/reported by", "http://example.org/reported%20by"},
+		{"http://example.org/a<b>", "http://example.org/a%3Cb%3E"},
+		{"http://example.org/{x}|^`\\\"", "http://example.org/%7Bx%7D%7C%5E%60%5C%22"},
+		{"http://example.org/tab\there", "http://example.org/tab%09here"},
+		{"http://example.org/café", "http://example.org/café"},
+		{"http://example.org/already%20encoded", "http://example.org/already%20encoded"},
+	}
+	for _, tt := range tests {
+		if got := escapeIRI(tt.in); got != tt.want {
+			t.Errorf("escapeIRI(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteNTriplesSpaceInLabel(t *testing.T) {
+	g := graph.Graph{
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com"}),
+			entity.MustNew(&model.Source{Key: "s1", Name: "feed"}),
+		},
+		Relations: []*model.Relation{{From: "websites/w1", To: "sources/s1", Label: "reported by"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteNTriples(&buf, g, WithNamespace("http://example.org/")); err != nil {
+		t.Fatal(err)
+	}
+	want := "<http://example.org/websites/w1> <http://example.org/rel/reported%20by> <http://example.org/sources/s1> .\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("output lacks %q:\n%s", want, buf.String())
+	}
+}
+
+func TestRelationNamespace(t *testing.T) {
+	g := graph.Graph{
+		Entities: []*model.Entity{
+			entity.MustNew(&model.Person{Key: "p1", Name: "Ada"}),
+			entity.MustNew(&model.Organization{Key: "o1", Name: "Acme"}),
+		},
+		Relations: []*model.Relation{{From: "persons/p1", To: "organizations/o1", Label: "name"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteTurtle(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"@prefix omnrel: <urn:omnsight:rel/> .\n",
+		"<urn:omnsight:persons/p1>\n    a omn:Person ;\n    omn:name \"Ada\" .\n",
+		"<urn:omnsight:persons/p1>\n    omnrel:name <urn:omnsight:organizations/o1> .\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestFormatDouble(t *testing.T) {
+	tests := []struct {
+		f       float64
+		bitSize int
+		want    string
+	}{
+		{1.5, 64, "1.5"},
+		{51.5074, 32, "51.5074"},
+		{1e21, 64, "1e+21"},
+		{math.Inf(1), 32, "INF"},
+		{math.Inf(-1), 64, "-INF"},
+		{math.NaN(), 64, "NaN"},
+	}
+	for _, tt := range tests {
+		if got := formatDouble(tt.f, tt.bitSize); got != tt.want {
+			t.Errorf("formatDouble(%v, %d) = %q, want %q", tt.f, tt.bitSize, got, tt.want)
+		}
+	}
+
+	g := graph.Graph{Entities: []*model.Entity{entity.MustNew(&model.Event{
+		Key:      "e1",
+		Location: &model.LocationData{Latitude: float32(math.Inf(1)), Longitude: float32(math.Inf(-1))},
+	})}}
+	var buf bytes.Buffer
+	if err := WriteNTriples(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"INF"^^<` + XSD + `double>`, `"-INF"^^<` + XSD + `double>`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %s:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Package rdf serializes entities and relations as RDF in Turtle or
+// N-Triples.
+//
+// Every document becomes a resource named by its handle under a
+// configurable namespace (<ns>persons/123) and typed with the namespace
+// class of its kind (<ns>Person). Populated fields become <ns><field>
+// statements: strings and enums as plain literals, timestamps as
+// xsd:dateTime (read with epoch.Default), other numbers as
+// xsd:integer or xsd:double, repeated fields as one statement per value,
+// a location as a blank node and attributes as an rdf:JSON literal. ACL
+// fields and revisions are never written.
+//
+// A relation is asserted as <from> <ns>rel/<label> <to>, keeping labels
+// apart from the field properties and classes. Its own fields, such as
+// confidence, are attached either to an rdf:Statement reifying the triple
+// (the default) or to the quoted triple using RDF-star.
+package rdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/graph"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Well-known namespaces.
+const (
+	RDF = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	XSD = "http://www.w3.org/2001/XMLSchema#"
+)
+
+// DefaultNamespace is used for resources, classes and properties unless
+// WithNamespace is given.
+const DefaultNamespace = "urn:omnsight:"
+
+// DefaultPrefix is the Turtle prefix of the namespace.
+const DefaultPrefix = "omn"
+
+// RelationPath is appended to the namespace to form the namespace of
+// relation labels. Turtle binds it to the namespace prefix followed by
+// "rel", as in omnrel:member_of.
+const RelationPath = "rel/"
+
+// Vocabulary terms.
+const (
+	rdfType      = RDF + "type"
+	rdfStatement = RDF + "Statement"
+	rdfSubject   = RDF + "subject"
+	rdfPredicate = RDF + "predicate"
+	rdfObject    = RDF + "object"
+	rdfJSON      = RDF + "JSON"
+	xsdString    = XSD + "string"
+	xsdInteger   = XSD + "integer"
+	xsdDouble    = XSD + "double"
+	xsdBoolean   = XSD + "boolean"
+	xsdDateTime  = XSD + "dateTime"
+)
+
+// RelationStyle selects how relation metadata is attached.
+type RelationStyle int
+
+const (
+	// Reification describes each relation with an rdf:Statement named by
+	// the relation's handle.
+	Reification RelationStyle = iota
+	// RDFStar annotates the quoted relation triple directly.
+	RDFStar
+)
+
+// TermKind is the kind of an RDF term.
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	Blank
+	Literal
+	Quoted
+)
+
+// Term is an RDF term. Value holds the IRI, the blank node label or the
+// lexical form of a literal; Triple is set for quoted triples.
+type Term struct {
+	Kind     TermKind
+	Value    string
+	Datatype string
+	Triple   *Triple
+}
+
+// Triple is one RDF statement.
+type Triple struct {
+	Subject, Predicate, Object Term
+}
+
+func iri(s string) Term { return Term{Kind: IRI, Value: s} }
+
+func literal(value, datatype string) Term {
+	return Term{Kind: Literal, Value: value, Datatype: datatype}
+}
+
+// Option configures the serialization.
+type Option func(*config)
+
+type config struct {
+	namespace string
+	prefix    string
+	style     RelationStyle
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{namespace: DefaultNamespace, prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithNamespace sets the namespace IRI of resources and vocabulary, for
+// example "https://kg.example.org/".
+func WithNamespace(ns string) Option {
+	return func(c *config) { c.namespace = ns }
+}
+
+// WithPrefix sets the Turtle prefix bound to the namespace.
+func WithPrefix(prefix string) Option {
+	return func(c *config) { c.prefix = prefix }
+}
+
+// WithRelationStyle selects reification or RDF-star for relation metadata.
+func WithRelationStyle(s RelationStyle) Option {
+	return func(c *config) { c.style = s }
+}
+
+// skipped fields are encoded in the resource name or relation triple, or
+// are never published.
+var skipped = map[protoreflect.Name]bool{
+	"id": true, "key": true, "rev": true, "from": true, "to": true, "label": true,
+	"owner": true, "read": true, "write": true,
+}
+
+// Triples converts g to RDF statements.
+func Triples(g graph.Graph, opts ...Option) ([]Triple, error) {
+	b := &builder{config: newConfig(opts)}
+	for _, e := range g.Entities {
+		doc := entity.Unwrap(e)
+		if doc == nil {
+			continue
+		}
+		h, err := arango.HandleOf(doc)
+		if err != nil {
+			return nil, err
+		}
+		subject := b.resource(h.String())
+		b.add(subject, iri(rdfType), iri(b.namespace+string(doc.ProtoReflect().Descriptor().Name())))
+		b.fields(subject, doc.ProtoReflect())
+	}
+	for _, r := range g.Relations {
+		if err := b.relation(r); err != nil {
+			return nil, err
+		}
+	}
+	return b.triples, nil
+}
+
+type builder struct {
+	*config
+	triples []Triple
+	blanks  int
+}
+
+func (b *builder) add(s, p, o Term) {
+	b.triples = append(b.triples, Triple{s, p, o})
+}
+
+func (b *builder) resource(handle string) Term {
+	return iri(b.namespace + handle)
+}
+
+func (b *builder) blank() Term {
+	t := Term{Kind: Blank, Value: "b" + strconv.Itoa(b.blanks)}
+	b.blanks++
+	return t
+}
+
+func (b *builder) relation(r *model.Relation) error {
+	from, err := arango.ParseHandle(r.GetFrom())
+	if err != nil {
+		return fmt.Errorf("relation %s: from: %w", r.GetKey(), err)
+	}
+	to, err := arango.ParseHandle(r.GetTo())
+	if err != nil {
+		return fmt.Errorf("relation %s: to: %w", r.GetKey(), err)
+	}
+	if r.GetLabel() == "" {
+		return fmt.Errorf("relation %s: %w", r.GetKey(), arango.ErrUnknownLabel)
+	}
+	stmt := Triple{b.resource(from.String()), iri(b.namespace + RelationPath + r.GetLabel()), b.resource(to.String())}
+	b.triples = append(b.triples, stmt)
+
+	var subject Term
+	switch b.style {
+	case RDFStar:
+		subject = Term{Kind: Quoted, Triple: &stmt}
+		b.fields(subject, r.ProtoReflect())
+	default:
+		if h, err := arango.HandleOf(r); err == nil {
+			subject = b.resource(h.String())
+		} else {
+			subject = b.blank()
+		}
+		b.add(subject, iri(rdfType), iri(rdfStatement))
+		b.add(subject, iri(rdfSubject), stmt.Subject)
+		b.add(subject, iri(rdfPredicate), stmt.Predicate)
+		b.add(subject, iri(rdfObject), stmt.Object)
+		b.fields(subject, r.ProtoReflect())
+	}
+	return nil
+}
+
+// fields adds a statement for every populated field of m, in field order.
+// Nested messages are described after the fields of m so statements stay
+// grouped by subject.
+func (b *builder) fields(subject Term, m protoreflect.Message) {
+	type nested struct {
+		node Term
+		m    protoreflect.Message
+	}
+	var later []nested
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if skipped[fd.Name()] || !m.Has(fd) {
+			continue
+		}
+		p := iri(b.namespace + string(fd.Name()))
+		v := m.Get(fd)
+		switch {
+		case fd.IsList():
+			for j := 0; j < v.List().Len(); j++ {
+				b.add(subject, p, scalar(fd, v.List().Get(j)))
+			}
+		case fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Struct":
+			data, _ := json.Marshal(v.Message().Interface().(*structpb.Struct).AsMap())
+			b.add(subject, p, literal(string(data), rdfJSON))
+		case fd.Message() != nil:
+			node := b.blank()
+			b.add(subject, p, node)
+			later = append(later, nested{node, v.Message()})
+		default:
+			b.add(subject, p, scalar(fd, v))
+		}
+	}
+	for _, n := range later {
+		b.add(n.node, iri(rdfType), iri(b.namespace+string(n.m.Descriptor().Name())))
+		b.fields(n.node, n.m)
+	}
+}
+
+func scalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) Term {
+	if epoch.IsTimestamp(fd) {
+		return literal(epoch.Default.Time(v.Int()).Format(time.RFC3339Nano), xsdDateTime)
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return literal(v.String(), xsdString)
+	case protoreflect.BoolKind:
+		return literal(strconv.FormatBool(v.Bool()), xsdBoolean)
+	case protoreflect.FloatKind:
+		return literal(formatDouble(v.Float(), 32), xsdDouble)
+	case protoreflect.DoubleKind:
+		return literal(formatDouble(v.Float(), 64), xsdDouble)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return literal(string(ev.Name()), xsdString)
+		}
+		return literal(strconv.Itoa(int(v.Enum())), xsdInteger)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return literal(strconv.FormatUint(v.Uint(), 10), xsdInteger)
+	}
+	return literal(strconv.FormatInt(v.Int(), 10), xsdInteger)
+}
+
+// formatDouble returns the xsd:double lexical form of f, which spells the
+// special values INF, -INF and NaN.
+func formatDouble(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
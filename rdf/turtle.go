@@ -0,0 +1,115 @@
+package rdf
+
+import (
+	"io"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/graph"
+	"github.com/omnsight/omniscent-library/internal/errwriter"
+)
+
+// WriteTurtle writes g as Turtle. Consecutive statements about the same
+// subject are grouped, and IRIs in the rdf, xsd and configured namespaces
+// are abbreviated where the local name allows it. Quoted triples use the
+// Turtle-star << s p o >> syntax.
+func WriteTurtle(w io.Writer, g graph.Graph, opts ...Option) error {
+	c := newConfig(opts)
+	triples, err := Triples(g, opts...)
+	if err != nil {
+		return err
+	}
+	tw := &turtleWriter{
+		Writer: errwriter.New(w),
+		prefixes: [][2]string{
+			{"rdf", RDF},
+			{"xsd", XSD},
+			{c.prefix, c.namespace},
+			{c.prefix + "rel", c.namespace + RelationPath},
+		},
+	}
+	for _, p := range tw.prefixes {
+		tw.Printf("@prefix %s: <%s> .\n", p[0], escapeIRI(p[1]))
+	}
+	for i, t := range triples {
+		if i > 0 && sameTerm(t.Subject, triples[i-1].Subject) {
+			tw.Printf(" ;\n    %s %s", tw.predicate(t.Predicate), tw.term(t.Object))
+			continue
+		}
+		if i > 0 {
+			tw.Printf(" .\n")
+		}
+		tw.Printf("\n%s\n    %s %s", tw.term(t.Subject), tw.predicate(t.Predicate), tw.term(t.Object))
+	}
+	if len(triples) > 0 {
+		tw.Printf(" .\n")
+	}
+	return tw.Err()
+}
+
+type turtleWriter struct {
+	*errwriter.Writer
+	prefixes [][2]string
+}
+
+func (tw *turtleWriter) predicate(t Term) string {
+	if t.Kind == IRI && t.Value == rdfType {
+		return "a"
+	}
+	return tw.term(t)
+}
+
+func (tw *turtleWriter) term(t Term) string {
+	switch t.Kind {
+	case IRI:
+		return tw.name(t.Value)
+	case Quoted:
+		return "<< " + tw.term(t.Triple.Subject) + " " + tw.predicate(t.Triple.Predicate) + " " + tw.term(t.Triple.Object) + " >>"
+	case Literal:
+		switch t.Datatype {
+		case "", xsdString:
+			return quote(t.Value)
+		case xsdInteger, xsdBoolean:
+			return t.Value
+		}
+		return quote(t.Value) + "^^" + tw.name(t.Datatype)
+	}
+	return ntTerm(t)
+}
+
+// name abbreviates s to a prefixed name when possible.
+func (tw *turtleWriter) name(s string) string {
+	for _, p := range tw.prefixes {
+		if local, ok := strings.CutPrefix(s, p[1]); ok && isLocalName(local) {
+			return p[0] + ":" + local
+		}
+	}
+	return "<" + escapeIRI(s) + ">"
+}
+
+// isLocalName reports whether s can be written unescaped after a prefix.
+func isLocalName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func sameTerm(a, b Term) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if a.Kind == Quoted {
+		return sameTerm(a.Triple.Subject, b.Triple.Subject) &&
+			sameTerm(a.Triple.Predicate, b.Triple.Predicate) &&
+			sameTerm(a.Triple.Object, b.Triple.Object)
+	}
+	return a.Value == b.Value && a.Datatype == b.Datatype
+}
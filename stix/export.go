@@ -0,0 +1,394 @@
+package stix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Export converts entities and the relations between them into a bundle.
+// Relations touching a Source become external references of the other
+// endpoint; all other relations become relationship objects. Every relation
+// endpoint must be among entities. Websites sharing a URL are one
+// observable in STIX, so they are exported as a single object carrying the
+// fields of the first of them.
+func Export(entities []*model.Entity, relations []*model.Relation) (*Bundle, error) {
+	x := &exporter{
+		ids:       map[string]string{},
+		sources:   map[string]*model.Source{},
+		events:    map[string]*model.Event{},
+		neighbors: map[string][]string{},
+		refs:      map[string][]string{},
+		extRefs:   map[string][]ExternalReference{},
+	}
+	for _, e := range entities {
+		if err := x.index(e); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range relations {
+		from, to := r.GetFrom(), r.GetTo()
+		if x.sources[from] == nil && x.sources[to] == nil {
+			x.neighbors[from] = append(x.neighbors[from], to)
+			x.neighbors[to] = append(x.neighbors[to], from)
+		}
+	}
+	for key, v := range x.events {
+		x.ids[key] = newID(x.eventType(key, v), key)
+	}
+	for _, r := range relations {
+		if err := x.link(r); err != nil {
+			return nil, err
+		}
+	}
+	var objects []*Object
+	emitted := map[string]bool{}
+	for _, e := range entities {
+		if obj := x.convert(e); obj != nil && !emitted[obj.ID] {
+			emitted[obj.ID] = true
+			objects = append(objects, obj)
+		}
+	}
+	objects = append(objects, x.relationships...)
+	return newBundle(objects), nil
+}
+
+// exporter tracks the STIX identifiers assigned to document handles.
+type exporter struct {
+	ids           map[string]string // handle -> STIX id
+	sources       map[string]*model.Source
+	events        map[string]*model.Event
+	neighbors     map[string][]string // handle -> related non-source handles
+	refs          map[string][]string
+	extRefs       map[string][]ExternalReference
+	relationships []*Object
+}
+
+func (x *exporter) index(e *model.Entity) error {
+	h, err := arango.HandleOf(e)
+	if err != nil {
+		return err
+	}
+	key := h.String()
+	switch v := entity.Unwrap(e).(type) {
+	case *model.Source:
+		x.sources[key] = v
+		return nil
+	case *model.Person:
+		x.ids[key] = newID(TypeIdentity, key)
+	case *model.Organization:
+		x.ids[key] = newID(organizationType(v), key)
+	case *model.Website:
+		typ, value := websiteObservable(v.GetUrl())
+		x.ids[key] = observableID(typ, value)
+	case *model.Event:
+		// The object type depends on what the event is related to, so
+		// its id is assigned once all relations are known.
+		x.events[key] = v
+	default:
+		return fmt.Errorf("stix: entity %s has no message set", key)
+	}
+	return nil
+}
+
+func (x *exporter) link(r *model.Relation) error {
+	from, to := r.GetFrom(), r.GetTo()
+	if s := x.sources[to]; s != nil {
+		return x.cite(from, s)
+	}
+	if s := x.sources[from]; s != nil {
+		return x.cite(to, s)
+	}
+	fromID, ok := x.ids[from]
+	if !ok {
+		return fmt.Errorf("stix: relation endpoint %q not exported", from)
+	}
+	toID, ok := x.ids[to]
+	if !ok {
+		return fmt.Errorf("stix: relation endpoint %q not exported", to)
+	}
+	x.refs[from] = append(x.refs[from], toID)
+	x.refs[to] = append(x.refs[to], fromID)
+
+	h, err := arango.HandleOf(r)
+	if err != nil {
+		return err
+	}
+	obj := &Object{
+		Type:             TypeRelationship,
+		SpecVersion:      SpecVersion,
+		ID:               newID(TypeRelationship, h.String()),
+		Created:          formatTime(r.GetCreatedAt()),
+		Modified:         formatTime(later(r.GetCreatedAt(), r.GetUpdatedAt())),
+		RelationshipType: strings.ReplaceAll(r.GetLabel(), "_", "-"),
+		SourceRef:        fromID,
+		TargetRef:        toID,
+		XKey:             h.Key,
+		XTitle:           r.GetName(),
+		XAttributes:      attributes(r.GetAttributes()),
+	}
+	if c := r.GetConfidence(); c != 0 {
+		obj.Confidence = &c
+	}
+	x.relationships = append(x.relationships, obj)
+	return nil
+}
+
+func (x *exporter) cite(handle string, s *model.Source) error {
+	if _, ok := x.ids[handle]; !ok {
+		return fmt.Errorf("stix: relation endpoint %q not exported", handle)
+	}
+	x.extRefs[handle] = append(x.extRefs[handle], ExternalReference{
+		SourceName:  sourceName(s),
+		Description: s.GetDescription(),
+		URL:         s.GetUrl(),
+		ExternalID:  s.GetKey(),
+	})
+	return nil
+}
+
+// sourceName returns the source_name of references citing s: its name or
+// title, or else the host of its URL or its key, as STIX requires one.
+func sourceName(s *model.Source) string {
+	if s.GetName() != "" {
+		return s.GetName()
+	}
+	if s.GetTitle() != "" {
+		return s.GetTitle()
+	}
+	if u, err := url.Parse(s.GetUrl()); err == nil && u.Host != "" {
+		return u.Host
+	}
+	h, _ := arango.HandleOf(s)
+	return h.Key
+}
+
+func (x *exporter) convert(e *model.Entity) *Object {
+	h, _ := arango.HandleOf(e)
+	handle := h.String()
+	id, ok := x.ids[handle]
+	if !ok {
+		return nil
+	}
+	obj := &Object{
+		SpecVersion:        SpecVersion,
+		ID:                 id,
+		ExternalReferences: x.extRefs[handle],
+		XKey:               h.Key,
+		XAttributes:        attributes(entity.Attributes(e)),
+	}
+	obj.Type, _ = splitID(id)
+	switch v := entity.Unwrap(e).(type) {
+	case *model.Person:
+		obj.Created = formatTime(v.GetUpdatedAt())
+		obj.Modified = obj.Created
+		obj.Name = v.GetName()
+		obj.IdentityClass = IdentityIndividual
+		obj.Labels = v.GetTags()
+		obj.Aliases = v.GetAliases()
+		obj.XNationality = v.GetNationality()
+		if v.GetRole() != "" {
+			obj.Roles = []string{v.GetRole()}
+		}
+		if v.GetBirthDate() != 0 {
+			obj.XBirthDate = formatTime(v.GetBirthDate())
+		}
+	case *model.Organization:
+		obj.Created = formatTime(v.GetDiscoveredAt())
+		obj.Modified = formatTime(later(v.GetDiscoveredAt(), v.GetLastVisited()))
+		obj.Name = v.GetName()
+		obj.Labels = v.GetTags()
+		obj.XType = v.GetType()
+		if obj.Type == TypeIdentity {
+			obj.IdentityClass = IdentityOrganization
+		} else {
+			obj.FirstSeen = obj.Created
+			obj.LastSeen = obj.Modified
+		}
+		if v.GetFoundedAt() != 0 {
+			obj.XFoundedAt = formatTime(v.GetFoundedAt())
+		}
+	case *model.Website:
+		obj.Value = v.GetUrl()
+		if obj.Type == TypeDomainName {
+			_, obj.Value = websiteObservable(v.GetUrl())
+		}
+		obj.XTitle = v.GetTitle()
+		obj.XTags = v.GetTags()
+		obj.XDescription = v.GetDescription()
+	case *model.Event:
+		at := formatTime(v.GetHappenedAt())
+		obj.Created = at
+		obj.Modified = formatTime(later(v.GetHappenedAt(), v.GetUpdatedAt()))
+		obj.Name = v.GetTitle()
+		obj.Description = v.GetDescription()
+		obj.Labels = v.GetTags()
+		obj.XType = v.GetType()
+		obj.XLocation = location(v.GetLocation())
+		refs := unique(x.refs[handle])
+		switch obj.Type {
+		case TypeReport:
+			obj.Published = at
+			obj.ObjectRefs = refs
+		case TypeSighting:
+			obj.FirstSeen, obj.LastSeen = at, at
+			obj.SightingOfRef = firstDomainObject(refs)
+		case TypeObservedData:
+			obj.FirstObserved, obj.LastObserved = at, at
+			obj.NumberObserved = 1
+			obj.ObjectRefs = observables(refs)
+		case TypeIncident:
+			// The name is required; Import maps a name equal to the
+			// key back to an empty title.
+			if obj.Name == "" {
+				obj.Name = h.Key
+			}
+		}
+	}
+	return obj
+}
+
+func newBundle(objects []*Object) *Bundle {
+	ids := make([]string, len(objects))
+	for i, o := range objects {
+		ids[i] = o.ID
+	}
+	sort.Strings(ids)
+	return &Bundle{
+		Type:    TypeBundle,
+		ID:      newID(TypeBundle, strings.Join(ids, ",")),
+		Objects: objects,
+	}
+}
+
+// organizationType maps an organization to identity or threat-actor.
+func organizationType(o *model.Organization) string {
+	switch strings.ToLower(o.GetType()) {
+	case "threat-actor", "threat_actor", "threat actor":
+		return TypeThreatActor
+	}
+	return TypeIdentity
+}
+
+// eventType maps the event at handle to the STIX object representing it.
+// A sighting needs a domain object it is a sighting of, observed-data an
+// observable and a report any object to refer to; an event lacking them
+// falls back to observed-data and then to an incident, which needs none.
+func (x *exporter) eventType(handle string, e *model.Event) string {
+	var observed, domain bool
+	for _, n := range x.neighbors[handle] {
+		if id, ok := x.ids[n]; ok && isObservable(id) {
+			observed = true
+		} else if ok || x.events[n] != nil {
+			domain = true
+		}
+	}
+	switch strings.ToLower(e.GetType()) {
+	case TypeReport:
+		if observed || domain {
+			return TypeReport
+		}
+		return TypeIncident
+	case TypeSighting:
+		if domain {
+			return TypeSighting
+		}
+	}
+	if observed {
+		return TypeObservedData
+	}
+	return TypeIncident
+}
+
+// websiteObservable returns url for absolute URLs and domain-name with the
+// bare host otherwise.
+func websiteObservable(raw string) (typ, value string) {
+	if u, err := url.Parse(raw); err == nil && u.IsAbs() {
+		return TypeURL, raw
+	}
+	host, _, _ := strings.Cut(raw, "/")
+	return TypeDomainName, strings.ToLower(host)
+}
+
+func isObservable(id string) bool {
+	typ, _ := splitID(id)
+	return typ == TypeURL || typ == TypeDomainName
+}
+
+func observables(refs []string) []string {
+	var out []string
+	for _, r := range refs {
+		if isObservable(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// unique returns ids without repetitions, in order. Websites sharing a URL
+// contribute the same observable id.
+func unique(ids []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func firstDomainObject(refs []string) string {
+	for _, r := range refs {
+		if !isObservable(r) {
+			return r
+		}
+	}
+	return ""
+}
+
+func location(l *model.LocationData) *Location {
+	if l == nil {
+		return nil
+	}
+	return &Location{
+		Latitude:              l.GetLatitude(),
+		Longitude:             l.GetLongitude(),
+		CountryCode:           l.GetCountryCode(),
+		AdministrativeArea:    l.GetAdministrativeArea(),
+		SubAdministrativeArea: l.GetSubAdministrativeArea(),
+		Locality:              l.GetLocality(),
+		SubLocality:           l.GetSubLocality(),
+		Address:               l.GetAddress(),
+		Postcode:              geo.PostalCode(l),
+	}
+}
+
+func attributes(s *structpb.Struct) map[string]any {
+	if len(s.GetFields()) == 0 {
+		return nil
+	}
+	return s.AsMap()
+}
+
+// jsonString encodes s as a JSON string without HTML escaping, matching
+// the canonical form used for observable identifiers.
+func jsonString(s string) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,289 @@
+package stix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/edge"
+	"github.com/omnsight/omniscent-library/entity"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/geo"
+	"github.com/omnsight/omniscent-library/internal/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Import converts a bundle into entities and relations. Document keys are
+// taken from x_omnsight_key when present and from the UUID of the STIX
+// identifier otherwise. External references become Source entities linked
+// with edge.ReportedBy relations, and object references not already covered
+// by a relationship become edge.RelatedTo relations. Objects of other types,
+// and repeated objects, are ignored.
+func Import(b *Bundle) ([]*model.Entity, []*model.Relation, error) {
+	if b.Type != TypeBundle {
+		return nil, nil, fmt.Errorf("stix: expected bundle, got %q", b.Type)
+	}
+	im := &importer{
+		handles: map[string]string{},
+		sources: map[string]bool{},
+		linked:  map[[2]string]bool{},
+	}
+	for _, obj := range b.Objects {
+		if err := im.entity(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, obj := range b.Objects {
+		if obj.Type != TypeRelationship {
+			continue
+		}
+		if err := im.relationship(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, obj := range b.Objects {
+		im.references(obj)
+	}
+	return im.entities, im.relations, nil
+}
+
+type importer struct {
+	handles   map[string]string // STIX id -> handle
+	sources   map[string]bool   // handles of imported sources
+	linked    map[[2]string]bool
+	entities  []*model.Entity
+	relations []*model.Relation
+}
+
+func (im *importer) entity(obj *Object) error {
+	if _, dup := im.handles[obj.ID]; dup {
+		return nil
+	}
+	key := objectKey(obj)
+	attrs, err := newStruct(obj.XAttributes)
+	if err != nil {
+		return fmt.Errorf("stix: %s: %w", obj.ID, err)
+	}
+	var msg entity.Tagged
+	switch obj.Type {
+	case TypeIdentity:
+		if obj.IdentityClass == IdentityIndividual {
+			p := &model.Person{
+				Key:         key,
+				Name:        obj.Name,
+				Nationality: obj.XNationality,
+				BirthDate:   parseTime(obj.XBirthDate),
+				UpdatedAt:   parseTime(obj.Modified),
+				Tags:        obj.Labels,
+				Aliases:     obj.Aliases,
+				Attributes:  attrs,
+			}
+			if len(obj.Roles) > 0 {
+				p.Role = obj.Roles[0]
+			}
+			msg = p
+			break
+		}
+		typ := obj.XType
+		if typ == "" && obj.IdentityClass != IdentityOrganization {
+			typ = obj.IdentityClass
+		}
+		msg = im.organization(obj, key, typ, attrs)
+	case TypeThreatActor:
+		typ := obj.XType
+		if typ == "" {
+			typ = TypeThreatActor
+		}
+		msg = im.organization(obj, key, typ, attrs)
+	case TypeURL, TypeDomainName:
+		msg = &model.Website{
+			Key:         key,
+			Url:         obj.Value,
+			Title:       obj.XTitle,
+			Description: firstNonEmpty(obj.XDescription, obj.Description),
+			Tags:        obj.XTags,
+			Attributes:  attrs,
+		}
+	case TypeObservedData, TypeSighting, TypeReport, TypeIncident:
+		typ := obj.XType
+		if typ == "" {
+			typ = obj.Type
+		}
+		title := obj.Name
+		if obj.Type == TypeIncident && title == key {
+			title = ""
+		}
+		msg = &model.Event{
+			Key:         key,
+			Type:        typ,
+			Location:    locationData(obj.XLocation),
+			Title:       title,
+			Description: obj.Description,
+			HappenedAt:  parseTime(firstNonEmpty(obj.FirstObserved, obj.FirstSeen, obj.Published, obj.Created)),
+			UpdatedAt:   modifiedAfter(obj.Modified, obj.Created),
+			Tags:        obj.Labels,
+			Attributes:  attrs,
+		}
+	default:
+		return nil
+	}
+	h, err := arango.HandleOf(msg)
+	if err != nil {
+		return fmt.Errorf("stix: %s: %w", obj.ID, err)
+	}
+	im.handles[obj.ID] = h.String()
+	im.entities = append(im.entities, entity.MustNew(msg))
+	for _, ref := range obj.ExternalReferences {
+		im.source(h.String(), ref)
+	}
+	return nil
+}
+
+func (im *importer) organization(obj *Object, key, typ string, attrs *structpb.Struct) *model.Organization {
+	first := firstNonEmpty(obj.FirstSeen, obj.Created)
+	return &model.Organization{
+		Key:          key,
+		Type:         typ,
+		Name:         obj.Name,
+		FoundedAt:    parseTime(obj.XFoundedAt),
+		DiscoveredAt: parseTime(first),
+		LastVisited:  modifiedAfter(firstNonEmpty(obj.LastSeen, obj.Modified), first),
+		Tags:         obj.Labels,
+		Attributes:   attrs,
+	}
+}
+
+// source records ref as a Source reported for the document at handle.
+func (im *importer) source(handle string, ref ExternalReference) {
+	key := ref.ExternalID
+	if arango.ValidateKey(key) != nil {
+		key = uuid.V5(namespace, ref.SourceName+"\x00"+ref.URL)
+	}
+	h := arango.Handle{Collection: arango.SourceCollection, Key: key}
+	if !im.sources[h.String()] {
+		im.sources[h.String()] = true
+		im.entities = append(im.entities, entity.MustNew(&model.Source{
+			Key:         key,
+			Url:         ref.URL,
+			Name:        ref.SourceName,
+			Description: ref.Description,
+		}))
+	}
+	im.relations = append(im.relations, &model.Relation{
+		From:  handle,
+		To:    h.String(),
+		Label: edge.ReportedBy,
+	})
+}
+
+func (im *importer) relationship(obj *Object) error {
+	from, ok := im.handles[obj.SourceRef]
+	if !ok {
+		return fmt.Errorf("stix: %s: unknown source_ref %q", obj.ID, obj.SourceRef)
+	}
+	to, ok := im.handles[obj.TargetRef]
+	if !ok {
+		return fmt.Errorf("stix: %s: unknown target_ref %q", obj.ID, obj.TargetRef)
+	}
+	attrs, err := newStruct(obj.XAttributes)
+	if err != nil {
+		return fmt.Errorf("stix: %s: %w", obj.ID, err)
+	}
+	r := &model.Relation{
+		Key:        objectKey(obj),
+		From:       from,
+		To:         to,
+		Name:       obj.XTitle,
+		Label:      strings.ReplaceAll(obj.RelationshipType, "-", "_"),
+		CreatedAt:  parseTime(obj.Created),
+		UpdatedAt:  modifiedAfter(obj.Modified, obj.Created),
+		Attributes: attrs,
+	}
+	if obj.Confidence != nil {
+		r.Confidence = *obj.Confidence
+	}
+	im.linked[[2]string{from, to}] = true
+	im.linked[[2]string{to, from}] = true
+	im.relations = append(im.relations, r)
+	return nil
+}
+
+// references turns object_refs and sighting_of_ref not already backed by a
+// relationship into related_to relations.
+func (im *importer) references(obj *Object) {
+	from, ok := im.handles[obj.ID]
+	if !ok {
+		return
+	}
+	refs := obj.ObjectRefs
+	if obj.SightingOfRef != "" {
+		refs = append([]string{obj.SightingOfRef}, refs...)
+	}
+	for _, ref := range refs {
+		to, ok := im.handles[ref]
+		if !ok || im.linked[[2]string{from, to}] {
+			continue
+		}
+		im.linked[[2]string{from, to}] = true
+		im.linked[[2]string{to, from}] = true
+		im.relations = append(im.relations, &model.Relation{
+			From:  from,
+			To:    to,
+			Label: edge.RelatedTo,
+		})
+	}
+}
+
+// objectKey returns the document key for obj.
+func objectKey(obj *Object) string {
+	if obj.XKey != "" {
+		return obj.XKey
+	}
+	_, uuid := splitID(obj.ID)
+	return uuid
+}
+
+// newStruct converts a custom attributes property back to a Struct.
+func newStruct(attrs map[string]any) (*structpb.Struct, error) {
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+	return structpb.NewStruct(attrs)
+}
+
+func locationData(l *Location) *model.LocationData {
+	if l == nil {
+		return nil
+	}
+	loc := &model.LocationData{
+		Latitude:              l.Latitude,
+		Longitude:             l.Longitude,
+		CountryCode:           l.CountryCode,
+		AdministrativeArea:    l.AdministrativeArea,
+		SubAdministrativeArea: l.SubAdministrativeArea,
+		Locality:              l.Locality,
+		SubLocality:           l.SubLocality,
+		Address:               l.Address,
+	}
+	geo.SetPostalCode(loc, l.Postcode)
+	return loc
+}
+
+// modifiedAfter returns the time of modified if it is later than created.
+// Export fills modified with the creation time when nothing changed since,
+// so an equal value maps back to an unset field.
+func modifiedAfter(modified, created string) int64 {
+	if m := parseTime(modified); m > parseTime(created) {
+		return m
+	}
+	return 0
+}
+
+func firstNonEmpty(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
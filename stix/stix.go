@@ -0,0 +1,189 @@
+// Package stix converts the OSINT model to and from STIX 2.1 bundles.
+//
+// The mapping is:
+//
+//	Person        identity (identity_class "individual")
+//	Organization  identity (identity_class "organization"), or threat-actor
+//	              when its type is "threat-actor"
+//	Website       url, or domain-name when the URL has no scheme
+//	Event         observed-data, sighting or report, chosen by its type,
+//	              or incident when it has nothing the chosen type could
+//	              refer to
+//	Source        external_references of the objects it is related to
+//	Relation      relationship
+//
+// STIX identifiers are deterministic: domain objects and relationships use a
+// UUIDv5 of their document handle, cyber observables use the STIX 2.1
+// derivation from their value. Fields with no STIX equivalent are carried in
+// x_omnsight_* custom properties so a bundle produced by Export imports back
+// to the same documents. ACL fields are never exported. Export reads
+// timestamps with epoch.Default; Import writes Unix seconds.
+package stix
+
+import (
+	"strings"
+	"time"
+
+	"github.com/omnsight/omniscent-library/epoch"
+	"github.com/omnsight/omniscent-library/internal/uuid"
+)
+
+// SpecVersion is the STIX version produced and accepted.
+const SpecVersion = "2.1"
+
+// STIX object types used by the mapping.
+const (
+	TypeBundle       = "bundle"
+	TypeIdentity     = "identity"
+	TypeThreatActor  = "threat-actor"
+	TypeURL          = "url"
+	TypeDomainName   = "domain-name"
+	TypeObservedData = "observed-data"
+	TypeSighting     = "sighting"
+	TypeReport       = "report"
+	TypeIncident     = "incident"
+	TypeRelationship = "relationship"
+)
+
+// Identity classes used for Person and Organization.
+const (
+	IdentityIndividual   = "individual"
+	IdentityOrganization = "organization"
+)
+
+// Bundle is a STIX 2.1 bundle.
+type Bundle struct {
+	Type    string    `json:"type"`
+	ID      string    `json:"id"`
+	Objects []*Object `json:"objects"`
+}
+
+// ExternalReference is a STIX external-reference.
+type ExternalReference struct {
+	SourceName  string `json:"source_name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ExternalID  string `json:"external_id,omitempty"`
+}
+
+// Location is the x_omnsight_location custom property of events.
+type Location struct {
+	Latitude              float32 `json:"latitude"`
+	Longitude             float32 `json:"longitude"`
+	CountryCode           string  `json:"country_code,omitempty"`
+	AdministrativeArea    string  `json:"administrative_area,omitempty"`
+	SubAdministrativeArea string  `json:"sub_administrative_area,omitempty"`
+	Locality              string  `json:"locality,omitempty"`
+	SubLocality           string  `json:"sub_locality,omitempty"`
+	Address               string  `json:"address,omitempty"`
+	Postcode              string  `json:"postcode,omitempty"`
+}
+
+// Object holds the union of the STIX properties used by the mapping. Only
+// the properties relevant to Type are set.
+type Object struct {
+	Type               string              `json:"type"`
+	SpecVersion        string              `json:"spec_version"`
+	ID                 string              `json:"id"`
+	Created            string              `json:"created,omitempty"`
+	Modified           string              `json:"modified,omitempty"`
+	Name               string              `json:"name,omitempty"`
+	Description        string              `json:"description,omitempty"`
+	Labels             []string            `json:"labels,omitempty"`
+	Confidence         *int32              `json:"confidence,omitempty"`
+	ExternalReferences []ExternalReference `json:"external_references,omitempty"`
+
+	// identity and threat-actor
+	IdentityClass    string   `json:"identity_class,omitempty"`
+	Roles            []string `json:"roles,omitempty"`
+	Aliases          []string `json:"aliases,omitempty"`
+	ThreatActorTypes []string `json:"threat_actor_types,omitempty"`
+
+	// url and domain-name
+	Value string `json:"value,omitempty"`
+
+	// observed-data, sighting and report
+	FirstObserved  string   `json:"first_observed,omitempty"`
+	LastObserved   string   `json:"last_observed,omitempty"`
+	NumberObserved int      `json:"number_observed,omitempty"`
+	FirstSeen      string   `json:"first_seen,omitempty"`
+	LastSeen       string   `json:"last_seen,omitempty"`
+	SightingOfRef  string   `json:"sighting_of_ref,omitempty"`
+	Published      string   `json:"published,omitempty"`
+	ReportTypes    []string `json:"report_types,omitempty"`
+	ObjectRefs     []string `json:"object_refs,omitempty"`
+
+	// relationship
+	RelationshipType string `json:"relationship_type,omitempty"`
+	SourceRef        string `json:"source_ref,omitempty"`
+	TargetRef        string `json:"target_ref,omitempty"`
+
+	// Custom properties preserving model fields without a STIX equivalent.
+	XKey         string         `json:"x_omnsight_key,omitempty"`
+	XType        string         `json:"x_omnsight_type,omitempty"`
+	XTitle       string         `json:"x_omnsight_title,omitempty"`
+	XDescription string         `json:"x_omnsight_description,omitempty"`
+	XTags        []string       `json:"x_omnsight_tags,omitempty"`
+	XNationality string         `json:"x_omnsight_nationality,omitempty"`
+	XBirthDate   string         `json:"x_omnsight_birth_date,omitempty"`
+	XFoundedAt   string         `json:"x_omnsight_founded_at,omitempty"`
+	XLocation    *Location      `json:"x_omnsight_location,omitempty"`
+	XAttributes  map[string]any `json:"x_omnsight_attributes,omitempty"`
+}
+
+// namespace is the STIX 2.1 namespace for deterministic identifiers.
+var namespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+// newID returns "<typ>--<uuidv5(namespace, name)>".
+func newID(typ, name string) string {
+	return typ + "--" + uuid.V5(namespace, name)
+}
+
+// observableID derives the identifier of a url or domain-name object from
+// its value, as specified for SCO id contributing properties.
+func observableID(typ, value string) string {
+	v, _ := jsonString(value)
+	return newID(typ, `{"value":`+v+`}`)
+}
+
+// splitID returns the type and UUID parts of a STIX identifier.
+func splitID(id string) (typ, uuid string) {
+	typ, uuid, _ = strings.Cut(id, "--")
+	return typ, uuid
+}
+
+// timestampLayout is the STIX timestamp format with millisecond precision.
+const timestampLayout = "2006-01-02T15:04:05.000Z"
+
+// formatTime formats a timestamp field read with epoch.Default. STIX
+// requires the timestamps it is used for, so 0 is written as the epoch.
+func formatTime(v int64) string {
+	t := epoch.Default.Time(v)
+	if t.IsZero() {
+		t = time.Unix(0, 0).UTC()
+	}
+	return t.Format(timestampLayout)
+}
+
+// later returns whichever of the timestamp fields a and b is later,
+// comparing them as times so that values in different units order
+// correctly.
+func later(a, b int64) int64 {
+	if epoch.Default.Time(b).After(epoch.Default.Time(a)) {
+		return b
+	}
+	return a
+}
+
+// parseTime returns the Unix seconds of a STIX timestamp, or 0 if s is
+// empty or malformed.
+func parseTime(s string) int64 {
+	if s == "" {
+		return 0
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
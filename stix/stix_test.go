@@ -0,0 +1,207 @@
+package stix
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/entity"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+func fixture() ([]*model.Entity, []*model.Relation) {
+	entities := []*model.Entity{
+		entity.MustNew(&model.Person{Key: "p1", Name: "Ada", Tags: []string{"vip"}, BirthDate: 315532800, UpdatedAt: 1700000000}),
+		entity.MustNew(&model.Organization{Key: "o1", Name: "Acme", DiscoveredAt: 1600000000}),
+		entity.MustNew(&model.Organization{Key: "o2", Name: "APT", Type: "threat-actor", DiscoveredAt: 1600000000}),
+		entity.MustNew(&model.Website{Key: "w1", Url: "https://example.com/a", Title: "first", Description: "landing page"}),
+		entity.MustNew(&model.Website{Key: "w2", Url: "https://example.com/a", Title: "second"}),
+		entity.MustNew(&model.Website{Key: "w3", Url: "example.org"}),
+		entity.MustNew(&model.Source{Key: "s1", Name: "feed", Url: "https://feed.example"}),
+		entity.MustNew(&model.Event{Key: "e1", Type: "sighting", Title: "seen", HappenedAt: 1650000000}),
+		entity.MustNew(&model.Event{Key: "e2", Type: "report", HappenedAt: 1650000000}),
+		entity.MustNew(&model.Event{Key: "e3", Title: "traffic", HappenedAt: 1650000000}),
+		entity.MustNew(&model.Event{Key: "e4", Type: "report", Title: "summary", HappenedAt: 1650000000}),
+	}
+	relations := []*model.Relation{
+		{Key: "r1", From: "persons/p1", To: "organizations/o1", Label: "member_of", CreatedAt: 1600000000},
+		{Key: "r2", From: "organizations/o2", To: "events/e1", Label: "participated_in", CreatedAt: 1600000000},
+		{Key: "r3", From: "events/e3", To: "websites/w3", Label: "related_to", CreatedAt: 1600000000},
+		{Key: "r4", From: "organizations/o2", To: "websites/w2", Label: "owns_domain", CreatedAt: 1600000000},
+		{Key: "r5", From: "events/e4", To: "websites/w1", Label: "mentions", CreatedAt: 1600000000},
+		{From: "events/e1", To: "sources/s1", Label: "reported_by"},
+	}
+	return entities, relations
+}
+
+func TestExport(t *testing.T) {
+	b, err := Export(fixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	types := map[string]string{}
+	for _, obj := range b.Objects {
+		if ids[obj.ID] {
+			t.Errorf("duplicate id %s", obj.ID)
+		}
+		ids[obj.ID] = true
+		if obj.XKey != "" {
+			types[obj.XKey] = obj.Type
+		}
+		switch obj.Type {
+		case TypeURL, TypeDomainName:
+			if obj.Description != "" {
+				t.Errorf("%s has a description property", obj.ID)
+			}
+		case TypeObservedData, TypeReport:
+			if len(obj.ObjectRefs) == 0 {
+				t.Errorf("%s has no object_refs", obj.ID)
+			}
+		case TypeSighting:
+			if obj.SightingOfRef == "" {
+				t.Errorf("%s has no sighting_of_ref", obj.ID)
+			}
+		}
+	}
+	want := map[string]string{
+		"p1": TypeIdentity, "o2": TypeThreatActor, "w1": TypeURL, "w3": TypeDomainName,
+		"e1": TypeSighting, "e2": TypeIncident, "e3": TypeObservedData, "e4": TypeReport,
+	}
+	for key, typ := range want {
+		if types[key] != typ {
+			t.Errorf("%s exported as %q, want %q", key, types[key], typ)
+		}
+	}
+	if _, ok := types["w2"]; ok {
+		t.Error("website w2 sharing w1's URL was exported separately")
+	}
+	for _, obj := range b.Objects {
+		for _, ref := range append(obj.ObjectRefs, obj.SourceRef, obj.TargetRef, obj.SightingOfRef) {
+			if ref != "" && !ids[ref] {
+				t.Errorf("%s refers to missing %s", obj.ID, ref)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	b, err := Export(fixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Bundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	entities, relations, err := Import(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := map[string]entity.Tagged{}
+	for _, e := range entities {
+		h, err := arango.HandleOf(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs[h.String()] = entity.Unwrap(e)
+	}
+	if p, _ := docs["persons/p1"].(*model.Person); p.GetName() != "Ada" || p.GetBirthDate() != 315532800 || len(p.GetTags()) != 1 {
+		t.Errorf("person = %v", p)
+	}
+	if o, _ := docs["organizations/o2"].(*model.Organization); o.GetType() != "threat-actor" {
+		t.Errorf("threat actor = %v", o)
+	}
+	if w, _ := docs["websites/w1"].(*model.Website); w.GetDescription() != "landing page" || w.GetTitle() != "first" {
+		t.Errorf("website = %v", w)
+	}
+	if _, ok := docs["websites/w2"]; ok {
+		t.Error("merged website w2 was imported")
+	}
+	if e, _ := docs["events/e2"].(*model.Event); e.GetType() != "report" || e.GetTitle() != "" || e.GetHappenedAt() != 1650000000 {
+		t.Errorf("event without references = %v", e)
+	}
+	if e, _ := docs["events/e1"].(*model.Event); e.GetTitle() != "seen" || e.GetType() != "sighting" {
+		t.Errorf("sighting = %v", e)
+	}
+	if _, ok := docs["sources/s1"]; !ok {
+		t.Error("source s1 not imported")
+	}
+
+	byKey := map[string]*model.Relation{}
+	for _, r := range relations {
+		if docs[r.GetFrom()] == nil || docs[r.GetTo()] == nil {
+			t.Errorf("relation %s %s %s points outside the import", r.GetFrom(), r.GetLabel(), r.GetTo())
+		}
+		if r.GetKey() != "" {
+			byKey[r.GetKey()] = r
+		}
+	}
+	tests := []struct{ key, from, label, to string }{
+		{"r1", "persons/p1", "member_of", "organizations/o1"},
+		{"r2", "organizations/o2", "participated_in", "events/e1"},
+		{"r4", "organizations/o2", "owns_domain", "websites/w1"},
+	}
+	for _, tt := range tests {
+		r := byKey[tt.key]
+		if r.GetFrom() != tt.from || r.GetLabel() != tt.label || r.GetTo() != tt.to {
+			t.Errorf("relation %s = %s %s %s, want %s %s %s", tt.key, r.GetFrom(), r.GetLabel(), r.GetTo(), tt.from, tt.label, tt.to)
+		}
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	tests := []struct {
+		source *model.Source
+		want   string
+	}{
+		{&model.Source{Key: "s", Name: "feed", Title: "Feed", Url: "https://feed.example/x"}, "feed"},
+		{&model.Source{Key: "s", Title: "Feed", Url: "https://feed.example/x"}, "Feed"},
+		{&model.Source{Key: "s", Url: "https://feed.example/x"}, "feed.example"},
+		{&model.Source{Key: "s", Url: "feed.example/x"}, "s"},
+		{&model.Source{Id: "sources/s2"}, "s2"},
+	}
+	for _, tt := range tests {
+		entities := []*model.Entity{
+			entity.MustNew(&model.Person{Key: "p"}),
+			entity.MustNew(tt.source),
+		}
+		h, _ := arango.HandleOf(tt.source)
+		b, err := Export(entities, []*model.Relation{{From: "persons/p", To: h.String(), Label: "reported_by"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs := b.Objects[0].ExternalReferences
+		if len(refs) != 1 || refs[0].SourceName != tt.want {
+			t.Errorf("Export(%v) external_references = %+v, want source_name %q", tt.source, refs, tt.want)
+		}
+	}
+}
+
+func TestExportMilliseconds(t *testing.T) {
+	seconds, err := Export(fixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entities, relations := fixture()
+	for _, e := range entities {
+		epoch.Default.Normalize(e, epoch.Milliseconds)
+	}
+	for _, r := range relations {
+		epoch.Default.Normalize(r, epoch.Milliseconds)
+	}
+	millis, err := Export(entities, relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := json.Marshal(seconds)
+	b, _ := json.Marshal(millis)
+	if string(a) != string(b) {
+		t.Errorf("bundle from milliseconds differs:\n got %s\nwant %s", b, a)
+	}
+}
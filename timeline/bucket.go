@@ -0,0 +1,148 @@
+package timeline
+
+import (
+	"fmt"
+	"time"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// Interval is the width of a bucket.
+type Interval int
+
+const (
+	Hour Interval = iota
+	Day
+	// Week buckets start on Monday.
+	Week
+	Month
+)
+
+var intervalNames = [...]string{"hour", "day", "week", "month"}
+
+func (iv Interval) String() string {
+	if iv < 0 || int(iv) >= len(intervalNames) {
+		return fmt.Sprintf("Interval(%d)", int(iv))
+	}
+	return intervalNames[iv]
+}
+
+// ParseInterval returns the Interval named s, as produced by String.
+func ParseInterval(s string) (Interval, bool) {
+	for i, name := range intervalNames {
+		if name == s {
+			return Interval(i), true
+		}
+	}
+	return 0, false
+}
+
+// Truncate returns the start of the bucket holding t, in t's location.
+// Hours are cut from t itself rather than rebuilt from the wall clock, so
+// the hour repeated when daylight saving time ends stays two buckets.
+func (iv Interval) Truncate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch iv {
+	case Hour:
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case Week:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	case Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Next returns the start of the bucket after the one starting at start.
+func (iv Interval) Next(start time.Time) time.Time {
+	switch iv {
+	case Hour:
+		return start.Add(time.Hour)
+	case Week:
+		return start.AddDate(0, 0, 7)
+	case Month:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Bucket is the events of one interval, [Start, End).
+type Bucket struct {
+	Start, End time.Time
+	Events     []*model.Event
+}
+
+// Buckets groups the events into consecutive intervals from the first
+// event to the last, including empty intervals in between.
+func (t *Timeline) Buckets(iv Interval) []Bucket {
+	if len(t.events) == 0 {
+		return nil
+	}
+	first, last := t.Span()
+	var out []Bucket
+	i := 0
+	for start := iv.Truncate(first); !start.After(last); start = iv.Next(start) {
+		b := Bucket{Start: start, End: iv.Next(start)}
+		for i < len(t.events) && At(t.events[i]).Before(b.End) {
+			b.Events = append(b.Events, t.events[i])
+			i++
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// Gap is a quiet period between two consecutive events.
+type Gap struct {
+	After, Before *model.Event
+	Start, End    time.Time
+}
+
+// Duration returns the length of the gap.
+func (g Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// Gaps returns the periods of at least min without events.
+func (t *Timeline) Gaps(min time.Duration) []Gap {
+	var out []Gap
+	for i := 1; i < len(t.events); i++ {
+		a, b := At(t.events[i-1]).In(t.loc), At(t.events[i]).In(t.loc)
+		if b.Sub(a) >= min {
+			out = append(out, Gap{After: t.events[i-1], Before: t.events[i], Start: a, End: b})
+		}
+	}
+	return out
+}
+
+// Burst is a run of events in quick succession.
+type Burst struct {
+	Start, End time.Time
+	Events     []*model.Event
+}
+
+// Bursts returns the maximal runs of at least min events in which each
+// event follows the previous one within window.
+func (t *Timeline) Bursts(window time.Duration, min int) []Burst {
+	var out []Burst
+	flush := func(run []*model.Event) {
+		if len(run) >= min && len(run) > 0 {
+			out = append(out, Burst{
+				Start:  At(run[0]).In(t.loc),
+				End:    At(run[len(run)-1]).In(t.loc),
+				Events: run,
+			})
+		}
+	}
+	var run []*model.Event
+	for i, e := range t.events {
+		if i > 0 && At(e).Sub(At(t.events[i-1])) > window {
+			flush(run)
+			run = nil
+		}
+		run = append(run, e)
+	}
+	flush(run)
+	return out
+}
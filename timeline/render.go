@@ -0,0 +1,141 @@
+package timeline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"github.com/omnsight/omniscent-library/internal/errwriter"
+	"github.com/omnsight/omniscent-library/internal/floatconv"
+)
+
+// View is the JSON structure of a bucketed timeline for UIs.
+type View struct {
+	Interval string       `json:"interval"`
+	Start    time.Time    `json:"start"`
+	End      time.Time    `json:"end"`
+	Count    int          `json:"count"`
+	Buckets  []BucketView `json:"buckets"`
+}
+
+// BucketView is one interval of a View. Empty intervals are kept so a
+// histogram can be drawn directly.
+type BucketView struct {
+	Start  time.Time `json:"start"`
+	Label  string    `json:"label"`
+	Count  int       `json:"count"`
+	Events []Entry   `json:"events,omitempty"`
+}
+
+// Entry is the summary of one event.
+type Entry struct {
+	ID    string    `json:"id,omitempty"`
+	Time  time.Time `json:"time"`
+	Type  string    `json:"type,omitempty"`
+	Title string    `json:"title,omitempty"`
+	Place string    `json:"place,omitempty"`
+	Lat   *float64  `json:"lat,omitempty"`
+	Lon   *float64  `json:"lon,omitempty"`
+	Tags  []string  `json:"tags,omitempty"`
+}
+
+// View returns the timeline grouped by iv. Start and End bound the first
+// and last buckets.
+func (t *Timeline) View(iv Interval) *View {
+	v := &View{Interval: iv.String(), Count: len(t.events), Buckets: []BucketView{}}
+	for _, b := range t.Buckets(iv) {
+		bv := BucketView{Start: b.Start, Label: iv.Label(b.Start), Count: len(b.Events)}
+		for _, e := range b.Events {
+			bv.Events = append(bv.Events, t.entry(e))
+		}
+		v.Buckets = append(v.Buckets, bv)
+	}
+	if n := len(v.Buckets); n > 0 {
+		v.Start, v.End = v.Buckets[0].Start, iv.Next(v.Buckets[n-1].Start)
+	}
+	return v
+}
+
+func (t *Timeline) entry(e *model.Event) Entry {
+	en := Entry{
+		Time:  At(e).In(t.loc),
+		Type:  e.GetType(),
+		Title: e.GetTitle(),
+		Place: Place(e.GetLocation()),
+		Tags:  e.GetTags(),
+	}
+	if h, err := arango.HandleOf(e); err == nil {
+		en.ID = h.String()
+	}
+	if l := e.GetLocation(); l != nil {
+		lat, lon := floatconv.Widen(l.GetLatitude()), floatconv.Widen(l.GetLongitude())
+		en.Lat, en.Lon = &lat, &lon
+	}
+	return en
+}
+
+// Label returns the display name of the bucket starting at start:
+// "2006-01-02 15h", "2006-01-02", "2006-W01" or "2006-01".
+func (iv Interval) Label(start time.Time) string {
+	switch iv {
+	case Hour:
+		return start.Format("2006-01-02 15h")
+	case Week:
+		y, w := start.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", y, w)
+	case Month:
+		return start.Format("2006-01")
+	}
+	return start.Format("2006-01-02")
+}
+
+// Place returns the most specific locality of l with its country, such as
+// "Paris, FR", or "" when l has no names.
+func Place(l *model.LocationData) string {
+	var parts []string
+	for _, s := range []string{l.GetSubLocality(), l.GetLocality(), l.GetAdministrativeArea()} {
+		if s != "" {
+			parts = append(parts, s)
+			break
+		}
+	}
+	if c := l.GetCountryCode(); c != "" {
+		parts = append(parts, c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteText writes the non-empty buckets of t grouped by iv, one line per
+// event:
+//
+//	2023-11-14 (2)
+//	  09:30  [protest] March on city hall - Paris, FR
+//	  22:13  Power outage
+func (t *Timeline) WriteText(w io.Writer, iv Interval) error {
+	layout := "15:04"
+	if iv == Week || iv == Month {
+		layout = "01-02 15:04"
+	}
+	ew := errwriter.New(w)
+	for _, b := range t.Buckets(iv) {
+		if len(b.Events) == 0 {
+			continue
+		}
+		ew.Printf("%s (%d)\n", iv.Label(b.Start), len(b.Events))
+		for _, e := range b.Events {
+			ew.Printf("  %s  ", At(e).In(t.loc).Format(layout))
+			if e.GetType() != "" {
+				ew.Printf("[%s] ", e.GetType())
+			}
+			ew.Printf("%s", e.GetTitle())
+			if p := Place(e.GetLocation()); p != "" {
+				ew.Printf(" - %s", p)
+			}
+			ew.Printf("\n")
+		}
+	}
+	return ew.Err()
+}
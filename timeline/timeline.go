@@ -0,0 +1,131 @@
+// Package timeline builds chronological views of Events for time-window
+// queries such as "what happened in region X between T1 and T2".
+//
+// A Timeline holds dated events sorted by happened_at, read through
+// epoch.Default so legacy millisecond values sort correctly. It can be
+// sliced by time or any predicate, grouped into hour, day, week or month
+// buckets, scanned for gaps and bursts, merged with timelines from other
+// sources and rendered as compact text or a JSON structure for UIs.
+package timeline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/omnsight/omniscent-library/arango"
+	"github.com/omnsight/omniscent-library/epoch"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// Timeline is an immutable, chronologically sorted list of events.
+type Timeline struct {
+	events []*model.Event
+	loc    *time.Location
+}
+
+// New returns the timeline of events. Events without happened_at are left
+// out; events at the same instant keep their input order.
+func New(events []*model.Event) *Timeline {
+	t := &Timeline{loc: time.UTC}
+	for _, e := range events {
+		if e.GetHappenedAt() != 0 {
+			t.events = append(t.events, e)
+		}
+	}
+	sort.SliceStable(t.events, func(i, j int) bool {
+		return At(t.events[i]).Before(At(t.events[j]))
+	})
+	return t
+}
+
+// At returns the time e happened, or the zero time if it is not set.
+func At(e *model.Event) time.Time {
+	return epoch.HappenedAt(e)
+}
+
+// In returns a copy of t that buckets and renders times in loc. The
+// default is UTC.
+func (t *Timeline) In(loc *time.Location) *Timeline {
+	return &Timeline{events: t.events, loc: loc}
+}
+
+// Events returns the events of t in chronological order. The slice must
+// not be modified.
+func (t *Timeline) Events() []*model.Event {
+	return t.events
+}
+
+// Len returns the number of events.
+func (t *Timeline) Len() int {
+	return len(t.events)
+}
+
+// Span returns the times of the first and last events, or zero times when
+// t is empty.
+func (t *Timeline) Span() (first, last time.Time) {
+	if len(t.events) == 0 {
+		return time.Time{}, time.Time{}
+	}
+	return At(t.events[0]).In(t.loc), At(t.events[len(t.events)-1]).In(t.loc)
+}
+
+// Between returns the events that happened in [from, to). A zero bound is
+// open.
+func (t *Timeline) Between(from, to time.Time) *Timeline {
+	lo := 0
+	if !from.IsZero() {
+		lo = sort.Search(len(t.events), func(i int) bool { return !At(t.events[i]).Before(from) })
+	}
+	hi := len(t.events)
+	if !to.IsZero() {
+		hi = sort.Search(len(t.events), func(i int) bool { return !At(t.events[i]).Before(to) })
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return &Timeline{events: t.events[lo:hi:hi], loc: t.loc}
+}
+
+// Filter returns the events for which keep returns true, such as those
+// inside a region.
+func (t *Timeline) Filter(keep func(*model.Event) bool) *Timeline {
+	out := &Timeline{loc: t.loc}
+	for _, e := range t.events {
+		if keep(e) {
+			out.events = append(out.events, e)
+		}
+	}
+	return out
+}
+
+// Merge combines timelines, for example from several sources, into one.
+// An event present in more than one of them, identified by its document
+// handle, appears once: the copy with the latest updated_at wins. Events
+// without a key are never merged. The result uses the location of the
+// first timeline.
+func Merge(timelines ...*Timeline) *Timeline {
+	var all []*model.Event
+	index := map[string]int{}
+	for _, t := range timelines {
+		for _, e := range t.events {
+			h, err := arango.HandleOf(e)
+			if err != nil {
+				all = append(all, e)
+				continue
+			}
+			i, seen := index[h.String()]
+			switch {
+			case !seen:
+				index[h.String()] = len(all)
+				all = append(all, e)
+			case epoch.UpdatedAt(e).After(epoch.UpdatedAt(all[i])):
+				all[i] = e
+			}
+		}
+	}
+	out := New(all)
+	if len(timelines) > 0 {
+		out.loc = timelines[0].loc
+	}
+	return out
+}
@@ -0,0 +1,328 @@
+package timeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+)
+
+// at returns the Unix seconds of an RFC 3339 time.
+func at(s string) int64 {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t.Unix()
+}
+
+func keys(events []*model.Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.GetKey()
+	}
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNew(t *testing.T) {
+	tl := New([]*model.Event{
+		{Key: "c", HappenedAt: at("2023-11-14T12:00:00Z")},
+		{Key: "undated"},
+		{Key: "a", HappenedAt: at("2023-11-14T09:00:00Z") * 1000},
+		{Key: "b1", HappenedAt: at("2023-11-14T10:00:00Z")},
+		{Key: "b2", HappenedAt: at("2023-11-14T10:00:00Z")},
+	})
+	if got, want := keys(tl.Events()), []string{"a", "b1", "b2", "c"}; !equal(got, want) {
+		t.Errorf("New order = %v, want %v", got, want)
+	}
+	first, last := tl.Span()
+	if !first.Equal(time.Date(2023, 11, 14, 9, 0, 0, 0, time.UTC)) || !last.Equal(time.Date(2023, 11, 14, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Span = %v, %v", first, last)
+	}
+	if first, last := New(nil).Span(); !first.IsZero() || !last.IsZero() {
+		t.Errorf("empty Span = %v, %v", first, last)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tl := New([]*model.Event{
+		{Key: "a", HappenedAt: at("2023-01-01T00:00:00Z")},
+		{Key: "b", HappenedAt: at("2023-02-01T00:00:00Z")},
+		{Key: "c", HappenedAt: at("2023-03-01T00:00:00Z")},
+	})
+	date := func(m time.Month) time.Time { return time.Date(2023, m, 1, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		from, to time.Time
+		want     []string
+	}{
+		{date(1), date(3), []string{"a", "b"}},
+		{date(2), time.Time{}, []string{"b", "c"}},
+		{time.Time{}, date(2), []string{"a"}},
+		{time.Time{}, time.Time{}, []string{"a", "b", "c"}},
+		{date(3), date(1), []string{}},
+	}
+	for _, tt := range tests {
+		if got := keys(tl.Between(tt.from, tt.to).Events()); !equal(got, tt.want) {
+			t.Errorf("Between(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+	got := tl.Filter(func(e *model.Event) bool { return e.GetKey() != "b" })
+	if !equal(keys(got.Events()), []string{"a", "c"}) {
+		t.Errorf("Filter = %v", keys(got.Events()))
+	}
+}
+
+func TestBuckets(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		loc    *time.Location
+		iv     Interval
+		events []string
+		starts []string
+		counts []int
+		labels []string
+	}{
+		{
+			"day with an empty day", time.UTC, Day,
+			[]string{"2023-11-14T09:00:00Z", "2023-11-14T23:59:59Z", "2023-11-16T00:00:00Z"},
+			[]string{"2023-11-14T00:00:00Z", "2023-11-15T00:00:00Z", "2023-11-16T00:00:00Z"},
+			[]int{2, 0, 1},
+			[]string{"2023-11-14", "2023-11-15", "2023-11-16"},
+		},
+		{
+			"week starts on Monday", time.UTC, Week,
+			[]string{"2023-11-14T09:00:00Z", "2023-11-19T23:00:00Z", "2023-11-20T00:00:00Z"},
+			[]string{"2023-11-13T00:00:00Z", "2023-11-20T00:00:00Z"},
+			[]int{2, 1},
+			[]string{"2023-W46", "2023-W47"},
+		},
+		{
+			"week across the year", time.UTC, Week,
+			[]string{"2021-01-01T12:00:00Z"},
+			[]string{"2020-12-28T00:00:00Z"},
+			[]int{1},
+			[]string{"2020-W53"},
+		},
+		{
+			"month", time.UTC, Month,
+			[]string{"2024-01-31T23:00:00Z", "2024-03-01T00:00:00Z"},
+			[]string{"2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z", "2024-03-01T00:00:00Z"},
+			[]int{1, 0, 1},
+			[]string{"2024-01", "2024-02", "2024-03"},
+		},
+		{
+			"day across spring DST", berlin, Day,
+			[]string{"2023-03-25T23:30:00Z", "2023-03-26T22:30:00Z"},
+			[]string{"2023-03-26T00:00:00+01:00", "2023-03-27T00:00:00+02:00"},
+			[]int{1, 1},
+			[]string{"2023-03-26", "2023-03-27"},
+		},
+		{
+			"hour across autumn DST", berlin, Hour,
+			[]string{"2023-10-29T00:10:00Z", "2023-10-29T01:10:00Z"},
+			[]string{"2023-10-29T02:00:00+02:00", "2023-10-29T02:00:00+01:00"},
+			[]int{1, 1},
+			[]string{"2023-10-29 02h", "2023-10-29 02h"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []*model.Event
+			for _, s := range tt.events {
+				events = append(events, &model.Event{HappenedAt: at(s)})
+			}
+			buckets := New(events).In(tt.loc).Buckets(tt.iv)
+			if len(buckets) != len(tt.starts) {
+				t.Fatalf("%d buckets, want %d", len(buckets), len(tt.starts))
+			}
+			for i, b := range buckets {
+				start, _ := time.Parse(time.RFC3339, tt.starts[i])
+				if !b.Start.Equal(start) || len(b.Events) != tt.counts[i] {
+					t.Errorf("bucket %d = %v with %d events, want %v with %d", i, b.Start, len(b.Events), start, tt.counts[i])
+				}
+				if !b.End.Equal(tt.iv.Next(b.Start)) {
+					t.Errorf("bucket %d ends at %v, want %v", i, b.End, tt.iv.Next(b.Start))
+				}
+				if l := tt.iv.Label(b.Start); l != tt.labels[i] {
+					t.Errorf("bucket %d label = %q, want %q", i, l, tt.labels[i])
+				}
+			}
+		})
+	}
+	if b := New(nil).Buckets(Day); b != nil {
+		t.Errorf("empty timeline Buckets = %v", b)
+	}
+}
+
+func TestDSTDayLength(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := Day.Truncate(time.Date(2023, 3, 26, 12, 0, 0, 0, berlin))
+	if d := Day.Next(start).Sub(start); d != 23*time.Hour {
+		t.Errorf("2023-03-26 in Berlin lasts %v, want 23h", d)
+	}
+}
+
+func TestGapsAndBursts(t *testing.T) {
+	tl := New([]*model.Event{
+		{Key: "a", HappenedAt: at("2023-11-14T09:00:00Z")},
+		{Key: "b", HappenedAt: at("2023-11-14T09:05:00Z")},
+		{Key: "c", HappenedAt: at("2023-11-14T09:09:00Z")},
+		{Key: "d", HappenedAt: at("2023-11-14T12:09:00Z")},
+		{Key: "e", HappenedAt: at("2023-11-14T12:20:00Z")},
+	})
+	gaps := tl.Gaps(time.Hour)
+	if len(gaps) != 1 || gaps[0].After.GetKey() != "c" || gaps[0].Before.GetKey() != "d" || gaps[0].Duration() != 3*time.Hour {
+		t.Errorf("Gaps(1h) = %+v", gaps)
+	}
+	if n := len(tl.Gaps(11 * time.Minute)); n != 2 {
+		t.Errorf("Gaps(11m) found %d gaps, want 2 as the bound is inclusive", n)
+	}
+
+	tests := []struct {
+		window time.Duration
+		min    int
+		want   [][]string
+	}{
+		{5 * time.Minute, 2, [][]string{{"a", "b", "c"}}},
+		{4 * time.Minute, 2, [][]string{{"b", "c"}}},
+		{3 * time.Minute, 2, nil},
+		{11 * time.Minute, 2, [][]string{{"a", "b", "c"}, {"d", "e"}}},
+		{11 * time.Minute, 3, [][]string{{"a", "b", "c"}}},
+		{4 * time.Hour, 1, [][]string{{"a", "b", "c", "d", "e"}}},
+	}
+	for _, tt := range tests {
+		bursts := tl.Bursts(tt.window, tt.min)
+		if len(bursts) != len(tt.want) {
+			t.Errorf("Bursts(%v, %d) = %d bursts, want %d", tt.window, tt.min, len(bursts), len(tt.want))
+			continue
+		}
+		for i, b := range bursts {
+			if got := keys(b.Events); !equal(got, tt.want[i]) {
+				t.Errorf("Bursts(%v, %d)[%d] = %v, want %v", tt.window, tt.min, i, got, tt.want[i])
+			}
+			if !b.Start.Equal(At(b.Events[0])) || !b.End.Equal(At(b.Events[len(b.Events)-1])) {
+				t.Errorf("Bursts(%v, %d)[%d] spans %v-%v", tt.window, tt.min, i, b.Start, b.End)
+			}
+		}
+	}
+	if b := New(nil).Bursts(time.Hour, 0); b != nil {
+		t.Errorf("empty timeline Bursts = %v", b)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := New([]*model.Event{
+		{Key: "e1", Title: "old", HappenedAt: at("2023-11-14T09:00:00Z"), UpdatedAt: at("2023-11-14T10:00:00Z")},
+		{Title: "keyless", HappenedAt: at("2023-11-14T11:00:00Z")},
+	}).In(berlin)
+	b := New([]*model.Event{
+		{Key: "e1", Title: "new", HappenedAt: at("2023-11-14T09:30:00Z"), UpdatedAt: at("2023-11-14T12:00:00Z") * 1000},
+		{Key: "e2", Title: "other", HappenedAt: at("2023-11-14T08:00:00Z")},
+		{Title: "keyless", HappenedAt: at("2023-11-14T11:00:00Z")},
+	})
+	m := Merge(a, b)
+	var titles []string
+	for _, e := range m.Events() {
+		titles = append(titles, e.GetTitle())
+	}
+	if want := []string{"other", "new", "keyless", "keyless"}; !equal(titles, want) {
+		t.Errorf("Merge = %v, want %v", titles, want)
+	}
+	if first, _ := m.Span(); first.Location() != berlin {
+		t.Errorf("Merge location = %v, want the first timeline's", first.Location())
+	}
+	if Merge().Len() != 0 {
+		t.Error("Merge() is not empty")
+	}
+}
+
+func TestViewAndText(t *testing.T) {
+	paris := &model.LocationData{Latitude: 48.8566, Longitude: 2.3522, Locality: "Paris", CountryCode: "FR"}
+	tl := New([]*model.Event{
+		{Key: "e1", Type: "protest", Title: "March on city hall", HappenedAt: at("2023-11-14T09:30:00Z"), Location: paris},
+		{Key: "e2", Title: "Power outage", HappenedAt: at("2023-11-14T22:13:00Z")},
+		{Title: "Aftermath", HappenedAt: at("2023-11-16T07:00:00Z"), Tags: []string{"follow-up"}},
+	})
+
+	var buf bytes.Buffer
+	if err := tl.WriteText(&buf, Day); err != nil {
+		t.Fatal(err)
+	}
+	want := "2023-11-14 (2)\n" +
+		"  09:30  [protest] March on city hall - Paris, FR\n" +
+		"  22:13  Power outage\n" +
+		"2023-11-16 (1)\n" +
+		"  07:00  Aftermath\n"
+	if buf.String() != want {
+		t.Errorf("WriteText(Day) =\n%s\nwant\n%s", buf.String(), want)
+	}
+	buf.Reset()
+	if err := tl.WriteText(&buf, Month); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2023-11 (3)\n  11-14 09:30  [protest] March on city hall - Paris, FR\n  11-14 22:13  Power outage\n  11-16 07:00  Aftermath\n"; buf.String() != want {
+		t.Errorf("WriteText(Month) =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	v := tl.View(Day)
+	if v.Interval != "day" || v.Count != 3 || len(v.Buckets) != 3 {
+		t.Fatalf("View = %+v", v)
+	}
+	if !v.Start.Equal(time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC)) || !v.End.Equal(time.Date(2023, 11, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("View spans %v-%v", v.Start, v.End)
+	}
+	if b := v.Buckets[1]; b.Label != "2023-11-15" || b.Count != 0 || b.Events != nil {
+		t.Errorf("empty bucket = %+v", b)
+	}
+	e := v.Buckets[0].Events[0]
+	if e.ID != "events/e1" || e.Place != "Paris, FR" || e.Lat == nil || *e.Lat != 48.8566 || *e.Lon != 2.3522 {
+		t.Errorf("entry = %+v", e)
+	}
+	b, err := json.Marshal(New(nil).View(Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"interval":"hour","start":"0001-01-01T00:00:00Z","end":"0001-01-01T00:00:00Z","count":0,"buckets":[]}`; string(b) != want {
+		t.Errorf("empty View = %s, want %s", b, want)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for _, iv := range []Interval{Hour, Day, Week, Month} {
+		if got, ok := ParseInterval(iv.String()); !ok || got != iv {
+			t.Errorf("ParseInterval(%q) = %v, %v", iv.String(), got, ok)
+		}
+	}
+	if _, ok := ParseInterval("year"); ok {
+		t.Error(`ParseInterval("year") succeeded`)
+	}
+	if s := Interval(9).String(); s != "Interval(9)" {
+		t.Errorf("Interval(9).String() = %q", s)
+	}
+}
@@ -0,0 +1,72 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError reports a single constraint violation. Field is the dotted path
+// of the offending field using proto field names, e.g. "location.latitude".
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// Errors is the list of violations found while validating one message. A
+// validator returns a non-nil Errors only when it contains at least one entry.
+type Errors []*FieldError
+
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Fields returns the paths of all violating fields in report order.
+func (es Errors) Fields() []string {
+	fields := make([]string, len(es))
+	for i, e := range es {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+// report collects violations under a common path prefix. Nested reports
+// share the error list of their parent.
+type report struct {
+	prefix string
+	errs   *Errors
+}
+
+func (r *report) path(field string) string {
+	if r.prefix == "" {
+		return field
+	}
+	return r.prefix + "." + field
+}
+
+func (r *report) addf(field, format string, args ...any) {
+	*r.errs = append(*r.errs, &FieldError{Field: r.path(field), Reason: fmt.Sprintf(format, args...)})
+}
+
+// nested returns a report that records violations below field.
+func (r *report) nested(field string) *report {
+	return &report{prefix: r.path(field), errs: r.errs}
+}
+
+func newReport() *report {
+	return &report{errs: new(Errors)}
+}
+
+func (r *report) err() error {
+	if len(*r.errs) == 0 {
+		return nil
+	}
+	return *r.errs
+}
@@ -0,0 +1,170 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sync"
+
+	"github.com/omnsight/omniscent-library/epoch"
+	"github.com/omnsight/omniscent-library/fuzzydate"
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldRule pairs a field with the (model.v1.rules) option declared on it.
+type fieldRule struct {
+	fd        protoreflect.FieldDescriptor
+	rules     *model.FieldRules
+	notBefore protoreflect.FieldDescriptor
+}
+
+// ruleCache maps a message full name to its []fieldRule.
+var ruleCache sync.Map
+
+// rulesFor returns the annotated fields of md, reading the descriptor
+// options only once per message type.
+func rulesFor(md protoreflect.MessageDescriptor) []fieldRule {
+	if v, ok := ruleCache.Load(md.FullName()); ok {
+		return v.([]fieldRule)
+	}
+	var out []fieldRule
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		opts := fd.Options()
+		if opts == nil || !proto.HasExtension(opts, model.E_Rules) {
+			continue
+		}
+		fr := fieldRule{fd: fd, rules: proto.GetExtension(opts, model.E_Rules).(*model.FieldRules)}
+		if name := fr.rules.GetNotBefore(); name != "" {
+			fr.notBefore = fields.ByName(protoreflect.Name(name))
+		}
+		out = append(out, fr)
+	}
+	v, _ := ruleCache.LoadOrStore(md.FullName(), out)
+	return v.([]fieldRule)
+}
+
+// walk applies the declared rules of m and recurses into every populated
+// message field, including the active case of a oneof.
+func walk(r *report, m protoreflect.Message) {
+	for _, fr := range rulesFor(m.Descriptor()) {
+		applyRule(r, m, fr)
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		name := string(fd.Name())
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walk(r.nested(fmt.Sprintf("%s[%d]", name, i)), list.Get(i).Message())
+			}
+			return true
+		}
+		walk(r.nested(name), v.Message())
+		if check := checks[fd.Message().FullName()]; check != nil {
+			if err := check(v.Message().Interface()); err != nil {
+				r.addf(name, "%v", err)
+			}
+		}
+		return true
+	})
+}
+
+// checks holds whole-message constraints that field rules cannot express.
+var checks = map[protoreflect.FullName]func(proto.Message) error{
+	"model.v1.FuzzyDate": func(m proto.Message) error { return fuzzydate.Validate(m.(*model.FuzzyDate)) },
+}
+
+func applyRule(r *report, m protoreflect.Message, fr fieldRule) {
+	name := string(fr.fd.Name())
+	v := m.Get(fr.fd)
+	if fr.fd.IsList() {
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			applyValue(r, fmt.Sprintf("%s[%d]", name, i), fr, list.Get(i))
+		}
+		return
+	}
+	applyValue(r, name, fr, v)
+	if fr.notBefore != nil {
+		if n, ok := number(fr.fd, v); ok && n != 0 {
+			if floor, ok := number(fr.notBefore, m.Get(fr.notBefore)); ok && n < floor {
+				r.addf(name, "%v is before %s %v", n, fr.notBefore.Name(), floor)
+			}
+		}
+	}
+}
+
+func applyValue(r *report, field string, fr fieldRule, v protoreflect.Value) {
+	rules := fr.rules
+	if rules.GetTimestamp() {
+		checkTimestamp(r, field, fr.fd, v)
+	}
+	if n, ok := number(fr.fd, v); ok {
+		switch {
+		case math.IsNaN(n):
+			r.addf(field, "value is NaN")
+		case rules.Min != nil && rules.Max != nil && (n < rules.GetMin() || n > rules.GetMax()):
+			r.addf(field, "%v is outside [%v, %v]", n, rules.GetMin(), rules.GetMax())
+		case rules.Min != nil && n < rules.GetMin():
+			r.addf(field, "%v is less than %v", n, rules.GetMin())
+		case rules.Max != nil && n > rules.GetMax():
+			r.addf(field, "%v is greater than %v", n, rules.GetMax())
+		}
+		return
+	}
+	if fr.fd.Kind() != protoreflect.StringKind || v.String() == "" {
+		return
+	}
+	s := v.String()
+	if rules.GetCountryCode() && !IsCountryCode(s) {
+		r.addf(field, "%q is not an ISO 3166-1 alpha-2 code", s)
+	}
+	if rules.GetAbsoluteUrl() {
+		u, err := url.Parse(s)
+		switch {
+		case err != nil:
+			r.addf(field, "unparseable URL: %v", err)
+		case !u.IsAbs() || u.Host == "":
+			r.addf(field, "%q is not an absolute URL", s)
+		}
+	}
+}
+
+// checkTimestamp reports an integer timestamp that looks like it is not in
+// seconds, and the rule itself when declared on a non-integer field.
+func checkTimestamp(r *report, field string, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+	default:
+		r.addf(field, "timestamp rule on a %s field", fd.Kind())
+		return
+	}
+	if n := v.Int(); n != 0 {
+		if u, ok := epoch.Detect(n); ok && u != epoch.Seconds {
+			r.addf(field, "%d looks like a timestamp in %s, want seconds", n, u)
+		}
+	}
+}
+
+// number converts a scalar numeric value to float64. The boolean is false
+// for non-numeric kinds.
+func number(fd protoreflect.FieldDescriptor, v protoreflect.Value) (float64, bool) {
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return float64(v.Int()), true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(v.Uint()), true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package validate
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fields returns the violating fields reported by err.
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var es Errors
+	if !errors.As(err, &es) {
+		t.Fatalf("error %v is %T, want Errors", err, err)
+	}
+	return es.Fields()
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want []string
+	}{
+		{"valid relation", &model.Relation{Confidence: 50, CreatedAt: 100, UpdatedAt: 200}, nil},
+		{"min", &model.Relation{Confidence: -1}, []string{"confidence"}},
+		{"max", &model.Source{Reliability: 101}, []string{"reliability"}},
+		{"min and max bounds", &model.Source{Reliability: 100}, nil},
+		{"float min", &model.LocationData{Latitude: -90.5}, []string{"latitude"}},
+		{"NaN", &model.LocationData{Latitude: float32(math.NaN())}, []string{"latitude"}},
+		{"absolute url", &model.Source{Url: "https://example.com/a"}, nil},
+		{"relative url", &model.Website{Url: "example.com"}, []string{"url"}},
+		{"url without host", &model.Website{Url: "mailto:a@example.com"}, []string{"url"}},
+		{"country code", &model.LocationData{CountryCode: "FR"}, nil},
+		{"lower-case country code", &model.LocationData{CountryCode: "fr"}, []string{"country_code"}},
+		{"unassigned country code", &model.LocationData{CountryCode: "XX"}, []string{"country_code"}},
+		{"not before", &model.Relation{CreatedAt: 200, UpdatedAt: 100}, []string{"updated_at"}},
+		{"not before unset", &model.Relation{CreatedAt: 200}, nil},
+		{"event updated before it happened", &model.Event{HappenedAt: 200, UpdatedAt: 100}, []string{"updated_at"}},
+		{
+			"nested",
+			&model.Event{Location: &model.LocationData{Longitude: 181, CountryCode: "ZZ"}},
+			[]string{"location.longitude", "location.country_code"},
+		},
+		{
+			"oneof case",
+			&model.Entity{Entity: &model.Entity_Source{Source: &model.Source{Reliability: -5}}},
+			[]string{"source.reliability"},
+		},
+	}
+	for _, tt := range tests {
+		if got := fields(t, Message(tt.msg)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Message(%v) fields = %v, want %v", tt.name, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestEntity(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *model.Entity
+		want []string
+	}{
+		{"empty", &model.Entity{}, []string{"entity"}},
+		{"valid", &model.Entity{Entity: &model.Entity_Person{Person: &model.Person{Name: "Ada"}}}, nil},
+		{
+			"invalid",
+			&model.Entity{Entity: &model.Entity_Website{Website: &model.Website{Url: "/about"}}},
+			[]string{"website.url"},
+		},
+	}
+	for _, tt := range tests {
+		if got := fields(t, Entity(tt.msg)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Entity(%v) fields = %v, want %v", tt.name, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestTimestampRule(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want []string
+	}{
+		{"seconds", &model.Event{HappenedAt: 1700000000}, nil},
+		{"milliseconds", &model.Event{HappenedAt: 1700000000000}, []string{"happened_at"}},
+		{"unset", &model.Source{}, nil},
+	}
+	for _, tt := range tests {
+		if got := fields(t, Message(tt.msg)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Message(%v) fields = %v, want %v", tt.name, tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestTimestampRuleKind(t *testing.T) {
+	md := (&model.LocationData{}).ProtoReflect().Descriptor()
+	for _, name := range []string{"latitude", "address"} {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		m := &model.LocationData{Latitude: 1.5, Address: "1 Main St"}
+		r := newReport()
+		applyValue(r, name, fieldRule{fd: fd, rules: &model.FieldRules{Timestamp: true}}, m.ProtoReflect().Get(fd))
+		if got := fields(t, r.err()); !reflect.DeepEqual(got, []string{name}) {
+			t.Errorf("timestamp rule on %s: fields = %v, want [%s]", name, got, name)
+		}
+	}
+}
+
+func TestFuzzyDateCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  proto.Message
+		want []string
+	}{
+		{"pre-epoch founding", &model.Organization{FoundedAt: -3786825600}, nil},
+		{"pre-epoch website", &model.Website{FoundedAt: -86400}, nil},
+		{
+			"valid",
+			&model.Person{FuzzyBirthDate: &model.FuzzyDate{Precision: model.FuzzyDate_PRECISION_DAY, Year: 1815, Month: 12, Day: 10}},
+			nil,
+		},
+		{
+			"day out of month",
+			&model.Person{FuzzyBirthDate: &model.FuzzyDate{Precision: model.FuzzyDate_PRECISION_DAY, Year: 1815, Month: 2, Day: 30}},
+			[]string{"fuzzy_birth_date"},
+		},
+		{
+			"field rule",
+			&model.Organization{FuzzyFoundedAt: &model.FuzzyDate{Precision: model.FuzzyDate_PRECISION_MONTH, Year: 1900, Month: 13}},
+			[]string{"fuzzy_founded_at.month", "fuzzy_founded_at"},
+		},
+	}
+	for _, tt := range tests {
+		if got := fields(t, Message(tt.msg)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Message(%v) fields = %v, want %v", tt.name, tt.msg, got, tt.want)
+		}
+	}
+}
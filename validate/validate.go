@@ -0,0 +1,33 @@
+// Package validate checks OSINT model messages for semantic consistency.
+//
+// Constraints are declared in the .proto files with the (model.v1.rules)
+// field option and read at runtime through protoreflect, so annotating a new
+// field is enough to have it validated. Every validator returns nil for a
+// valid message or an Errors value listing each violation with the proto
+// path of the field it concerns.
+package validate
+
+import (
+	model "github.com/omnsight/omniscent-library/gen/model/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Message validates any message against the rules declared on its fields,
+// descending into nested messages.
+func Message(m proto.Message) error {
+	r := newReport()
+	walk(r, m.ProtoReflect())
+	return r.err()
+}
+
+// Entity validates whichever message is set in the oneof. Field paths are
+// prefixed with the oneof case, e.g. "person.name". An Entity with no case
+// set is reported as an error on "entity".
+func Entity(m *model.Entity) error {
+	if m.GetEntity() == nil {
+		r := newReport()
+		r.addf("entity", "no entity set")
+		return r.err()
+	}
+	return Message(m)
+}